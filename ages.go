@@ -0,0 +1,63 @@
+package cacher
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// defaultAgeBuckets are the AgeHistogram buckets used if none are given.
+var defaultAgeBuckets = []time.Duration{time.Second, 10 * time.Second, time.Minute, 10 * time.Minute, time.Hour}
+
+// Histogram counts durations by bucket. Counts[i] is the number of durations
+// up to Buckets[i] and above the previous bucket, and the last count, one more
+// than there are buckets, is the number of durations above all buckets.
+type Histogram struct {
+	Buckets []time.Duration
+	Counts  []int
+}
+
+// add counts a duration.
+func (h Histogram) add(d time.Duration) {
+	h.Counts[sort.Search(len(h.Buckets), func(i int) bool { return d <= h.Buckets[i] })]++
+}
+
+// AgeStats describes how long unexpired items have been cached and how long they have left.
+type AgeStats struct {
+	Age       Histogram // Time since items were set
+	Remaining Histogram // Time until items expire, for items that expire
+	NoExpiry  int       // Items that never expire
+}
+
+// AgeHistogram returns histograms of the ages and remaining TTLs of unexpired items,
+// with the given bucket upper bounds in any order, or 1s, 10s, 1m, 10m and 1h if none are given.
+// If most items expire long before they get old, the TTLs may be too short; if many
+// expired items wait for the cleaner, ClearingInterval may be too long.
+func (c *Cacher) AgeHistogram(buckets ...time.Duration) AgeStats {
+	if len(buckets) == 0 {
+		buckets = defaultAgeBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	stats := AgeStats{
+		Age:       Histogram{Buckets: buckets, Counts: make([]int, len(buckets)+1)},
+		Remaining: Histogram{Buckets: buckets, Counts: make([]int, len(buckets)+1)},
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	for _, item := range c.cache {
+		if c.checkExpiration(item) != nil {
+			continue
+		}
+		stats.Age.add(now.Sub(item.createdAt))
+		if at := c.expiresAt(item); at.IsZero() {
+			stats.NoExpiry++
+		} else {
+			stats.Remaining.add(at.Sub(now))
+		}
+	}
+	return stats
+}
@@ -0,0 +1,36 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacher_AgeHistogram(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("forever", "v", 0)
+	cache.Set("short", "v", 30*time.Second)
+	cache.Set("long", "v", 2*time.Hour)
+	cache.Set("expired", "v", time.Nanosecond)
+
+	// состарим один элемент на 20 минут
+	cache.mu.Lock()
+	item := cache.cache["long"]
+	item.createdAt = item.createdAt.Add(-20 * time.Minute)
+	cache.cache["long"] = item
+	cache.mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	stats := cache.AgeHistogram(time.Hour, time.Minute)
+	assert.Equal(t, []time.Duration{time.Minute, time.Hour}, stats.Age.Buckets)
+	assert.Equal(t, []int{2, 1, 0}, stats.Age.Counts)
+	assert.Equal(t, []int{1, 0, 1}, stats.Remaining.Counts)
+	assert.Equal(t, 1, stats.NoExpiry)
+
+	stats = cache.AgeHistogram()
+	assert.Equal(t, defaultAgeBuckets, stats.Age.Buckets)
+	assert.Len(t, stats.Remaining.Counts, len(defaultAgeBuckets)+1)
+}
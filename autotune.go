@@ -0,0 +1,88 @@
+package cacher
+
+import (
+	"runtime"
+	"time"
+)
+
+var (
+	defaultTargetHitRatio = 0.9
+
+	// autoTuneStep is the share of the capacity added or removed per tuning run.
+	autoTuneStep = 0.1
+)
+
+// tuner holds the auto-tuning bounds and the counters seen by the last run.
+type tuner struct {
+	min, max     int
+	target       float64
+	maxHeap      uint64
+	hits, misses uint64
+}
+
+// startAutoTune adjusts the capacity every interval until the cache is closed.
+func (c *Cacher) startAutoTune(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var ms runtime.MemStats
+			runtime.ReadMemStats(&ms)
+
+			c.mu.Lock()
+			c.autoTune(ms.HeapAlloc)
+			c.mu.Unlock()
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// autoTune grows or shrinks the capacity by autoTuneStep based on the hit ratio
+// since the last run and the heap size, staying within MinCapacity and MaxCapacity.
+// Heap above MaxHeapBytes shrinks the cache and evicts the excess items. A hit ratio
+// below the target grows a full cache; a ratio well above it (halfway to 1) shrinks it.
+func (c *Cacher) autoTune(heap uint64) {
+	hits := c.counters.hits - c.tuner.hits
+	misses := c.counters.misses - c.tuner.misses
+	c.tuner.hits, c.tuner.misses = c.counters.hits, c.counters.misses
+	if c.capacity == 0 {
+		return
+	}
+
+	step := max(int(float64(c.capacity)*autoTuneStep), 1)
+	ratio := Metrics{Hits: hits, Misses: misses}.HitRatio()
+	capacity := c.capacity
+	switch {
+	case c.tuner.maxHeap > 0 && heap > c.tuner.maxHeap:
+		capacity -= step
+	case hits+misses == 0:
+		return
+	case ratio < c.tuner.target && len(c.cache) >= c.capacity:
+		capacity += step
+	case ratio > c.tuner.target+(1-c.tuner.target)/2:
+		capacity -= step
+	}
+
+	capacity = max(capacity, c.tuner.min)
+	if c.tuner.max > 0 {
+		capacity = min(capacity, c.tuner.max)
+	}
+	if capacity == c.capacity {
+		return
+	}
+
+	c.logger.Info("cache capacity tuned",
+		"from", c.capacity, "to", capacity, "hit_ratio", ratio, "heap", heap)
+	c.capacity = capacity
+	for len(c.cache) > c.capacity {
+		key, ok := c.victim()
+		if !ok {
+			break
+		}
+		c.evictKey(key)
+	}
+	c.checkWatermarks()
+}
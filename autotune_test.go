@@ -0,0 +1,74 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacher_AutoTune(t *testing.T) {
+	cache := New(Config{
+		Capacity:         100,
+		ClearingInterval: -1,
+		AutoTuneInterval: time.Hour, // запускаем вручную
+		MinCapacity:      50,
+		MaxCapacity:      115,
+	})
+	defer cache.Close()
+
+	tune := func(heap uint64) int {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		cache.autoTune(heap)
+		return cache.capacity
+	}
+
+	// кэш заполнен, попаданий мало — ёмкость растёт
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i, 0)
+	}
+	for i := 100; i < 200; i++ {
+		cache.Get(i)
+	}
+	assert.Equal(t, 110, tune(0))
+
+	// без новых обращений ничего не меняется
+	assert.Equal(t, 110, tune(0))
+
+	// рост ограничен MaxCapacity
+	for i := 100; i < 110; i++ {
+		cache.Set(i, i, 0)
+	}
+	cache.Get("missing")
+	assert.Equal(t, 115, tune(0))
+
+	// почти все обращения — попадания, ёмкость уменьшается
+	for i := 0; i < 100; i++ {
+		cache.Get(i)
+	}
+	assert.Equal(t, 104, tune(0))
+}
+
+func TestCacher_AutoTuneMemoryPressure(t *testing.T) {
+	cache := New(Config{
+		Capacity:         100,
+		AutoTuneInterval: time.Hour,
+		MinCapacity:      85,
+		MaxHeapBytes:     1 << 20,
+	})
+	defer cache.Close()
+
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i, 0)
+	}
+
+	cache.mu.Lock()
+	cache.autoTune(2 << 20)
+	cache.autoTune(2 << 20)
+	cache.mu.Unlock()
+
+	// лишние элементы вытесняются, ёмкость не ниже MinCapacity
+	assert.Equal(t, 85, cache.GetCapacity())
+	assert.Equal(t, 85, cache.Metrics().Items)
+}
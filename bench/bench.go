@@ -0,0 +1,131 @@
+// Package bench runs reproducible workloads against cache configurations and
+// reports their hit ratio and throughput, to compare eviction policies on data
+// shaped like your own.
+package bench
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+// Workload is a named sequence of key accesses.
+type Workload struct {
+	Name string
+	Keys []uint64
+}
+
+// Zipfian returns n accesses to keys in [0, keys) with a Zipf distribution
+// of exponent s (> 1): a few keys are very popular and most are rarely accessed.
+// The same seed always produces the same sequence.
+func Zipfian(n int, keys uint64, s float64, seed uint64) Workload {
+	r := rand.New(rand.NewPCG(seed, seed))
+	zipf := rand.NewZipf(r, s, 1, keys-1)
+
+	w := Workload{Name: fmt.Sprintf("zipfian(s=%g)", s), Keys: make([]uint64, n)}
+	for i := range w.Keys {
+		w.Keys[i] = zipf.Uint64()
+	}
+	return w
+}
+
+// Uniform returns n accesses to keys in [0, keys), each equally likely.
+// The same seed always produces the same sequence.
+func Uniform(n int, keys uint64, seed uint64) Workload {
+	r := rand.New(rand.NewPCG(seed, seed))
+
+	w := Workload{Name: "uniform", Keys: make([]uint64, n)}
+	for i := range w.Keys {
+		w.Keys[i] = r.Uint64N(keys)
+	}
+	return w
+}
+
+// Scan returns n accesses that cycle through keys in [0, keys) in order,
+// like a batch job reading a whole table.
+func Scan(n int, keys uint64) Workload {
+	w := Workload{Name: "scan", Keys: make([]uint64, n)}
+	for i := range w.Keys {
+		w.Keys[i] = uint64(i) % keys
+	}
+	return w
+}
+
+// Concat returns a workload running the given workloads one after another.
+func Concat(name string, workloads ...Workload) Workload {
+	w := Workload{Name: name}
+	for _, part := range workloads {
+		w.Keys = append(w.Keys, part.Keys...)
+	}
+	return w
+}
+
+// Result describes a workload run.
+type Result struct {
+	Workload   string
+	Policy     string
+	Capacity   int
+	Requests   int
+	Hits       int
+	Duration   time.Duration
+	HitRatio   float64
+	Throughput float64 // Requests per second
+}
+
+// String formats the result as a single line.
+func (r Result) String() string {
+	return fmt.Sprintf("%-20s %-8s capacity=%-8d hit ratio=%6.2f%% throughput=%.0f ops/s",
+		r.Workload, r.Policy, r.Capacity, r.HitRatio*100, r.Throughput)
+}
+
+// RunWorkload replays a workload on a new cache created from cfg.
+// Every access is a Get, followed by a Set of the key on a miss.
+// Unless ClearingInterval is set, the background cleaner is disabled.
+func RunWorkload(cfg cacher.Config, w Workload) Result {
+	if cfg.ClearingInterval == 0 {
+		cfg.ClearingInterval = -1
+	}
+	cache := cacher.New(cfg)
+	defer cache.Close()
+
+	hits := 0
+	start := time.Now()
+	for _, key := range w.Keys {
+		if _, err := cache.Get(key); err == nil {
+			hits++
+			continue
+		}
+		cache.Set(key, key, 0)
+	}
+	elapsed := time.Since(start)
+
+	r := Result{
+		Workload: w.Name,
+		Policy:   cache.GetEvictionPolicy(),
+		Capacity: cfg.Capacity,
+		Requests: len(w.Keys),
+		Hits:     hits,
+		Duration: elapsed,
+	}
+	if r.Requests > 0 {
+		r.HitRatio = float64(hits) / float64(r.Requests)
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(r.Requests) / elapsed.Seconds()
+	}
+	return r
+}
+
+// Compare runs every workload against every configuration.
+// Results are ordered by workload, then by configuration.
+func Compare(configs []cacher.Config, workloads ...Workload) []Result {
+	results := make([]Result, 0, len(configs)*len(workloads))
+	for _, w := range workloads {
+		for _, cfg := range configs {
+			results = append(results, RunWorkload(cfg, w))
+		}
+	}
+	return results
+}
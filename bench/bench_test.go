@@ -0,0 +1,52 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloads(t *testing.T) {
+	// Одинаковый seed — одинаковая последовательность
+	assert.Equal(t, Zipfian(100, 1000, 1.1, 1).Keys, Zipfian(100, 1000, 1.1, 1).Keys)
+	assert.Equal(t, Uniform(100, 1000, 1).Keys, Uniform(100, 1000, 1).Keys)
+	assert.NotEqual(t, Uniform(100, 1000, 1).Keys, Uniform(100, 1000, 2).Keys)
+
+	for _, key := range Uniform(1000, 10, 1).Keys {
+		assert.Less(t, key, uint64(10))
+	}
+	assert.Equal(t, []uint64{0, 1, 2, 0, 1}, Scan(5, 3).Keys)
+	assert.Len(t, Concat("mixed", Scan(5, 3), Scan(2, 3)).Keys, 7)
+}
+
+func TestRunWorkload(t *testing.T) {
+	// Все ключи помещаются в кэш — промахи только при первом обращении
+	r := RunWorkload(cacher.Config{Capacity: 10}, Scan(100, 10))
+	assert.Equal(t, "LRU", r.Policy)
+	assert.Equal(t, 100, r.Requests)
+	assert.Equal(t, 90, r.Hits)
+	assert.InDelta(t, 0.9, r.HitRatio, 0.001)
+	assert.Greater(t, r.Throughput, 0.0)
+	assert.Contains(t, r.String(), "scan")
+
+	// Циклический проход по 11 ключам при ёмкости 10 — худший случай для LRU
+	lru := RunWorkload(cacher.Config{Capacity: 10, EvictionPolicy: cacher.LRU}, Scan(110, 11))
+	mru := RunWorkload(cacher.Config{Capacity: 10, EvictionPolicy: cacher.MRU}, Scan(110, 11))
+	assert.Equal(t, 0, lru.Hits)
+	assert.Greater(t, mru.Hits, 0)
+}
+
+func TestCompare(t *testing.T) {
+	configs := []cacher.Config{
+		{Capacity: 100, EvictionPolicy: cacher.LRU},
+		{Capacity: 100, EvictionPolicy: cacher.LFU},
+	}
+	results := Compare(configs, Zipfian(10000, 1000, 1.2, 1), Uniform(10000, 1000, 1))
+	assert.Len(t, results, 4)
+	assert.Equal(t, "LFU", results[1].Policy)
+	assert.Equal(t, "uniform", results[2].Workload)
+
+	// На zipf-распределении кэш даёт заметно больше попаданий, чем на равномерном
+	assert.Greater(t, results[0].HitRatio, results[2].HitRatio)
+}
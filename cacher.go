@@ -1,27 +1,61 @@
 // Package cacher provides an in-memory, thread-safe cache with support for TTL,
-// multiple eviction policies (LRU, MRU, LFU, RANDOM), and automatic cleanup.
+// multiple eviction policies (LRU, MRU, LFU, RANDOM, CLOCK, SLRU, LRUK, WRANDOM, GDSF,
+// HYPERBOLIC, LIFO), and automatic cleanup.
 package cacher
 
 import (
-	"container/list"
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Eviction policies
 const (
-	LRU    = iota // Least Recently Used
-	MRU           // Most Recently Used
-	LFU           // Least Frequently Used
-	RANDOM        // Random eviction
+	LRU        = iota // Least Recently Used
+	MRU               // Most Recently Used
+	LFU               // Least Frequently Used
+	RANDOM            // Random eviction
+	CLOCK             // Second-chance approximation of LRU
+	SLRU              // Segmented LRU with probationary and protected segments
+	LRUK              // LRU-K: evicts by the K-th most recent access
+	WRANDOM           // Random eviction weighted towards rarely used items
+	GDSF              // Greedy-Dual-Size-Frequency: evicts large, rarely used items first
+	HYPERBOLIC        // Evicts by accesses per time in cache
+	LIFO              // Last In, First Out: evicts the most recently set item
 )
 
+// policyNames maps eviction policies to their names.
+var policyNames = [...]string{
+	LRU:        "LRU",
+	MRU:        "MRU",
+	LFU:        "LFU",
+	RANDOM:     "RANDOM",
+	CLOCK:      "CLOCK",
+	SLRU:       "SLRU",
+	LRUK:       "LRU-K",
+	WRANDOM:    "WRANDOM",
+	GDSF:       "GDSF",
+	HYPERBOLIC: "HYPERBOLIC",
+	LIFO:       "LIFO",
+}
+
+// DefaultExpiration can be passed as a TTL to use the cache's DefaultTTL.
+const DefaultExpiration time.Duration = -1
+
 var (
 	defaultClearingInterval = 100 * time.Second
+	defaultProtectedRatio   = 0.8
+	defaultLRUKHistory      = 2
+
+	// adaptiveSpeedUpRatio is the share of expired items per run
+	// above which the adaptive cleaner runs more often.
+	adaptiveSpeedUpRatio = 0.25
 )
 
 // Config holds configuration for the cache.
@@ -30,21 +64,303 @@ type Config struct {
 	// If 0, capacity is unlimited.
 	Capacity int
 
+	// AutoTuneInterval, if positive, makes the cache adjust Capacity at this
+	// interval based on the hit ratio and heap size, within MinCapacity and
+	// MaxCapacity. A full cache with a hit ratio below TargetHitRatio (default 0.9)
+	// grows by 10%; one with a hit ratio well above it, or a heap (runtime.MemStats.HeapAlloc)
+	// above MaxHeapBytes, shrinks by 10%. Capacity is the starting point and must not be 0.
+	// MaxCapacity 0 means no upper bound, MaxHeapBytes 0 no heap limit.
+	AutoTuneInterval time.Duration
+	MinCapacity      int
+	MaxCapacity      int
+	TargetHitRatio   float64
+	MaxHeapBytes     uint64
+
+	// DefaultTTL is the TTL of items set with DefaultExpiration.
+	// If 0, such items never expire.
+	DefaultTTL time.Duration
+
 	// ClearingInterval is how often expired items are removed.
-	// If 0, defaults to 100 seconds.
+	// If 0, defaults to 100 seconds. If negative, no background cleaner is started
+	// and expired items are only removed lazily (on access, on Set at full capacity
+	// or by DeleteExpired).
 	ClearingInterval time.Duration
 
+	// ExpiryTimer removes every item at the moment it expires (after GracePeriod),
+	// using a timer set to the next expiration instead of waiting for the cleaner,
+	// so OnExpired is called on time. This lets schedulers be built on the cache,
+	// e.g. set a key with a 30s TTL to do something 30 seconds from now.
+	ExpiryTimer bool
+
+	// AdaptiveClearing lets the cleaner tune its interval after every run:
+	// it halves the interval when many items expired and doubles it when none did,
+	// staying between MinClearingInterval and MaxClearingInterval.
+	AdaptiveClearing bool
+
+	// MinClearingInterval and MaxClearingInterval bound the adaptive interval.
+	// If 0, they default to a tenth and ten times ClearingInterval.
+	MinClearingInterval time.Duration
+	MaxClearingInterval time.Duration
+
 	// EvictionPolicy defines which item to remove when capacity is reached.
-	// Must be one of: LRU, MRU, LFU, RANDOM.
+	// Must be one of: LRU, MRU, LFU, RANDOM, CLOCK, SLRU, LRUK, WRANDOM, GDSF, HYPERBOLIC, LIFO.
 	EvictionPolicy int
+
+	// ProtectedRatio is the share of Capacity reserved for the protected segment
+	// of SLRU. Items are promoted there on their second access.
+	// If 0, defaults to 0.8.
+	ProtectedRatio float64
+
+	// EvictExpiringWithin, if positive, makes eviction at full capacity prefer
+	// items that expire within this time, soonest first, and apply EvictionPolicy
+	// only if there are none. So a fresh hot item is not evicted while one that
+	// is about to expire survives. Use a large value to always evict the item
+	// with the shortest remaining TTL first.
+	EvictExpiringWithin time.Duration
+
+	// LFUSampleSize, if positive, makes LFU pick the least frequently used of
+	// this many randomly sampled items (like Redis, e.g. 5) instead of scanning
+	// all items on every eviction. Larger samples are closer to exact LFU.
+	LFUSampleSize int
+
+	// LRUKHistory is the K of the LRUK policy: the number of recent accesses
+	// remembered per item. Items accessed fewer than K times are evicted first.
+	// If 0, defaults to 2.
+	LRUKHistory int
+
+	// TTLJitter randomizes the TTL of each item by up to ±TTLJitter of its value
+	// (e.g. 0.1 for ±10%), so items set together do not expire together.
+	// Must be between 0 and 1. If 0, TTLs are used as is.
+	TTLJitter float64
+
+	// CompressionThreshold enables gzip compression of []byte and string values
+	// of at least this many bytes. Values are decompressed transparently on read.
+	// If 0, values are never compressed.
+	CompressionThreshold int
+
+	// MaxValueBytes is the size of the largest value stored, in bytes as estimated
+	// by MemoryUsage after encoding and compression. Larger values are rejected:
+	// SetE returns ErrValueTooLarge, other setters drop the value together with
+	// the older value of the key. If 0, values of any size are stored.
+	MaxValueBytes int
+
+	// MaxKeyLength is the length of the longest string key stored, in bytes.
+	// Longer keys are rejected like oversized values, with ErrKeyTooLong.
+	// If 0, keys of any length are stored.
+	MaxKeyLength int
+
+	// HistoryDepth, if positive, keeps this many previous values of a key when it
+	// is overwritten with Set or Update, newest first, for GetPrevious and History.
+	// Previous values are dropped with the key. If 0, no history is kept.
+	HistoryDepth int
+
+	// Codec, if set, encodes values to bytes on Set and decodes them on read.
+	// Every Get returns a fresh copy, so callers cannot modify cached values.
+	// Values that fail to encode are stored as is.
+	Codec Codec
+
+	// Cloner, if set, copies values on every read, so callers can safely
+	// modify what Get returns. Not used for values encoded with Codec.
+	Cloner Cloner
+
+	// EvictBatchSize and EvictBatchPercent make a Set at full capacity evict
+	// a batch of items at once instead of one, to avoid evicting on every write.
+	// The batch is the larger of EvictBatchSize items and EvictBatchPercent
+	// percent of Capacity (e.g. 5). If both are 0, one item is evicted.
+	EvictBatchSize    int
+	EvictBatchPercent float64
+
+	// HighWatermark is the occupancy (fraction of Capacity, e.g. 0.9) at which
+	// OnHighWatermark is called. If 0, watermarks are disabled.
+	HighWatermark float64
+
+	// LowWatermark is the occupancy at which OnLowWatermark is called after
+	// the high watermark was reached. If 0, defaults to HighWatermark.
+	LowWatermark float64
+
+	// EvictToLowWatermark evicts items down to LowWatermark as soon as
+	// HighWatermark is reached, instead of one item per Set at full capacity.
+	EvictToLowWatermark bool
+
+	// OnHighWatermark and OnLowWatermark are called when occupancy crosses the watermarks.
+	OnHighWatermark WatermarkFunc
+	OnLowWatermark  WatermarkFunc
+
+	// GracePeriod keeps expired items for this long before they are removed.
+	// Get does not return them, but GetStale does, so stale values can be served
+	// while the origin is unavailable. If 0, expired items are removed right away.
+	GracePeriod time.Duration
+
+	// RefreshBeta scales how early GetWithRefresh asks for a refresh.
+	// Values above 1 favor earlier refreshes. If 0, defaults to 1.
+	RefreshBeta float64
+
+	// DoorkeeperKeys enables an admission doorkeeper: a bloom filter sized for
+	// this many keys that remembers keys set once. A new key is only cached
+	// the second time it is set, so one-hit wonders do not take up capacity.
+	// The filter is reset after DoorkeeperKeys keys were added. If 0, all keys are admitted.
+	DoorkeeperKeys int
+
+	// DoorkeeperFalsePositiveRate is the target false positive rate of the doorkeeper.
+	// If 0, defaults to 0.01.
+	DoorkeeperFalsePositiveRate float64
+
+	// GhostKeys, if positive, keeps the last GhostKeys evicted keys (without values)
+	// to count misses that a larger cache would have served. See WouldHaveHit.
+	GhostKeys int
+
+	// FrequencySketchKeys enables a count-min sketch sized for about this many
+	// distinct keys that tracks how often keys are read, including keys that
+	// are not cached. See EstimateFrequency. If 0, frequencies are not tracked.
+	FrequencySketchKeys int
+
+	// PurgeOnRead makes GetAll, Keys and Stats remove expired items before reading.
+	// By default they skip expired items and leave them to the cleaner.
+	PurgeOnRead bool
+
+	// TrackKeys, if positive, records hits, misses and evictions per key and the
+	// times of its last TrackKeys hits, to debug why specific keys keep getting
+	// evicted. See KeyStats. Stats are kept for the 10000 most recently used keys,
+	// including missing ones, until ResetCounter; enable it only while debugging.
+	TrackKeys int
+
+	// TrackLatency records how long Get and Set take and how long they wait
+	// for the cache lock, reported as percentiles by Metrics. It costs two
+	// time.Now calls per operation, so it is off by default.
+	TrackLatency bool
+
+	// CoarseClock, if positive, makes the cache read the time from a clock
+	// updated by a background goroutine at this resolution (e.g. 10ms) instead of
+	// calling time.Now on every operation. Access times and expiration are then
+	// only as precise as the resolution.
+	CoarseClock time.Duration
+
+	// KeyFunc, if set, maps every key to the string it is stored under.
+	// Use it to cache by keys that are not comparable, such as slices or
+	// structs containing slices, which otherwise panic. Keys returns mapped keys.
+	KeyFunc func(key interface{}) string
+
+	// OnEvicted is called for items evicted because the capacity is reached,
+	// and OnExpired for expired items when they are removed.
+	// Callbacks run outside the lock, so they may use the cache.
+	OnEvicted ItemFunc
+	OnExpired ItemFunc
+
+	// CallbackWorkers is the number of goroutines running OnEvicted, OnExpired
+	// and watermark callbacks. If 0, defaults to 4.
+	CallbackWorkers int
+
+	// CallbackQueueSize is the number of callbacks waiting for a worker.
+	// When the queue is full, further callbacks are dropped rather than
+	// blocking the cache. If 0, defaults to 1024.
+	CallbackQueueSize int
+
+	// FlushOnShutdown makes Shutdown remove all items, calling OnExpired
+	// for expired ones and OnEvicted for the rest.
+	FlushOnShutdown bool
+
+	// SnapshotPath, if set, is the file Shutdown saves the items to with SaveToFile.
+	SnapshotPath string
+
+	// SnapshotInterval, if positive, also saves the items to SnapshotPath this often.
+	// Snapshot counts, errors, duration and size are reported by Metrics.
+	SnapshotInterval time.Duration
+
+	// WALPath, if set, is a write-ahead log file every change of the items is
+	// appended to: writes, TTL changes, pins, deletes, evictions, expirations and Clear.
+	// After a crash, ReplayWAL reconstructs the items from it, more exactly than
+	// snapshots can. Reads are not logged, so sliding TTLs restart from the last write.
+	WALPath string
+
+	// WALSync is the fsync policy of the WAL: WALSyncPeriodic (default),
+	// WALSyncAlways or WALSyncNever.
+	WALSync int
+
+	// WALSyncInterval is how often the WAL is synced with WALSyncPeriodic.
+	// If 0, defaults to 1 second.
+	WALSyncInterval time.Duration
+
+	// SnapshotKeep is the number of snapshots kept at SnapshotPath, SnapshotPath.1
+	// and so on, newest first. If 0, only the latest snapshot is kept.
+	SnapshotKeep int
+
+	// TombstoneTTL, if positive, makes Delete leave a tombstone that blocks setting
+	// the key again for this long, so a racing writer holding a stale value cannot
+	// put it back right after the key was invalidated. Set and the other setters
+	// silently ignore buried keys; SetIfVersion does not check tombstones.
+	TombstoneTTL time.Duration
+
+	// PinnedNeverExpire makes pinned items ignore their TTL.
+	// By default pinned items are protected from eviction but still expire.
+	PinnedNeverExpire bool
+
+	// Overflow is an optional secondary store. Items evicted because the
+	// capacity is reached are written to it and restored on a later Get.
+	// If it implements KeyLister, items it held before a restart are restored too.
+	// If nil, evicted items are discarded.
+	Overflow Backend
+
+	// Indexes are secondary indexes by name, so items can be found or removed
+	// by attributes derived from their values with GetByIndex and DeleteByIndex.
+	// More can be added with AddIndex.
+	Indexes map[string]IndexFunc
+
+	// Store is an optional system of record written by Set, Write and WriteDelete,
+	// so the cache can front a database. Other setters do not write to it, and
+	// Delete only removes the cached value.
+	Store Backend
+
+	// WriteBehind makes Write and WriteDelete update the cache right away and
+	// flush the changes to Store in batches every WriteBehindInterval
+	// (default 1 second), retrying failed changes up to WriteRetries times
+	// (default 3). Shutdown flushes the remaining changes.
+	// By default writes go through to Store synchronously.
+	WriteBehind         bool
+	WriteBehindInterval time.Duration
+	WriteRetries        int
+
+	// Logger receives debug and info messages about evictions, cleaner runs,
+	// configuration changes and overflow errors. If nil, nothing is logged.
+	Logger *slog.Logger
+}
+
+// Option changes the configuration passed to New.
+type Option func(*Config)
+
+// WithDoorkeeper enables the admission doorkeeper for about expectedKeys keys
+// with the given false positive rate. See Config.DoorkeeperKeys.
+func WithDoorkeeper(expectedKeys int, fpRate float64) Option {
+	return func(cfg *Config) {
+		cfg.DoorkeeperKeys = expectedKeys
+		cfg.DoorkeeperFalsePositiveRate = fpRate
+	}
 }
 
 // cache holds the actual cached value and metadata.
 type cache struct {
 	value      interface{}   // The stored value
 	ttl        time.Duration // Time-to-live
+	deadline   time.Time     // Absolute expiration time, zero if none
+	negative   bool          // Marks the key as known to be missing
+	recompute  time.Duration // Time it took to compute the value (for XFetch)
+	version    uint64        // Changes on every write
 	counter    int           // Access counter (for LFU)
+	createdAt  time.Time     // When the value was set
+	modifiedAt time.Time     // When the value was last updated in place, zero if never
 	lastUsedAt time.Time     // Last access time (for LRU/MRU)
+	priority   int           // Eviction priority (lower is evicted first)
+	pinned     bool          // Pinned items are never evicted
+	referenced bool          // Accessed since the clock hand last passed (for CLOCK)
+	protected  bool          // In the protected segment (for SLRU)
+	history    []time.Time   // Last K access times, oldest first (for LRUK)
+	size       int64         // Stored value size in bytes, 0 if not measured (for GDSF)
+	clock      float64       // Cache clock at the last access (for GDSF)
+	compressed int           // Compression kind of value
+	encoded    bool          // Value is encoded with the codec
+	etag       string        // Hash of value for conditional reads, empty until computed
+	previous   []cache       // Overwritten values, newest first (for GetPrevious)
+	cloner     Cloner        // Per-item cloner, overrides the cache one
+	element    *element      // Position in the access order list
 }
 
 // Cacher is a thread-safe in-memory cache with TTL and eviction policies.
@@ -52,119 +368,624 @@ type Cacher struct {
 	mu               sync.RWMutex
 	cache            map[interface{}]cache // Main storage
 	capacity         int                   // Max items
-	keys             *list.List            // Order of access (for LRU/MRU)
+	keys             *keyList              // Order of access (for LRU/MRU)
+	hand             *element              // Clock hand (for CLOCK)
+	protectedRatio   float64
+	protectedCount   int // Items in the protected segment (for SLRU)
+	lruK             int
+	lfuSample        int
+	expiringWithin   time.Duration
+	gdsfClock        float64     // Priority of the last evicted item (for GDSF)
+	priorities       map[int]int // Number of unpinned items per priority
+	expiry           expiryHeap  // Expiring items ordered by expiration time
+	expiryIndex      map[interface{}]*expiryEntry
+	expiryWake       chan struct{} // Reschedules the expiry timer, nil if disabled
+	schedule         expiryHeap    // Values set with SetAfter ordered by visibility time
+	scheduled        map[interface{}]scheduledItem
+	scheduleWake     chan struct{} // Reschedules the SetAfter timer
+	scheduleOnce     sync.Once     // Starts the SetAfter timer
+	tombstoneTTL     time.Duration
+	tombstones       map[interface{}]time.Time // Deleted keys that cannot be set until the time
+	counters         counters
+	tuner            tuner       // Auto-tuning settings and state
+	ghosts           *ghostList  // Recently evicted keys, nil if disabled
+	latency          *latency    // Get/Set durations and lock waits, nil if disabled
+	keyTracker       *keyTracker // Per-key stats, nil if disabled
+	pinnedNoExpire   bool
+	grace            time.Duration
+	purgeOnRead      bool
+	refreshBeta      float64
+	doorkeeper       *bloom  // Admission filter for new keys, nil if disabled
+	sketch           *sketch // Read frequency estimator, nil if disabled
+	ttlJitter        float64
+	compressAbove    int
+	maxValueBytes    int
+	maxKeyLength     int
+	historyDepth     int
+	evicted          *[]interface{} // Collects keys evicted during SetE, nil otherwise
+	codec            Codec
+	cloner           Cloner
+	highWatermark    float64
+	lowWatermark     float64
+	shedToLow        bool
+	evictSize        int
+	evictPercent     float64
+	onHighWatermark  WatermarkFunc
+	onLowWatermark   WatermarkFunc
+	onEvicted        ItemFunc
+	onExpired        ItemFunc
+	dispatcher       *dispatcher // Runs callbacks outside the lock
+	flushOnShutdown  bool
+	snapshotPath     string
+	snapshotKeep     int
+	snapshots        snapshotStats
+	wal              *wal // Write-ahead log, nil if disabled
+	shutdown         bool
+	aboveHigh        bool         // High watermark reached and low not yet
+	defaultTTL       atomic.Int64 // Read outside the lock by newItem
 	clearingInterval time.Duration
+	stopClearing     context.CancelFunc // Stops the running cleaner, nil if none
+	adaptiveClearing bool
+	minClearing      time.Duration
+	maxClearing      time.Duration
 	evictionPolicy   int
+	overflow         Backend      // Secondary store for evicted items
+	origin           Backend      // System of record for Write, nil if none
+	writeBehind      *writeBehind // Queued store changes, nil in write-through mode
+	writeRetries     int
+	spilled          map[interface{}]*spilledEntry // Keys currently held by overflow
+	spillQueue       []spillOp                     // Overflow store writes for runSpills
+	spillWake        chan struct{}
+	spillDone        chan struct{} // Closed when runSpills has written the queue and returned
+	flightMu         sync.Mutex
+	flights          map[interface{}]*call   // In-flight shared calls
+	waiters          map[interface{}]*waiter // Goroutines blocked in Wait
+	version          uint64                  // Last assigned item version
+	keyFunc          func(interface{}) string
+	clock            *atomic.Int64 // Coarse time in Unix nanoseconds, nil if disabled
+	logger           *slog.Logger
+	indexes          map[string]*index // Secondary indexes by name
+	deps             dependencies      // Links set by SetWithDependencies
+	cfg              Config            // Configuration with defaults, for Clone
 	ctx              context.Context
 	cancel           context.CancelFunc
 }
 
-// New creates a new cache with the given configuration.
-// Starts a background goroutine to clean expired items.
-func New(cfg Config) *Cacher {
+// New creates a new cache with the given configuration, changed by opts.
+// Starts a background goroutine to clean expired items, unless ClearingInterval is negative.
+// Invalid values are clamped or replaced with defaults; see Config.Validate and MustNew.
+func New(cfg Config, opts ...Option) *Cacher {
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 	if cfg.ClearingInterval == 0 {
 		cfg.ClearingInterval = defaultClearingInterval
 	}
+	if cfg.MinClearingInterval == 0 {
+		cfg.MinClearingInterval = cfg.ClearingInterval / 10
+	}
+	if cfg.MaxClearingInterval == 0 {
+		cfg.MaxClearingInterval = cfg.ClearingInterval * 10
+	}
+	if cfg.ProtectedRatio == 0 {
+		cfg.ProtectedRatio = defaultProtectedRatio
+	}
+	if cfg.LRUKHistory <= 0 {
+		cfg.LRUKHistory = defaultLRUKHistory
+	}
+	if cfg.LowWatermark == 0 || cfg.LowWatermark > cfg.HighWatermark {
+		cfg.LowWatermark = cfg.HighWatermark
+	}
+	if cfg.RefreshBeta <= 0 {
+		cfg.RefreshBeta = defaultRefreshBeta
+	}
+	if cfg.DoorkeeperFalsePositiveRate <= 0 || cfg.DoorkeeperFalsePositiveRate >= 1 {
+		cfg.DoorkeeperFalsePositiveRate = defaultDoorkeeperFPRate
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.New(slog.DiscardHandler)
+	}
+	if cfg.CallbackWorkers <= 0 {
+		cfg.CallbackWorkers = defaultCallbackWorkers
+	}
+	if cfg.CallbackQueueSize <= 0 {
+		cfg.CallbackQueueSize = defaultCallbackQueue
+	}
+	if cfg.TargetHitRatio <= 0 || cfg.TargetHitRatio > 1 {
+		cfg.TargetHitRatio = defaultTargetHitRatio
+	}
+	if cfg.WriteBehindInterval <= 0 {
+		cfg.WriteBehindInterval = defaultWriteBehindInterval
+	}
+	if cfg.WriteRetries <= 0 {
+		cfg.WriteRetries = defaultWriteRetries
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cacher := &Cacher{
 		cache:            make(map[interface{}]cache),
 		capacity:         cfg.Capacity,
-		keys:             list.New(),
+		priorities:       make(map[int]int),
+		expiryIndex:      make(map[interface{}]*expiryEntry),
+		scheduled:        make(map[interface{}]scheduledItem),
+		scheduleWake:     make(chan struct{}, 1),
+		tombstoneTTL:     cfg.TombstoneTTL,
+		tombstones:       make(map[interface{}]time.Time),
 		clearingInterval: cfg.ClearingInterval,
+		adaptiveClearing: cfg.AdaptiveClearing,
+		minClearing:      cfg.MinClearingInterval,
+		maxClearing:      cfg.MaxClearingInterval,
 		evictionPolicy:   cfg.EvictionPolicy,
+		protectedRatio:   cfg.ProtectedRatio,
+		lruK:             cfg.LRUKHistory,
+		lfuSample:        cfg.LFUSampleSize,
+		expiringWithin:   cfg.EvictExpiringWithin,
+		pinnedNoExpire:   cfg.PinnedNeverExpire,
+		grace:            max(cfg.GracePeriod, 0),
+		purgeOnRead:      cfg.PurgeOnRead,
+		refreshBeta:      cfg.RefreshBeta,
+		ttlJitter:        min(max(cfg.TTLJitter, 0), 1),
+		compressAbove:    cfg.CompressionThreshold,
+		maxValueBytes:    cfg.MaxValueBytes,
+		maxKeyLength:     cfg.MaxKeyLength,
+		historyDepth:     cfg.HistoryDepth,
+		codec:            cfg.Codec,
+		cloner:           cfg.Cloner,
+		highWatermark:    cfg.HighWatermark,
+		lowWatermark:     cfg.LowWatermark,
+		shedToLow:        cfg.EvictToLowWatermark,
+		evictSize:        cfg.EvictBatchSize,
+		evictPercent:     min(max(cfg.EvictBatchPercent, 0), 100),
+		onHighWatermark:  cfg.OnHighWatermark,
+		onLowWatermark:   cfg.OnLowWatermark,
+		onEvicted:        cfg.OnEvicted,
+		onExpired:        cfg.OnExpired,
+		flushOnShutdown:  cfg.FlushOnShutdown,
+		snapshotPath:     cfg.SnapshotPath,
+		snapshotKeep:     cfg.SnapshotKeep,
+		overflow:         cfg.Overflow,
+		origin:           cfg.Store,
+		writeRetries:     cfg.WriteRetries,
+		spilled:          make(map[interface{}]*spilledEntry),
+		flights:          make(map[interface{}]*call),
+		waiters:          make(map[interface{}]*waiter),
+		logger:           cfg.Logger,
+		dispatcher:       newDispatcher(cfg.CallbackWorkers, cfg.CallbackQueueSize, cfg.Logger),
+		keyFunc:          cfg.KeyFunc,
+		indexes:          make(map[string]*index),
+		deps:             newDependencies(),
+		cfg:              cfg,
 		ctx:              ctx,
 		cancel:           cancel,
 	}
 
-	go cacher.startClearing()
+	cacher.keys = newKeyList(&cacher.counters)
+	for name, fn := range cfg.Indexes {
+		cacher.indexes[name] = newIndex(fn)
+	}
+	cacher.defaultTTL.Store(int64(cfg.DefaultTTL))
+	if cfg.CoarseClock > 0 {
+		cacher.startClock(cfg.CoarseClock)
+	}
+	if cfg.DoorkeeperKeys > 0 {
+		cacher.doorkeeper = newBloom(cfg.DoorkeeperKeys, cfg.DoorkeeperFalsePositiveRate)
+	}
+
+	if cfg.GhostKeys > 0 {
+		cacher.ghosts = newGhostList(cfg.GhostKeys, &cacher.counters)
+	}
+	if cfg.FrequencySketchKeys > 0 {
+		cacher.sketch = newSketch(cfg.FrequencySketchKeys)
+	}
+	if cfg.TrackLatency {
+		cacher.latency = new(latency)
+	}
+	if cfg.TrackKeys > 0 {
+		cacher.keyTracker = newKeyTracker(cfg.TrackKeys, &cacher.counters)
+	}
+	if cfg.Overflow != nil {
+		cacher.loadSpilled()
+		cacher.spillWake = make(chan struct{}, 1)
+		cacher.spillDone = make(chan struct{})
+		go cacher.runSpills()
+	}
+
+	cacher.restartClearing()
+	if cfg.Store != nil && cfg.WriteBehind {
+		cacher.writeBehind = &writeBehind{pending: make(map[interface{}]pendingWrite)}
+		go cacher.startWriteBehind(cfg.WriteBehindInterval)
+	}
+	if cfg.AutoTuneInterval > 0 {
+		cacher.tuner = tuner{
+			min:     max(cfg.MinCapacity, 1),
+			max:     cfg.MaxCapacity,
+			target:  cfg.TargetHitRatio,
+			maxHeap: cfg.MaxHeapBytes,
+		}
+		go cacher.startAutoTune(cfg.AutoTuneInterval)
+	}
+	if cfg.ExpiryTimer {
+		cacher.expiryWake = make(chan struct{}, 1)
+		go cacher.runExpiryTimer()
+	}
+	if cfg.SnapshotInterval > 0 && cfg.SnapshotPath != "" {
+		go cacher.startSnapshots(cfg.SnapshotInterval)
+	}
+	if cfg.WALPath != "" {
+		if cfg.WALSyncInterval <= 0 {
+			cfg.WALSyncInterval = defaultWALSyncInterval
+		}
+		wal, err := openWAL(cfg.WALPath, cfg.WALSync)
+		if err != nil {
+			cacher.logger.Error("cache wal disabled", "path", cfg.WALPath, "error", err)
+		} else {
+			cacher.wal = wal
+			go cacher.runWAL(wal, cfg.WALSyncInterval)
+		}
+	}
 	return cacher
 }
 
 // Get retrieves a value from the cache by key.
+// If the key was evicted to the overflow store, it is restored first.
 // Returns an error if the key is not found or the TTL has expired.
 func (c *Cacher) Get(key interface{}) (interface{}, error) {
-	c.mu.RLock()
-	value, ok := c.cache[key]
-	c.mu.RUnlock()
-
-	if !ok {
-		return nil, fmt.Errorf("cache not found for key: %v", key)
+	if c.latency != nil {
+		defer c.latency.get.since(time.Now())
 	}
+	key = c.key(key)
+	c.unspill(key)
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	return c.get(key)
+}
+
+// GetStale is like Get, but returns an expired item that has not been removed yet
+// instead of an error, with stale set to true. Useful to serve stale content
+// when the origin is unavailable. Stale reads do not count as accesses.
+func (c *Cacher) GetStale(key interface{}) (value interface{}, stale bool, err error) {
+	key = c.key(key)
+	c.unspill(key)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if err := checkExpiration(value); err != nil {
-		c.removeKey(key)
+	if item, ok := c.cache[key]; ok && c.checkExpiration(item) != nil {
+		c.counters.misses++
+		if item.negative {
+			return nil, true, ErrNegativeCached
+		}
+		return c.load(item), true, nil
+	}
+	value, err = c.get(key)
+	return value, false, err
+}
+
+// get looks up a key, counting the access. Must be called with c.mu held.
+func (c *Cacher) get(key interface{}) (interface{}, error) {
+	if c.sketch != nil {
+		c.sketch.increment(key)
+	}
+
+	if len(c.scheduled) > 0 {
+		c.releaseScheduled()
+	}
+
+	if len(c.spilled) > 0 {
+		c.restorePending(key)
+	}
+	value, ok := c.cache[key]
+	if !ok {
+		c.counters.misses++
+		if c.ghosts != nil {
+			c.ghosts.miss(key)
+		}
+		if c.keyTracker != nil {
+			c.keyTracker.miss(key)
+		}
+		return nil, fmt.Errorf("cache not found for key: %v", key)
+	}
+
+	if err := c.checkExpiration(value); err != nil {
+		c.expire(key, value)
+		c.counters.misses++
+		if c.keyTracker != nil {
+			c.keyTracker.miss(key)
+		}
 		return nil, err
 	}
+	if len(c.deps.links) > 0 && c.expireDependencies(key) {
+		c.counters.misses++
+		if c.keyTracker != nil {
+			c.keyTracker.miss(key)
+		}
+		return nil, fmt.Errorf("cache not found for key: %v", key)
+	}
 	c.update(key, value)
+	c.counters.hits++
+	if c.keyTracker != nil {
+		c.keyTracker.hit(key, c.now())
+	}
 
-	keyNote := c.getKeyNote(key)
-	if keyNote != nil {
-		c.keys.MoveToFront(keyNote)
+	if c.evictionPolicy != CLOCK {
+		keyNote := c.getKeyNote(key)
+		if keyNote != nil {
+			c.keys.MoveToFront(keyNote)
+		}
+	}
+	if c.evictionPolicy == SLRU {
+		c.promote(key)
 	}
 
-	return value.value, nil
+	if value.negative {
+		return nil, ErrNegativeCached
+	}
+	return c.load(value), nil
 }
 
-// GetAll returns all values in the cache (order not guaranteed).
+// GetAll returns all unexpired values in the cache (order not guaranteed).
+// Negative entries are skipped.
 func (c *Cacher) GetAll() []interface{} {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.purgeBeforeRead()
 	values := make([]interface{}, 0, len(c.cache))
 	for _, item := range c.cache {
-		values = append(values, item.value)
+		if item.negative || c.checkExpiration(item) != nil {
+			continue
+		}
+		values = append(values, c.load(item))
 	}
 	return values
 }
 
+// Items returns a copy of all unexpired key-value pairs.
+// Items are copied under the lock, values are decoded after it is released.
+// Negative entries are skipped.
+func (c *Cacher) Items() map[interface{}]interface{} {
+	c.mu.RLock()
+	snapshot := make(map[interface{}]cache, len(c.cache))
+	for key, item := range c.cache {
+		if !item.negative && c.checkExpiration(item) == nil {
+			snapshot[key] = item
+		}
+	}
+	c.mu.RUnlock()
+
+	items := make(map[interface{}]interface{}, len(snapshot))
+	for key, item := range snapshot {
+		items[key] = c.load(item)
+	}
+	return items
+}
+
 // Set adds a value to the cache with a TTL.
 // If capacity is reached, an item is evicted based on the policy.
+// If Config.Store is set, the value is written to it as with Write; a failed
+// write-through is logged and leaves the cache unchanged.
 func (c *Cacher) Set(key, value interface{}, ttl time.Duration) {
-	item := cache{
-		value:      value,
-		ttl:        ttl,
-		counter:    1,
-		lastUsedAt: time.Now(),
+	if c.origin != nil {
+		if err := c.Write(key, value, ttl); err != nil {
+			c.logger.Warn("cache store write failed", "key", key, "error", err)
+		}
+		return
 	}
+	c.SetWithPriority(key, value, ttl, 0)
+}
 
-	c.mu.Lock()
+// SetResult describes the effect of SetE.
+type SetResult struct {
+	Stored   bool          // False if a tombstone or the doorkeeper kept the value out
+	Replaced bool          // An unexpired value of the key was overwritten
+	Evicted  []interface{} // Keys evicted to make room, in eviction order
+}
+
+// SetE is like Set, but reports what the write displaced, and returns an error
+// instead of dropping the value if it exceeds the size limits
+// (see Config.MaxValueBytes and Config.MaxKeyLength).
+func (c *Cacher) SetE(key, value interface{}, ttl time.Duration) (SetResult, error) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, ttl, 0)
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	var res SetResult
+	if old, ok := c.cache[key]; ok && !old.negative && c.checkExpiration(old) == nil {
+		res.Replaced = true
+	}
+	c.evicted = &res.Evicted
+	defer func() { c.evicted = nil }()
+
+	stored, err := c.store(key, item)
+	if err != nil {
+		return SetResult{}, err
+	}
+	res.Stored = stored
+	res.Replaced = res.Replaced && stored
+	return res, nil
+}
+
+// SetWithPriority adds a value to the cache with a TTL and an eviction priority.
+// When capacity is reached, only items with the lowest priority in the cache
+// are considered for eviction. Set uses priority 0.
+func (c *Cacher) SetWithPriority(key, value interface{}, ttl time.Duration, priority int) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, ttl, priority)
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.store(key, item)
+}
+
+// SetWithDeadline adds a value to the cache that expires at the given time
+// instead of after a TTL. Accessing the item does not extend its lifetime.
+func (c *Cacher) SetWithDeadline(key, value interface{}, deadline time.Time) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, 0, 0)
+	item.deadline = deadline
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.store(key, item)
+}
+
+// SetWithLimits adds a value to the cache with two independent limits:
+// it expires after idle without access or maxLifetime after being set,
+// whichever comes first. A zero limit is not applied.
+func (c *Cacher) SetWithLimits(key, value interface{}, idle, maxLifetime time.Duration) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, idle, 0)
+	if maxLifetime > 0 {
+		item.deadline = item.createdAt.Add(maxLifetime)
+	}
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.store(key, item)
+}
+
+// SetWithCloner adds a value to the cache with a TTL.
+// Reads return a copy of the value made by cloner instead of the value itself.
+func (c *Cacher) SetWithCloner(key, value interface{}, ttl time.Duration, cloner Cloner) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, ttl, 0)
+	item.cloner = cloner
+
+	c.lock()
 	defer c.mu.Unlock()
 
-	if c.capacity > 0 && len(c.cache) >= c.capacity {
-		c.evict()
+	c.store(key, item)
+}
+
+// Update replaces the value of an existing item, keeping its TTL, priority,
+// access counter and position in the access order.
+// Returns an error if the key is not found or the TTL has expired,
+// and ErrValueTooLarge if the value exceeds Config.MaxValueBytes.
+func (c *Cacher) Update(key, value interface{}) error {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
 	}
+	key = c.key(key)
+
+	c.lock()
+	defer c.mu.Unlock()
 
+	item, ok := c.cache[key]
+	if !ok {
+		return fmt.Errorf("cache not found for key: %v", key)
+	}
+	if err := c.checkExpiration(item); err != nil {
+		c.expire(key, item)
+		return err
+	}
+	if c.maxValueBytes > 0 {
+		if err := c.checkLimits(key, c.pack(item, value)); err != nil {
+			return err
+		}
+	}
+	if c.historyDepth > 0 {
+		item.previous = c.previousOf(item)
+	}
+
+	c.replace(key, item, value)
+	return nil
+}
+
+// replace stores a new value in an existing item, keeping its metadata.
+// Must be called with c.mu held.
+func (c *Cacher) replace(key interface{}, item cache, value interface{}) cache {
+	item = c.pack(item, value)
+	item.version = c.nextVersion()
+	item.modifiedAt = c.now()
+	item.etag = ""
 	c.cache[key] = item
-	c.keys.PushFront(key)
+	c.logSet(key, item)
+	c.reindex(key, item)
+	if len(c.deps.links) > 0 {
+		c.invalidateDependents(key)
+	}
+	return item
 }
 
-// Clear removes all items from the cache.
+// Clear removes all items from the cache, including those in the overflow store.
 func (c *Cacher) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.clear()
+	c.log(walRecord{Op: walClear})
+}
+
+// clear removes all items. Must be called with c.mu held.
+func (c *Cacher) clear() {
 	c.cache = make(map[interface{}]cache)
-	c.keys = list.New()
+	c.keys = newKeyList(&c.counters)
+	c.hand = nil
+	c.protectedCount = 0
+	c.priorities = make(map[int]int)
+	c.expiry = nil
+	c.expiryIndex = make(map[interface{}]*expiryEntry)
+	c.schedule = nil
+	c.scheduled = make(map[interface{}]scheduledItem)
+	c.deps = newDependencies()
+	for name, idx := range c.indexes {
+		c.indexes[name] = newIndex(idx.fn)
+	}
+	for key := range c.spilled {
+		c.dropSpilled(key)
+	}
+	c.checkWatermarks()
 }
 
-// Delete removes an item from the cache by key.
+// Delete removes an item from the cache by key, including a value scheduled with SetAfter.
+// With TombstoneTTL, the key cannot be set again for that long, even if it was not found.
 // Returns an error if the key is not found.
 func (c *Cacher) Delete(key interface{}) error {
+	key = c.key(key)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if _, ok := c.cache[key]; !ok {
+	scheduled := c.unschedule(key)
+	if !c.remove(key) && !scheduled {
 		return fmt.Errorf("cache not found for key: %v", key)
 	}
-
-	c.removeKey(key)
 	return nil
 }
 
+// DeleteExpired removes all expired items and returns how many were removed.
+// Useful when the background cleaner runs rarely or for deterministic cleanup in tests.
+func (c *Cacher) DeleteExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := c.removeExpired()
+	c.checkWatermarks()
+	return removed
+}
+
 // SetCapacity changes the maximum number of items in the cache.
 // Can be called at runtime.
 func (c *Cacher) SetCapacity(newCapacity int) error {
@@ -175,7 +996,9 @@ func (c *Cacher) SetCapacity(newCapacity int) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.logger.Info("cache capacity changed", "from", c.capacity, "to", newCapacity)
 	c.capacity = newCapacity
+	c.checkWatermarks()
 	return nil
 }
 
@@ -187,15 +1010,17 @@ func (c *Cacher) GetCapacity() int {
 }
 
 // SetEvictionPolicy changes the eviction policy at runtime.
-// Must be one of: LRU, MRU, LFU, RANDOM.
+// Must be one of: LRU, MRU, LFU, RANDOM, CLOCK, SLRU, LRUK, WRANDOM, GDSF, HYPERBOLIC, LIFO.
 func (c *Cacher) SetEvictionPolicy(policy int) error {
-	if policy < LRU || policy > RANDOM {
-		return fmt.Errorf("invalid eviction policy: %d (must be 0-3)", policy)
+	if policy < 0 || policy >= len(policyNames) {
+		return fmt.Errorf("invalid eviction policy: %d (must be 0-%d)", policy, len(policyNames)-1)
 	}
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.logger.Info("cache eviction policy changed",
+		"from", policyName(c.evictionPolicy), "to", policyName(policy))
 	c.evictionPolicy = policy
 	return nil
 }
@@ -205,21 +1030,54 @@ func (c *Cacher) GetEvictionPolicy() string {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	switch c.evictionPolicy {
-	case LRU:
-		return "LRU"
-	case MRU:
-		return "MRU"
-	case LFU:
-		return "LFU"
-	case RANDOM:
-		return "RANDOM"
+	return policyName(c.evictionPolicy)
+}
+
+// SetClearingInterval changes how often expired items are removed and restarts
+// the cleaner. As with Config.ClearingInterval, 0 means the default of 100 seconds
+// and a negative interval stops the cleaner. Can be called at runtime.
+func (c *Cacher) SetClearingInterval(interval time.Duration) {
+	if interval == 0 {
+		interval = defaultClearingInterval
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logger.Info("cache clearing interval changed", "from", c.clearingInterval, "to", interval)
+	c.clearingInterval = interval
+	c.restartClearing()
+}
+
+// GetClearingInterval returns the current clearing interval.
+// With AdaptiveClearing it changes after every cleaner run.
+func (c *Cacher) GetClearingInterval() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.clearingInterval
+}
+
+// key maps a caller's key with the key function, if any.
+func (c *Cacher) key(key interface{}) interface{} {
+	if c.keyFunc == nil {
+		return key
 	}
-	return "UNKNOWN"
+	return c.keyFunc(key)
+}
+
+// policyName returns the name of an eviction policy.
+func policyName(policy int) string {
+	if policy < 0 || policy >= len(policyNames) {
+		return "UNKNOWN"
+	}
+	return policyNames[policy]
 }
 
 // SetTTL updates the TTL of an existing item.
 func (c *Cacher) SetTTL(key interface{}, ttl time.Duration) error {
+	key = c.key(key)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -230,46 +1088,159 @@ func (c *Cacher) SetTTL(key interface{}, ttl time.Duration) error {
 
 	item.ttl = ttl
 	c.cache[key] = item
+	c.logSet(key, item)
+	c.trackExpiry(key)
 	return nil
 }
 
-// GetTTL returns the remaining TTL for a key.
+// ExpireAt sets an absolute expiration time for an existing item.
+// The item expires at t or when its TTL runs out, whichever comes first.
+// A zero t removes the deadline. Returns an error if the key is not found.
+func (c *Cacher) ExpireAt(key interface{}, t time.Time) error {
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.cache[key]
+	if !ok {
+		return fmt.Errorf("cache not found for key: %v", key)
+	}
+
+	item.deadline = t
+	c.cache[key] = item
+	c.logSet(key, item)
+	c.trackExpiry(key)
+	return nil
+}
+
+// Touch resets the expiration of an item to ttl from now without reading it.
+// Unlike Get, it does not change the access counter or the access order.
+// Returns an error if the key is not found or the TTL has expired.
+func (c *Cacher) Touch(key interface{}, ttl time.Duration) error {
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.cache[key]
+	if !ok {
+		return fmt.Errorf("cache not found for key: %v", key)
+	}
+	if err := c.checkExpiration(item); err != nil {
+		c.expire(key, item)
+		return err
+	}
+
+	item.ttl = ttl
+	item.lastUsedAt = c.now()
+	c.cache[key] = item
+	c.logSet(key, item)
+	c.trackExpiry(key)
+	return nil
+}
+
+// GetTTL returns the TTL for a key.
 // Returns an error if the key is not found.
+// See Entry for the remaining time and other metadata.
 func (c *Cacher) GetTTL(key interface{}) (time.Duration, error) {
+	key = c.key(key)
+
 	c.mu.RLock()
 	item, ok := c.cache[key]
 	c.mu.RUnlock()
 	if !ok {
 		return 0, fmt.Errorf("cache not found for key: %v", key)
 	}
+	if err := c.checkExpiration(item); err != nil {
+		return 0, err
+	}
 	return item.ttl, nil
 }
 
+// Pin protects an item from capacity eviction.
+// If every item is pinned, the cache may grow beyond its capacity.
+// Returns an error if the key is not found.
+func (c *Cacher) Pin(key interface{}) error {
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.cache[key]
+	if !ok {
+		return fmt.Errorf("cache not found for key: %v", key)
+	}
+	if item.pinned {
+		return nil
+	}
+
+	c.trackPriority(item.priority, -1)
+	item.pinned = true
+	c.cache[key] = item
+	c.logSet(key, item)
+	c.trackExpiry(key)
+	return nil
+}
+
+// Unpin makes a pinned item evictable again.
+// Returns an error if the key is not found.
+func (c *Cacher) Unpin(key interface{}) error {
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.cache[key]
+	if !ok {
+		return fmt.Errorf("cache not found for key: %v", key)
+	}
+	if !item.pinned {
+		return nil
+	}
+
+	c.trackPriority(item.priority, 1)
+	item.pinned = false
+	c.cache[key] = item
+	c.logSet(key, item)
+	c.trackExpiry(key)
+	return nil
+}
+
 // GetCounter returns the access counter for a key.
-// Useful for LFU debugging.
+// Useful for LFU debugging. See Entry for all metadata at once,
+// and KeyStats and ResetCounter to follow a key across evictions.
 func (c *Cacher) GetCounter(key interface{}) (int, error) {
+	key = c.key(key)
+
 	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	item, ok := c.cache[key]
-	c.mu.RUnlock()
 	if !ok {
 		return -1, fmt.Errorf("cache not found for key: %v", key)
 	}
+	if err := c.checkExpiration(item); err != nil {
+		return -1, err
+	}
 	return item.counter, nil
 }
 
-// Keys returns a slice of all keys in the cache.
+// Keys returns a slice of all unexpired keys in the cache.
 // Returns an error if the cache is empty.
 func (c *Cacher) Keys() ([]interface{}, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if len(c.cache) == 0 {
-		return nil, errors.New("no keys found")
-	}
-
+	c.purgeBeforeRead()
 	keys := make([]interface{}, 0, len(c.cache))
-	for key := range c.cache {
-		keys = append(keys, key)
+	for key, item := range c.cache {
+		if c.checkExpiration(item) == nil {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no keys found")
 	}
 	return keys, nil
 }
@@ -277,21 +1248,23 @@ func (c *Cacher) Keys() ([]interface{}, error) {
 // Stats returns a formatted string with cache statistics.
 // Useful for debugging and monitoring.
 func (c *Cacher) Stats() string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	policy := "UNKNOWN"
-	switch c.evictionPolicy {
-	case LRU:
-		policy = "LRU"
-	case MRU:
-		policy = "MRU"
-	case LFU:
-		policy = "LFU"
-	case RANDOM:
-		policy = "RANDOM"
+	c.purgeBeforeRead()
+	var lines string
+	items := 0
+	for key, value := range c.cache {
+		if c.checkExpiration(value) != nil {
+			continue
+		}
+		items++
+		lines += fmt.Sprintf("  Key: %v Value: %v TTL: %v Counter: %d Last Used: %v\n",
+			key, c.load(value), value.ttl, value.counter, value.lastUsedAt)
 	}
 
+	policy := policyName(c.evictionPolicy)
+
 	capacity := "unlimited"
 	if c.capacity > 0 {
 		capacity = strconv.Itoa(c.capacity)
@@ -299,140 +1272,697 @@ func (c *Cacher) Stats() string {
 
 	occupancy := 0.0
 	if c.capacity > 0 {
-		occupancy = (float64(len(c.cache)) * 100) / float64(c.capacity)
+		occupancy = (float64(items) * 100) / float64(c.capacity)
+	}
+
+	clearing := "disabled"
+	if c.clearingInterval > 0 {
+		clearing = c.clearingInterval.String()
 	}
 
 	stats := fmt.Sprintf("STATS\n"+
 		"Eviction Policy: %s\n"+
 		"Capacity: %s\n"+
-		"Clearing Interval: %v\n"+
+		"Clearing Interval: %s\n"+
 		"Items: %d\n"+
 		"Occupancy: %.2f%%\n"+
+		"Hits: %d\n"+
+		"Misses: %d\n"+
+		"Evictions: %d\n"+
+		"Expirations: %d\n"+
+		"Expired Purged: %d\n"+
 		"Cache:\n",
-		policy, capacity, c.clearingInterval, len(c.cache), occupancy)
-
-	for key, value := range c.cache {
-		stats += fmt.Sprintf("  Key: %v Value: %v TTL: %v Counter: %d Last Used: %v\n",
-			key, value.value, value.ttl, value.counter, value.lastUsedAt)
-	}
+		policy, capacity, clearing, items, occupancy,
+		c.counters.hits, c.counters.misses, c.counters.evictions, c.counters.expirations, c.counters.purged)
 
-	return stats
+	return stats + lines
 }
 
 // Close stops the background clearing goroutine.
 // Should be called when the cache is no longer needed.
+// Items queued for the overflow store are written before it returns.
+// See Shutdown for a graceful alternative.
 func (c *Cacher) Close() {
 	c.cancel()
+	c.dispatcher.close()
+	if c.spillDone != nil {
+		<-c.spillDone
+	}
+}
+
+// Shutdown stops the background goroutines like Close, then saves a snapshot
+// to SnapshotPath, flushes the write-behind queue and the items if configured,
+// and waits for queued callbacks to finish. Returns the context error if ctx is done first,
+// or the snapshot error. Calling Shutdown again does nothing.
+// The cache should not be used after Shutdown.
+func (c *Cacher) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	if c.shutdown {
+		c.mu.Unlock()
+		return nil
+	}
+	c.shutdown = true
+	c.mu.Unlock()
+
+	c.cancel()
+	if c.spillDone != nil {
+		<-c.spillDone
+	}
+
+	var err error
+	if c.snapshotPath != "" {
+		if err = c.snapshot(); err != nil {
+			c.logger.Warn("cache snapshot failed", "path", c.snapshotPath, "error", err)
+		}
+	}
+	if flushErr := c.Flush(); flushErr != nil {
+		c.logger.Warn("cache write-behind flush failed", "error", flushErr)
+	}
+	if c.flushOnShutdown {
+		c.flush()
+	}
+
+	c.dispatcher.close()
+	if waitErr := c.dispatcher.wait(ctx); waitErr != nil {
+		return waitErr
+	}
+	return err
+}
+
+// flush removes all items, reporting them to the callbacks.
+// Items in the overflow store are kept.
+func (c *Cacher) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, item := range c.cache {
+		expired := c.checkExpiration(item) != nil
+		c.removeKey(key)
+		if expired {
+			c.notifyExpired(key, item)
+		} else {
+			c.notifyEvicted(key, item)
+		}
+	}
+	c.checkWatermarks()
+}
+
+// store sets an item on behalf of a caller and reports whether it was stored.
+// Keys with a tombstone are ignored, and new keys must first pass the doorkeeper.
+// Items over the size limits are rejected and the older value of the key is removed.
+func (c *Cacher) store(key interface{}, item cache) (bool, error) {
+	if err := c.checkLimits(key, item); err != nil {
+		c.reject(key, err)
+		return false, err
+	}
+	if len(c.tombstones) > 0 && c.buried(key) {
+		return false, nil
+	}
+	c.dropSpilled(key)
+	if _, ok := c.cache[key]; !ok && !c.admit(key) {
+		return false, nil
+	}
+	c.set(key, item)
+	return true, nil
+}
+
+// set stores an item. An existing item with the same key is replaced in place
+// and counts as a use: it is moved to the front of the access order, marked as
+// referenced for CLOCK and keeps its access history for LRUK. Otherwise, if capacity
+// is reached, expired items are removed first, and another item is evicted only if none had expired.
+func (c *Cacher) set(key interface{}, item cache) {
+	if len(c.deps.links) > 0 {
+		// The new value drops the links of the old one and outdates the keys derived from it
+		c.unlink(key)
+		c.invalidateDependents(key)
+	}
+
+	var history []time.Time
+	if old, ok := c.cache[key]; ok {
+		if old.protected {
+			c.protectedCount--
+		}
+		if !old.pinned {
+			c.trackPriority(old.priority, -1)
+		}
+		item.pinned = old.pinned
+		item.element = old.element
+		item.referenced = true
+		history = old.history
+		if c.historyDepth > 0 {
+			item.previous = c.previousOf(old)
+		}
+		c.keys.MoveToFront(item.element)
+	} else {
+		if c.capacity > 0 && len(c.cache) >= c.capacity {
+			if purged := c.removeExpired(); purged > 0 {
+				c.counters.purged += uint64(purged)
+			} else {
+				c.evict()
+			}
+		}
+		item.element = c.keys.PushFront(key)
+	}
+
+	if !item.pinned {
+		c.trackPriority(item.priority, 1)
+	}
+	if c.evictionPolicy == LRUK {
+		item.history = c.recordAccess(history, item.lastUsedAt)
+	}
+	if c.evictionPolicy == GDSF {
+		item.size = max(sizeOf(item.value), 1)
+		item.clock = c.gdsfClock
+	}
+	item.version = c.nextVersion()
+	c.cache[key] = item
+	c.logSet(key, item)
+	c.reindex(key, item)
+	c.trackExpiry(key)
+	c.checkWatermarks()
+	c.wake(key)
+}
+
+// newItem creates an item for a newly set value.
+func (c *Cacher) newItem(value interface{}, ttl time.Duration, priority int) cache {
+	ttl = c.resolveTTL(ttl)
+	now := c.now()
+	item := cache{
+		ttl:        c.jitter(ttl),
+		counter:    1,
+		createdAt:  now,
+		lastUsedAt: now,
+		priority:   priority,
+	}
+	return c.pack(item, value)
+}
+
+// resolveTTL replaces DefaultExpiration with the cache's DefaultTTL.
+func (c *Cacher) resolveTTL(ttl time.Duration) time.Duration {
+	if ttl == DefaultExpiration {
+		return time.Duration(c.defaultTTL.Load())
+	}
+	return ttl
+}
+
+// pack stores a value in an item, encoding and compressing it if configured.
+func (c *Cacher) pack(item cache, value interface{}) cache {
+	value, item.encoded = c.encode(value)
+	item.value, item.compressed = compress(value, c.compressAbove)
+	return item
+}
+
+// jitter randomizes a TTL by up to ±ttlJitter of its value.
+func (c *Cacher) jitter(ttl time.Duration) time.Duration {
+	if c.ttlJitter == 0 || ttl <= 0 {
+		return ttl
+	}
+	delta := (rand.Float64()*2 - 1) * c.ttlJitter * float64(ttl)
+	return max(ttl+time.Duration(delta), 1)
 }
 
 // update increments the access counter and updates lastUsedAt.
 func (c *Cacher) update(key interface{}, value cache) {
 	value.counter++
-	value.lastUsedAt = time.Now()
+	value.lastUsedAt = c.now()
+	value.referenced = true
+	if c.evictionPolicy == LRUK {
+		value.history = c.recordAccess(value.history, value.lastUsedAt)
+	}
+	if c.evictionPolicy == GDSF {
+		value.clock = c.gdsfClock
+	}
 	c.cache[key] = value
+	c.trackExpiry(key)
 }
 
 // startClearing runs a background loop to remove expired items.
-func (c *Cacher) startClearing() {
-	ticker := time.NewTicker(c.clearingInterval)
+func (c *Cacher) startClearing(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
 			c.mu.Lock()
-			c.processClearing()
+			interval := c.processClearing()
 			c.mu.Unlock()
-		case <-c.ctx.Done():
+			ticker.Reset(interval)
+		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-// processClearing removes all expired items from the cache.
-func (c *Cacher) processClearing() {
-	now := time.Now()
-	for key, value := range c.cache {
-		if value.ttl != 0 && value.lastUsedAt.Add(value.ttl).Before(now) {
-			c.removeKey(key)
-		}
+// restartClearing stops the running cleaner, if any, and starts a new one
+// with the current clearing interval, unless it is negative.
+// Must be called with c.mu held or before the cache is shared.
+func (c *Cacher) restartClearing() {
+	if c.stopClearing != nil {
+		c.stopClearing()
+		c.stopClearing = nil
+	}
+	if c.clearingInterval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.stopClearing = cancel
+	go c.startClearing(ctx, c.clearingInterval)
+}
+
+// processClearing removes all expired items from the cache
+// and returns the interval until the next run.
+func (c *Cacher) processClearing() time.Duration {
+	items := len(c.cache)
+	removed := c.removeExpired()
+	c.removeTombstones()
+	c.checkWatermarks()
+
+	if c.adaptiveClearing {
+		c.clearingInterval = c.adaptInterval(removed, items)
 	}
+	c.logger.Debug("cache cleaner run",
+		"removed", removed, "items", len(c.cache), "next", c.clearingInterval)
+	return c.clearingInterval
+}
+
+// adaptInterval returns the next clearing interval based on how many of the
+// items expired since the last run.
+func (c *Cacher) adaptInterval(removed, items int) time.Duration {
+	interval := c.clearingInterval
+	switch {
+	case removed == 0:
+		interval *= 2
+	case float64(removed) >= float64(items)*adaptiveSpeedUpRatio:
+		interval /= 2
+	}
+	return min(max(interval, c.minClearing), c.maxClearing)
 }
 
 // removeKey removes a key from both the map and the list.
 func (c *Cacher) removeKey(key interface{}) {
 	e := c.getKeyNote(key)
 	if e != nil {
+		if e == c.hand {
+			c.hand = e.Prev()
+		}
 		c.keys.Remove(e)
 	}
+	if item, ok := c.cache[key]; ok {
+		c.untrackExpiry(key)
+		if !item.pinned {
+			c.trackPriority(item.priority, -1)
+		}
+		if item.protected {
+			c.protectedCount--
+		}
+		c.unindex(key)
+		c.log(walRecord{Op: walDelete, Key: key})
+	}
 	delete(c.cache, key)
+	if len(c.deps.links) > 0 {
+		c.unlink(key)
+		c.invalidateDependents(key)
+	}
+}
+
+// trackPriority adjusts the number of items with the given priority.
+func (c *Cacher) trackPriority(priority, delta int) {
+	c.priorities[priority] += delta
+	if c.priorities[priority] <= 0 {
+		delete(c.priorities, priority)
+	}
 }
 
-// evict removes one item based on the current policy.
+// lowestPriority returns the lowest priority among unpinned items.
+func (c *Cacher) lowestPriority() int {
+	first := true
+	lowest := 0
+	for priority := range c.priorities {
+		if first || priority < lowest {
+			lowest = priority
+			first = false
+		}
+	}
+	return lowest
+}
+
+// evict removes a batch of items based on the current policy.
 func (c *Cacher) evict() {
+	for i := 0; i < c.evictBatch(); i++ {
+		key, ok := c.victim()
+		if !ok {
+			return
+		}
+		c.evictKey(key)
+	}
+}
+
+// evictBatch returns how many items to evict when capacity is reached.
+func (c *Cacher) evictBatch() int {
+	n := int(c.evictPercent * float64(c.capacity) / 100)
+	return max(n, c.evictSize, 1)
+}
+
+// victim returns the key the current policy would evict next.
+// Only unpinned items with the lowest priority in the cache are candidates.
+func (c *Cacher) victim() (interface{}, bool) {
+	if len(c.priorities) == 0 {
+		return nil, false
+	}
+
+	lowest := c.lowestPriority()
+	candidate := func(key interface{}) bool {
+		item, ok := c.cache[key]
+		return ok && !item.pinned && item.priority == lowest
+	}
+
+	if key, ok := c.victimExpiring(candidate); ok {
+		return key, true
+	}
 	switch c.evictionPolicy {
 	case LRU:
-		c.evictLRU()
+		return c.victimLRU(candidate)
 	case MRU:
-		c.evictMRU()
+		return c.victimMRU(candidate)
 	case LFU:
-		c.evictLFU()
+		return c.victimLFU(candidate)
 	case RANDOM:
-		c.evictRANDOM()
+		return c.victimRANDOM(candidate)
+	case CLOCK:
+		return c.victimCLOCK(candidate)
+	case SLRU:
+		return c.victimSLRU(candidate)
+	case LRUK:
+		return c.victimLRUK(candidate)
+	case WRANDOM:
+		return c.victimWRANDOM(candidate)
+	case GDSF:
+		return c.victimGDSF(candidate)
+	case HYPERBOLIC:
+		return c.victimHyperbolic(candidate)
+	case LIFO:
+		return c.victimLIFO(candidate)
 	}
+	return nil, false
 }
 
-// evictLRU removes the least recently used item (from the back of the list).
-func (c *Cacher) evictLRU() {
-	if e := c.keys.Back(); e != nil {
-		c.removeKey(e.Value)
+// victimLRU returns the least recently used candidate (from the back of the list).
+func (c *Cacher) victimLRU(candidate func(interface{}) bool) (interface{}, bool) {
+	for e := c.keys.Back(); e != nil; e = e.Prev() {
+		if candidate(e.Value) {
+			return e.Value, true
+		}
 	}
+	return nil, false
 }
 
-// evictMRU removes the most recently used item (from the front of the list).
-func (c *Cacher) evictMRU() {
-	if e := c.keys.Front(); e != nil {
-		c.removeKey(e.Value)
+// victimMRU returns the most recently used candidate (from the front of the list).
+func (c *Cacher) victimMRU(candidate func(interface{}) bool) (interface{}, bool) {
+	for e := c.keys.Front(); e != nil; e = e.Next() {
+		if candidate(e.Value) {
+			return e.Value, true
+		}
 	}
+	return nil, false
 }
 
-// evictLFU removes the least frequently used item.
-func (c *Cacher) evictLFU() {
+// victimLFU returns the least frequently used candidate, or the least frequently
+// used of lfuSample random candidates if sampling is enabled.
+func (c *Cacher) victimLFU(candidate func(interface{}) bool) (interface{}, bool) {
+	if c.lfuSample > 0 {
+		if key, ok := c.sampleLFU(candidate); ok {
+			return key, true
+		}
+	}
+
 	var minKey interface{}
 	var minCount = -1
 	for key, value := range c.cache {
+		if !candidate(key) {
+			continue
+		}
 		if minCount == -1 || value.counter < minCount {
 			minKey = key
 			minCount = value.counter
 		}
 	}
-	if minKey != nil {
-		c.removeKey(minKey)
+	return minKey, minCount != -1
+}
+
+// sampleLFU returns the least frequently used of lfuSample candidates drawn uniformly
+// from the key list (with replacement). It gives up after drawing 2*lfuSample keys
+// that are not candidates, e.g. pinned ones, so victimLFU falls back to a full scan.
+func (c *Cacher) sampleLFU(candidate func(interface{}) bool) (interface{}, bool) {
+	var minKey interface{}
+	var minCount = -1
+	for sampled, misses := 0, 0; sampled < c.lfuSample && misses < 2*c.lfuSample; {
+		e := c.keys.Random()
+		if e == nil {
+			break
+		}
+		if !candidate(e.Value) {
+			misses++
+			continue
+		}
+		sampled++
+		if count := c.cache[e.Value].counter; minCount == -1 || count < minCount {
+			minKey = e.Value
+			minCount = count
+		}
 	}
+	return minKey, minCount != -1
 }
 
-// evictRANDOM removes a random item (the first one iterated).
-func (c *Cacher) evictRANDOM() {
+// victimRANDOM returns a random candidate (the first one iterated).
+func (c *Cacher) victimRANDOM(candidate func(interface{}) bool) (interface{}, bool) {
 	for key := range c.cache {
-		c.removeKey(key)
-		break
+		if candidate(key) {
+			return key, true
+		}
 	}
+	return nil, false
 }
 
-// checkExpiration returns an error if the item has expired.
-func checkExpiration(value cache) error {
-	if value.ttl != 0 && value.lastUsedAt.Add(value.ttl).Before(time.Now()) {
-		return errors.New("TTL expired")
+// victimCLOCK sweeps the clock hand from the oldest item towards the newest,
+// giving referenced candidates a second chance by clearing their bit.
+// Returns the first unreferenced candidate.
+func (c *Cacher) victimCLOCK(candidate func(interface{}) bool) (interface{}, bool) {
+	for i := 0; i <= 2*c.keys.Len(); i++ {
+		if c.hand == nil {
+			c.hand = c.keys.Back()
+			if c.hand == nil {
+				return nil, false
+			}
+		}
+
+		key := c.hand.Value
+		c.hand = c.hand.Prev()
+		if !candidate(key) {
+			continue
+		}
+
+		item := c.cache[key]
+		if !item.referenced {
+			return key, true
+		}
+		item.referenced = false
+		c.cache[key] = item
 	}
-	return nil
+	return nil, false
 }
 
-// getKeyNote finds the list element for a key.
-func (c *Cacher) getKeyNote(key interface{}) *list.Element {
-	for e := c.keys.Front(); e != nil; e = e.Next() {
-		if e.Value == key {
-			return e
+// victimSLRU returns the least recently used candidate of the probationary segment,
+// or of the protected segment if there are no probationary candidates.
+func (c *Cacher) victimSLRU(candidate func(interface{}) bool) (interface{}, bool) {
+	key, ok := c.victimLRU(func(key interface{}) bool {
+		return candidate(key) && !c.cache[key].protected
+	})
+	if ok {
+		return key, true
+	}
+	return c.victimLRU(candidate)
+}
+
+// victimWRANDOM returns a random candidate, chosen with a probability inversely
+// proportional to its access count, in a single pass (weighted reservoir sampling).
+func (c *Cacher) victimWRANDOM(candidate func(interface{}) bool) (interface{}, bool) {
+	var victim interface{}
+	var total float64
+	for key, item := range c.cache {
+		if !candidate(key) {
+			continue
+		}
+		weight := 1 / float64(max(item.counter, 1))
+		total += weight
+		if rand.Float64()*total < weight {
+			victim = key
+		}
+	}
+	return victim, total > 0
+}
+
+// victimGDSF returns the candidate with the lowest Greedy-Dual-Size-Frequency priority.
+func (c *Cacher) victimGDSF(candidate func(interface{}) bool) (interface{}, bool) {
+	var victim interface{}
+	var lowest float64
+	found := false
+	for key, item := range c.cache {
+		if !candidate(key) {
+			continue
+		}
+		if priority := gdsfPriority(item); !found || priority < lowest {
+			victim, lowest, found = key, priority, true
+		}
+	}
+	return victim, found
+}
+
+// gdsfPriority returns the GDSF priority of an item: its access count per byte
+// plus the cache clock at its last access. The clock advances to the priority
+// of each evicted item, so items not used for a while age out without decaying counters.
+// Items set before the policy was switched to GDSF are measured on every call.
+func gdsfPriority(item cache) float64 {
+	size := item.size
+	if size == 0 {
+		size = max(sizeOf(item.value), 1)
+	}
+	return item.clock + float64(item.counter)/float64(size)
+}
+
+// victimHyperbolic returns the candidate with the fewest accesses per second since
+// it was set. Unlike LFU, counts need no aging: a once popular item loses priority
+// as its time in the cache grows.
+func (c *Cacher) victimHyperbolic(candidate func(interface{}) bool) (interface{}, bool) {
+	now := c.now()
+	var victim interface{}
+	var lowest float64
+	found := false
+	for key, item := range c.cache {
+		if !candidate(key) {
+			continue
+		}
+		age := max(now.Sub(item.createdAt), time.Millisecond)
+		if rate := float64(item.counter) / age.Seconds(); !found || rate < lowest {
+			victim, lowest, found = key, rate, true
+		}
+	}
+	return victim, found
+}
+
+// victimLIFO returns the most recently set candidate, so the items set first
+// stay in the cache however they are used. Setting an existing key counts as setting it anew.
+func (c *Cacher) victimLIFO(candidate func(interface{}) bool) (interface{}, bool) {
+	var victim interface{}
+	var newest time.Time
+	found := false
+	for key, item := range c.cache {
+		if !candidate(key) {
+			continue
 		}
+		if !found || item.createdAt.After(newest) {
+			victim, newest, found = key, item.createdAt, true
+		}
+	}
+	return victim, found
+}
+
+// promote moves an accessed item to the protected segment of SLRU.
+// If the segment is full, its least recently used item is moved back to probation.
+func (c *Cacher) promote(key interface{}) {
+	item := c.cache[key]
+	if item.protected {
+		return
+	}
+	item.protected = true
+	c.cache[key] = item
+	c.protectedCount++
+
+	limit := int(c.protectedRatio * float64(c.capacity))
+	if c.capacity <= 0 || c.protectedCount <= limit {
+		return
+	}
+
+	for e := c.keys.Back(); e != nil; e = e.Prev() {
+		demoted, ok := c.cache[e.Value]
+		if !ok || !demoted.protected {
+			continue
+		}
+		demoted.protected = false
+		c.cache[e.Value] = demoted
+		c.protectedCount--
+		c.keys.MoveToFront(e)
+		return
+	}
+}
+
+// victimLRUK returns the candidate whose K-th most recent access is the oldest.
+// Candidates with fewer than K accesses go first, least recently used among them.
+func (c *Cacher) victimLRUK(candidate func(interface{}) bool) (interface{}, bool) {
+	var victim interface{}
+	var oldest time.Time
+	found := false
+	for e := c.keys.Back(); e != nil; e = e.Prev() {
+		if !candidate(e.Value) {
+			continue
+		}
+		history := c.cache[e.Value].history
+		if len(history) < c.lruK {
+			return e.Value, true
+		}
+		if !found || history[0].Before(oldest) {
+			victim, oldest, found = e.Value, history[0], true
+		}
+	}
+	return victim, found
+}
+
+// recordAccess appends an access time, keeping only the last K.
+func (c *Cacher) recordAccess(history []time.Time, at time.Time) []time.Time {
+	if len(history) < c.lruK {
+		return append(history, at)
+	}
+	copy(history, history[1:])
+	history[len(history)-1] = at
+	return history
+}
+
+// evictKey removes a key chosen by the eviction policy,
+// moving it to the overflow store if one is configured.
+func (c *Cacher) evictKey(key interface{}) {
+	item := c.cache[key]
+	if c.evictionPolicy == GDSF {
+		c.gdsfClock = max(c.gdsfClock, gdsfPriority(item))
+	}
+	c.spill(key, item)
+	c.removeKey(key)
+	c.counters.evictions++
+	if c.ghosts != nil {
+		c.ghosts.add(key)
+	}
+	if c.keyTracker != nil {
+		c.keyTracker.evicted(key)
+	}
+	c.notifyEvicted(key, item)
+	if c.evicted != nil {
+		*c.evicted = append(*c.evicted, key)
+	}
+	c.logger.Debug("cache evicted item",
+		"key", key, "policy", policyName(c.evictionPolicy), "items", len(c.cache), "capacity", c.capacity)
+}
+
+// checkExpiration returns an error if the item has expired.
+func (c *Cacher) checkExpiration(value cache) error {
+	if value.pinned && c.pinnedNoExpire {
+		return nil
+	}
+	if at := c.expiresAt(value); !at.IsZero() && at.Before(c.now()) {
+		return errors.New("TTL expired")
 	}
 	return nil
 }
+
+// getKeyNote returns the list element for a key.
+func (c *Cacher) getKeyNote(key interface{}) *element {
+	return c.cache[key].element
+}
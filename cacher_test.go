@@ -1,6 +1,10 @@
 package cacher
 
 import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -41,6 +45,44 @@ func TestCacher_GetExpired(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCacher_SetExisting(t *testing.T) {
+	cfg := Config{Capacity: 2, EvictionPolicy: LRU}
+	cache := New(cfg)
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+	cache.Set("k1", "v1.1", 5*time.Second) // замена без вытеснения
+
+	assert.Equal(t, 2, cache.keys.Len())
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1", got)
+	_, err = cache.Get("k2")
+	assert.NoError(t, err)
+}
+
+func TestCacher_Update(t *testing.T) {
+	cfg := Config{Capacity: 10}
+	cache := New(cfg)
+
+	cache.SetWithPriority("k1", "v1", 5*time.Second, 3)
+	cache.Get("k1")
+	require.NoError(t, cache.Update("k1", "v2"))
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got)
+
+	ttl, err := cache.GetTTL("k1")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, ttl)
+	counter, err := cache.GetCounter("k1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, counter)
+
+	assert.Error(t, cache.Update("missing", "v"))
+}
+
 func TestCacher_GetAll(t *testing.T) {
 	cfg := Config{Capacity: 10}
 	cache := New(cfg)
@@ -95,6 +137,22 @@ func TestCacher_Capacity(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCacher_CapacityPurgesExpired(t *testing.T) {
+	cfg := Config{Capacity: 2, EvictionPolicy: MRU}
+	cache := New(cfg)
+
+	cache.Set("k1", "v1", 20*time.Millisecond)
+	cache.Set("k2", "v2", 5*time.Second)
+	time.Sleep(30 * time.Millisecond)
+	cache.Set("k3", "v3", 5*time.Second) // удаляется истёкший k1, а не k2 (MRU)
+
+	_, err := cache.Get("k2")
+	assert.NoError(t, err)
+	_, err = cache.Get("k3")
+	assert.NoError(t, err)
+	assert.Contains(t, cache.Stats(), "Expired Purged: 1")
+}
+
 func TestCacher_LRU(t *testing.T) {
 	cfg := Config{Capacity: 2, EvictionPolicy: LRU}
 	cache := New(cfg)
@@ -128,6 +186,34 @@ func TestCacher_MRU(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCacher_UpdateThenEvict(t *testing.T) {
+	tests := []struct {
+		policy  int
+		evicted string
+	}{
+		{LRU, "k2"},   // обновлённый k1 становится самым свежим
+		{MRU, "k1"},   // обновлённый k1 — самый свежий, его и вытесняем
+		{CLOCK, "k2"}, // обновление ставит бит обращения
+		{SLRU, "k2"},
+		{LRUK, "k2"}, // у k1 два обращения, у k2 одно
+	}
+	for _, tt := range tests {
+		t.Run(policyName(tt.policy), func(t *testing.T) {
+			cache := New(Config{Capacity: 2, EvictionPolicy: tt.policy})
+			defer cache.Close()
+
+			cache.Set("k1", "v1", 5*time.Second)
+			cache.Set("k2", "v2", 5*time.Second)
+			cache.Set("k1", "v1.1", 5*time.Second)
+			cache.Set("k3", "v3", 5*time.Second)
+
+			_, err := cache.Get(tt.evicted)
+			assert.Error(t, err)
+			assert.Len(t, cache.GetAll(), 2)
+		})
+	}
+}
+
 func TestCacher_LFU(t *testing.T) {
 	cfg := Config{Capacity: 2, EvictionPolicy: LFU}
 	cache := New(cfg)
@@ -146,6 +232,192 @@ func TestCacher_LFU(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCacher_EvictExpiringWithin(t *testing.T) {
+	cfg := Config{Capacity: 4, EvictionPolicy: LRU, EvictExpiringWithin: time.Minute}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("old", "v", 0)                // самый старый, но без TTL
+	cache.Set("dying", "v", 10*time.Second) // истекает раньше всех
+	cache.Set("later", "v", 30*time.Second) // тоже в окне
+	cache.Set("fresh", "v", 10*time.Minute) // за пределами окна
+	assert.Equal(t, []interface{}{"dying", "later", "old", "fresh"}, cache.NextVictims(4))
+
+	cache.Set("k1", "v", 0)
+	_, err := cache.Get("dying")
+	assert.Error(t, err)
+	cache.Set("k2", "v", 0)
+	_, err = cache.Get("later")
+	assert.Error(t, err)
+
+	// в окне больше нет элементов — работает LRU
+	cache.Set("k3", "v", 0)
+	_, err = cache.Get("old")
+	assert.Error(t, err)
+	_, err = cache.Get("fresh")
+	assert.NoError(t, err)
+}
+
+func TestCacher_WRANDOM(t *testing.T) {
+	cfg := Config{Capacity: 2, EvictionPolicy: WRANDOM}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("hot", "v1", 0)
+	cache.Set("cold", "v2", 0)
+	for i := 0; i < 99; i++ {
+		cache.Get("hot")
+	}
+
+	// вероятность выбора обратно пропорциональна числу обращений: 1/100 против 1
+	cold := 0
+	cache.mu.Lock()
+	for i := 0; i < 1000; i++ {
+		key, ok := cache.victim()
+		require.True(t, ok)
+		if key == "cold" {
+			cold++
+		}
+	}
+	cache.mu.Unlock()
+	assert.Greater(t, cold, 950)
+	assert.Less(t, cold, 1000)
+
+	cache.Set("k3", "v3", 0)
+	assert.Equal(t, 2, cache.Metrics().Items)
+}
+
+func TestCacher_GDSF(t *testing.T) {
+	cfg := Config{Capacity: 3, EvictionPolicy: GDSF}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("blob", make([]byte, 1<<20), 0)
+	cache.Set("small1", "v1", 0)
+	cache.Set("small2", "v2", 0)
+	for i := 0; i < 10; i++ {
+		cache.Get("blob") // частые обращения не спасают большой объект
+	}
+
+	cache.Set("small3", "v3", 0)
+	_, err := cache.Get("blob")
+	assert.Error(t, err)
+	for _, key := range []string{"small1", "small2", "small3"} {
+		_, err := cache.Get(key)
+		assert.NoError(t, err)
+	}
+
+	// часы сдвигаются к приоритету вытесненного, поэтому новые объекты не вытесняются сразу
+	cache.mu.RLock()
+	clock := cache.gdsfClock
+	cache.mu.RUnlock()
+	assert.Greater(t, clock, 0.0)
+
+	for i := 0; i < 5; i++ {
+		cache.Get("small1")
+		cache.Get("small3")
+	}
+	cache.Set("small4", "v4", 0)
+	_, err = cache.Get("small2")
+	assert.Error(t, err)
+	assert.Equal(t, []interface{}{"small4"}, cache.NextVictims(1))
+}
+
+func TestCacher_Hyperbolic(t *testing.T) {
+	cfg := Config{Capacity: 2, EvictionPolicy: HYPERBOLIC}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("old", "v1", 0)
+	cache.Set("new", "v2", 0)
+	for i := 0; i < 50; i++ {
+		cache.Get("old")
+	}
+
+	// 51 обращение за час реже, чем одно за миллисекунды: LFU оставил бы "old"
+	cache.mu.Lock()
+	item := cache.cache["old"]
+	item.createdAt = item.createdAt.Add(-time.Hour)
+	cache.cache["old"] = item
+	cache.mu.Unlock()
+
+	assert.Equal(t, []interface{}{"old", "new"}, cache.NextVictims(2))
+	cache.Set("k3", "v3", 0)
+	_, err := cache.Get("old")
+	assert.Error(t, err)
+	_, err = cache.Get("new")
+	assert.NoError(t, err)
+}
+
+func TestCacher_LIFO(t *testing.T) {
+	cfg := Config{Capacity: 3, EvictionPolicy: LIFO}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("warm1", "v1", 0)
+	cache.Set("warm2", "v2", 0)
+	time.Sleep(time.Millisecond)
+	cache.Set("later1", "v3", 0)
+	time.Sleep(time.Millisecond)
+
+	// новые ключи вытесняют друг друга, прогретые остаются
+	for i := 0; i < 5; i++ {
+		cache.Set(fmt.Sprintf("later%d", i+2), i, 0)
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, []interface{}{"later6"}, cache.NextVictims(1))
+	for _, key := range []string{"warm1", "warm2", "later6"} {
+		_, err := cache.Get(key)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 3, cache.Metrics().Items)
+}
+
+func TestCacher_SampledLFU(t *testing.T) {
+	cfg := Config{Capacity: 100, EvictionPolicy: LFU, LFUSampleSize: 5}
+	cache := New(cfg)
+	defer cache.Close()
+
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i, 0)
+		if i%2 == 0 {
+			for j := 0; j < 10; j++ {
+				cache.Get(i) // чётные ключи — горячие
+			}
+		}
+	}
+	for i := 100; i < 150; i++ {
+		cache.Set(i, i, 0)
+	}
+
+	// из выборки вытесняется наименее используемый, поэтому горячие ключи почти всегда остаются
+	hot := 0
+	for i := 0; i < 100; i += 2 {
+		if _, err := cache.Get(i); err == nil {
+			hot++
+		}
+	}
+	assert.Equal(t, 100, cache.Metrics().Items)
+	assert.Greater(t, hot, 40)
+}
+
+func BenchmarkCacher_LFUEviction(b *testing.B) {
+	for _, sample := range []int{0, 5} {
+		b.Run(fmt.Sprintf("sample=%d", sample), func(b *testing.B) {
+			cache := New(Config{Capacity: 100000, EvictionPolicy: LFU, LFUSampleSize: sample})
+			defer cache.Close()
+			for i := 0; i < 100000; i++ {
+				cache.Set(i, i, 0)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cache.Set(100000+i, i, 0)
+			}
+		})
+	}
+}
+
 func TestCacher_RANDOM(t *testing.T) {
 	cfg := Config{Capacity: 2, EvictionPolicy: RANDOM}
 	cache := New(cfg)
@@ -164,6 +436,150 @@ func TestCacher_RANDOM(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCacher_CLOCK(t *testing.T) {
+	cfg := Config{Capacity: 3, EvictionPolicy: CLOCK}
+	cache := New(cfg)
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+	cache.Set("k3", "v3", 5*time.Second)
+	cache.Get("k1")                      // k1 получает второй шанс
+	cache.Set("k4", "v4", 5*time.Second) // вытесняется k2
+
+	_, err := cache.Get("k2")
+	assert.Error(t, err)
+
+	cache.Set("k5", "v5", 5*time.Second) // k3 не использовался — вытесняется он
+	_, err = cache.Get("k3")
+	assert.Error(t, err)
+	for _, key := range []string{"k1", "k4", "k5"} {
+		_, err = cache.Get(key)
+		assert.NoError(t, err)
+	}
+}
+
+func TestCacher_SLRU(t *testing.T) {
+	cfg := Config{Capacity: 4, EvictionPolicy: SLRU, ProtectedRatio: 0.5}
+	cache := New(cfg)
+
+	cache.Set("hot1", "v", 5*time.Second)
+	cache.Set("hot2", "v", 5*time.Second)
+	cache.Get("hot1") // переходят в защищённый сегмент
+	cache.Get("hot2")
+
+	// Сканирование одноразовыми ключами не вытесняет горячие
+	for i := 0; i < 10; i++ {
+		cache.Set(i, "v", 5*time.Second)
+	}
+
+	_, err := cache.Get("hot1")
+	assert.NoError(t, err)
+	_, err = cache.Get("hot2")
+	assert.NoError(t, err)
+	_, err = cache.Get(9)
+	assert.NoError(t, err) // k9 продвигается, hot1 (LRU защищённого) возвращается на испытательный
+	_, err = cache.Get(0)
+	assert.Error(t, err)
+
+	cache.Set("new", "v", 5*time.Second) // вытесняется 8 — последний на испытательном сегменте
+	_, err = cache.Get(8)
+	assert.Error(t, err)
+	_, err = cache.Get("hot2")
+	assert.NoError(t, err)
+}
+
+func TestCacher_LRUK(t *testing.T) {
+	cfg := Config{Capacity: 3, EvictionPolicy: LRUK, LRUKHistory: 2}
+	cache := New(cfg)
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+	cache.Get("k1")
+	cache.Get("k2")
+	cache.Set("k3", "v3", 5*time.Second) // одно обращение — вытесняется первым
+	cache.Set("k4", "v4", 5*time.Second)
+
+	_, err := cache.Get("k3")
+	assert.Error(t, err)
+
+	cache.Get("k4")
+	cache.Get("k2")
+	cache.Set("k5", "v5", 5*time.Second) // у k1 самое старое второе обращение
+	_, err = cache.Get("k1")
+	assert.Error(t, err)
+	_, err = cache.Get("k2")
+	assert.NoError(t, err)
+	_, err = cache.Get("k4")
+	assert.NoError(t, err)
+}
+
+func TestCacher_SetWithPriority(t *testing.T) {
+	cfg := Config{Capacity: 2, EvictionPolicy: LRU}
+	cache := New(cfg)
+
+	cache.SetWithPriority("config", "v1", 5*time.Second, 10)
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second) // вытесняется k1, хотя config старше
+	cache.Set("k3", "v3", 5*time.Second) // вытесняется k2
+
+	_, err := cache.Get("config")
+	assert.NoError(t, err)
+	_, err = cache.Get("k1")
+	assert.Error(t, err)
+	_, err = cache.Get("k2")
+	assert.Error(t, err)
+
+	// Остались только элементы с высоким приоритетом — вытесняется один из них
+	cache.SetWithPriority("k4", "v4", 5*time.Second, 10)
+	cache.SetWithPriority("k5", "v5", 5*time.Second, 10)
+	_, err = cache.Get("k5")
+	assert.NoError(t, err)
+	assert.Len(t, cache.GetAll(), 2)
+}
+
+func TestCacher_Pin(t *testing.T) {
+	cfg := Config{Capacity: 2, EvictionPolicy: LRU}
+	cache := New(cfg)
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+	require.NoError(t, cache.Pin("k1"))
+	cache.Set("k3", "v3", 5*time.Second) // k1 закреплён, вытесняется k2
+
+	_, err := cache.Get("k1")
+	assert.NoError(t, err)
+	_, err = cache.Get("k2")
+	assert.Error(t, err)
+
+	require.NoError(t, cache.Unpin("k1"))
+	cache.Get("k3")
+	cache.Set("k4", "v4", 5*time.Second) // k1 снова можно вытеснить
+
+	_, err = cache.Get("k1")
+	assert.Error(t, err)
+
+	assert.Error(t, cache.Pin("missing"))
+	assert.Error(t, cache.Unpin("missing"))
+}
+
+func TestCacher_PinnedTTL(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	cache.Set("k1", "v1", 20*time.Millisecond)
+	require.NoError(t, cache.Pin("k1"))
+
+	time.Sleep(30 * time.Millisecond)
+	_, err := cache.Get("k1")
+	assert.Error(t, err)
+
+	cache = New(Config{Capacity: 10, PinnedNeverExpire: true})
+	cache.Set("k1", "v1", 20*time.Millisecond)
+	require.NoError(t, cache.Pin("k1"))
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = cache.Get("k1")
+	assert.NoError(t, err)
+}
+
 func TestCacher_TTLUpdate(t *testing.T) {
 	cfg := Config{Capacity: 10}
 	cache := New(cfg)
@@ -192,6 +608,27 @@ func TestCacher_GetTTL(t *testing.T) {
 	assert.Equal(t, 5*time.Second, ttl)
 }
 
+func TestCacher_TTLJitter(t *testing.T) {
+	cfg := Config{TTLJitter: 0.1}
+	cache := New(cfg)
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		cache.Set(i, "value", 10*time.Second)
+		ttl, err := cache.GetTTL(i)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, ttl, 9*time.Second)
+		assert.LessOrEqual(t, ttl, 11*time.Second)
+		seen[ttl] = true
+	}
+	assert.Greater(t, len(seen), 1)
+
+	cache.Set("forever", "value", 0)
+	ttl, err := cache.GetTTL("forever")
+	require.NoError(t, err)
+	assert.Zero(t, ttl)
+}
+
 func TestCacher_GetCounter(t *testing.T) {
 	cfg := Config{Capacity: 10}
 	cache := New(cfg)
@@ -242,6 +679,20 @@ func TestCacher_Stats(t *testing.T) {
 	assert.Contains(t, stats, "Key: k2 Value: v2")
 }
 
+func TestCacher_DeleteExpired(t *testing.T) {
+	cfg := Config{Capacity: 10, ClearingInterval: time.Hour}
+	cache := New(cfg)
+
+	cache.Set("k1", "v1", 10*time.Millisecond)
+	cache.Set("k2", "v2", 10*time.Millisecond)
+	cache.Set("k3", "v3", 5*time.Second)
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Equal(t, 2, cache.DeleteExpired())
+	assert.Equal(t, 0, cache.DeleteExpired())
+	assert.Len(t, cache.GetAll(), 1)
+}
+
 func TestCacher_SetCapacity(t *testing.T) {
 	cfg := Config{Capacity: 10}
 	cache := New(cfg)
@@ -262,8 +713,248 @@ func TestCacher_SetEvictionPolicy(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "MRU", cache.GetEvictionPolicy())
 
-	err = cache.SetEvictionPolicy(10)
+	err = cache.SetEvictionPolicy(CLOCK)
+	require.NoError(t, err)
+	assert.Equal(t, "CLOCK", cache.GetEvictionPolicy())
+
+	err = cache.SetEvictionPolicy(len(policyNames))
+	assert.Error(t, err)
+}
+
+func TestCacher_SetClearingInterval(t *testing.T) {
+	cfg := Config{ClearingInterval: time.Hour}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 10*time.Millisecond)
+	cache.SetClearingInterval(10 * time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, cache.GetClearingInterval())
+
+	// очиститель перезапущен с новым интервалом
+	assert.Eventually(t, func() bool {
+		return cache.Metrics().Items == 0
+	}, time.Second, 10*time.Millisecond)
+
+	cache.SetClearingInterval(0)
+	assert.Equal(t, 100*time.Second, cache.GetClearingInterval())
+
+	cache.SetClearingInterval(-1)
+	assert.Contains(t, cache.Stats(), "Clearing Interval: disabled")
+}
+
+func TestCacher_DisabledCleaner(t *testing.T) {
+	cfg := Config{Capacity: 10, ClearingInterval: -1}
+	cache := New(cfg)
+
+	assert.Contains(t, cache.Stats(), "Clearing Interval: disabled")
+
+	cache.Set("k1", "v1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := cache.Get("k1") // истёкший элемент удаляется лениво
 	assert.Error(t, err)
+	assert.Empty(t, cache.GetAll())
+}
+
+func TestCacher_AdaptiveClearing(t *testing.T) {
+	cfg := Config{
+		ClearingInterval:    20 * time.Millisecond,
+		AdaptiveClearing:    true,
+		MinClearingInterval: 10 * time.Millisecond,
+		MaxClearingInterval: 80 * time.Millisecond,
+	}
+	cache := New(cfg)
+	defer cache.Close()
+
+	// Ничего не истекает — интервал растёт до максимума
+	assert.Eventually(t, func() bool {
+		return strings.Contains(cache.Stats(), "Clearing Interval: 80ms")
+	}, time.Second, 10*time.Millisecond)
+
+	cache.mu.Lock()
+	assert.Equal(t, 40*time.Millisecond, cache.adaptInterval(5, 10))
+	assert.Equal(t, 80*time.Millisecond, cache.adaptInterval(1, 10))
+	cache.clearingInterval = 10 * time.Millisecond
+	assert.Equal(t, 10*time.Millisecond, cache.adaptInterval(10, 10))
+	cache.mu.Unlock()
+}
+
+func TestCacher_Touch(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1, Capacity: 2, EvictionPolicy: LRU})
+	defer cache.Close()
+
+	cache.Set("session", "data", 30*time.Millisecond)
+	cache.Set("other", "data", 0)
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, cache.Touch("session", 30*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	_, err := cache.Entry("session")
+	require.NoError(t, err)
+
+	// Счётчик не меняется, позиция в LRU тоже
+	counter, err := cache.GetCounter("session")
+	require.NoError(t, err)
+	assert.Equal(t, 1, counter)
+	cache.Set("new", "data", 0)
+	_, err = cache.Get("session")
+	assert.Error(t, err)
+
+	assert.Error(t, cache.Touch("missing", time.Second))
+}
+
+func TestCacher_GetStale(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("key1", "value1", 10*time.Millisecond)
+	value, stale, err := cache.GetStale("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", value)
+	assert.False(t, stale)
+
+	time.Sleep(20 * time.Millisecond)
+	value, stale, err = cache.GetStale("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", value)
+	assert.True(t, stale)
+
+	// После очистки устаревшего значения больше нет
+	cache.DeleteExpired()
+	_, _, err = cache.GetStale("key1")
+	assert.Error(t, err)
+}
+
+func TestCacher_GracePeriod(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1, GracePeriod: 50 * time.Millisecond})
+	defer cache.Close()
+
+	cache.Set("key1", "value1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	// Get не отдаёт истёкшее значение, но и не удаляет его в течение grace-периода
+	_, err := cache.Get("key1")
+	assert.Error(t, err)
+	assert.Equal(t, 0, cache.DeleteExpired())
+	value, stale, err := cache.GetStale("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", value)
+	assert.True(t, stale)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, cache.DeleteExpired())
+	_, _, err = cache.GetStale("key1")
+	assert.Error(t, err)
+}
+
+func TestCacher_SetWithDeadline(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	deadline := time.Now().Add(30 * time.Millisecond)
+	cache.SetWithDeadline("token", "jwt", deadline)
+
+	// Доступ не продлевает срок жизни
+	time.Sleep(20 * time.Millisecond)
+	_, err := cache.Get("token")
+	require.NoError(t, err)
+	info, err := cache.Entry("token")
+	require.NoError(t, err)
+	assert.Equal(t, deadline, info.Deadline)
+	assert.LessOrEqual(t, info.Remaining, 10*time.Millisecond)
+
+	time.Sleep(15 * time.Millisecond)
+	_, err = cache.Get("token")
+	assert.Error(t, err)
+}
+
+func TestCacher_SetWithLimits(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.SetWithLimits("active", "data", 40*time.Millisecond, 100*time.Millisecond)
+	cache.SetWithLimits("idle", "data", 40*time.Millisecond, time.Minute)
+
+	// active читается чаще, чем истекает idle-таймаут, но живёт не дольше maxLifetime
+	for i := 0; i < 3; i++ {
+		time.Sleep(20 * time.Millisecond)
+		_, err := cache.Get("active")
+		require.NoError(t, err)
+	}
+	_, err := cache.Get("idle")
+	assert.Error(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+	_, err = cache.Get("active")
+	assert.Error(t, err)
+}
+
+func TestCacher_ExpireAt(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("key1", "value1", time.Minute)
+	cache.Set("key2", "value2", time.Minute)
+	require.NoError(t, cache.ExpireAt("key1", time.Now().Add(10*time.Millisecond)))
+	require.NoError(t, cache.ExpireAt("key2", time.Now().Add(time.Hour)))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, cache.DeleteExpired())
+	_, err := cache.Get("key1")
+	assert.Error(t, err)
+
+	// Срабатывает то, что наступит раньше — здесь TTL
+	info, err := cache.Entry("key2")
+	require.NoError(t, err)
+	assert.InDelta(t, float64(time.Minute), float64(info.Remaining), float64(time.Second))
+
+	assert.Error(t, cache.ExpireAt("missing", time.Now()))
+}
+
+func TestCacher_KeyFunc(t *testing.T) {
+	type query struct {
+		Table string
+		IDs   []int
+	}
+	cfg := Config{
+		ClearingInterval: -1,
+		KeyFunc:          func(key interface{}) string { return fmt.Sprintf("%#v", key) },
+	}
+	cache := New(cfg)
+	defer cache.Close()
+
+	// Без KeyFunc такой ключ вызвал бы панику
+	cache.Set(query{"users", []int{1, 2}}, "rows", 0)
+	got, err := cache.Get(query{"users", []int{1, 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "rows", got)
+
+	_, err = cache.Get(query{"users", []int{1}})
+	assert.Error(t, err)
+
+	require.NoError(t, cache.Delete(query{"users", []int{1, 2}}))
+	_, err = cache.Get(query{"users", []int{1, 2}})
+	assert.Error(t, err)
+}
+
+func TestCacher_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := Config{
+		Capacity:         1,
+		ClearingInterval: -1,
+		Logger:           slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("key1", "value1", 0)
+	cache.Set("key2", "value2", 0)
+	require.NoError(t, cache.SetCapacity(2))
+	require.NoError(t, cache.SetEvictionPolicy(LFU))
+
+	out := buf.String()
+	assert.Contains(t, out, `msg="cache evicted item" key=key1 policy=LRU`)
+	assert.Contains(t, out, `msg="cache capacity changed" from=1 to=2`)
+	assert.Contains(t, out, `msg="cache eviction policy changed" from=LRU to=LFU`)
 }
 
 func TestCacher_Close(t *testing.T) {
@@ -275,3 +966,140 @@ func TestCacher_Close(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 	// Нет паники — хорошо
 }
+
+func TestCacher_CoarseClock(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1, CoarseClock: 20 * time.Millisecond})
+	defer cache.Close()
+
+	cache.Set("key1", "value1", 10*time.Millisecond)
+
+	// Время обновляется только раз в 20ms, поэтому TTL истекает не раньше следующего тика
+	assert.Eventually(t, func() bool {
+		_, err := cache.Get("key1")
+		return err != nil
+	}, time.Second, 5*time.Millisecond)
+
+	cache.mu.RLock()
+	now := cache.now()
+	cache.mu.RUnlock()
+	assert.WithinDuration(t, time.Now(), now, 50*time.Millisecond)
+
+	// Записи в пределах одного тика получают одинаковое время
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 0)
+	a, err := cache.Entry("a")
+	require.NoError(t, err)
+	b, err := cache.Entry("b")
+	require.NoError(t, err)
+	assert.Equal(t, a.CreatedAt, b.CreatedAt)
+}
+
+func TestCacher_Items(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1, CompressionThreshold: 8})
+	defer cache.Close()
+
+	cache.Set("key1", "value1", 0)
+	cache.Set("key2", strings.Repeat("x", 100), 0) // хранится сжатым
+	cache.Set("expired", "value", time.Millisecond)
+	cache.SetNegative("missing", 0)
+	time.Sleep(5 * time.Millisecond)
+
+	assert.Equal(t, map[interface{}]interface{}{
+		"key1": "value1",
+		"key2": strings.Repeat("x", 100),
+	}, cache.Items())
+}
+
+func TestCacher_ReadsSkipExpired(t *testing.T) {
+	for _, purge := range []bool{false, true} {
+		cache := New(Config{ClearingInterval: -1, Capacity: 10, PurgeOnRead: purge})
+
+		cache.Set("live", "value", 0)
+		cache.Set("dead", "value", time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+
+		assert.Equal(t, []interface{}{"value"}, cache.GetAll())
+		keys, err := cache.Keys()
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"live"}, keys)
+		_, err = cache.GetCounter("dead")
+		assert.Error(t, err)
+		_, err = cache.GetTTL("dead")
+		assert.Error(t, err)
+
+		stats := cache.Stats()
+		assert.Contains(t, stats, "Items: 1\n")
+		assert.NotContains(t, stats, "Key: dead")
+
+		// Без PurgeOnRead запись остаётся до очистки
+		cache.mu.RLock()
+		_, stored := cache.cache["dead"]
+		cache.mu.RUnlock()
+		assert.Equal(t, !purge, stored)
+		cache.Close()
+	}
+}
+
+func TestCacher_EvictBatch(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1, Capacity: 20, EvictBatchPercent: 10, EvictionPolicy: LRU})
+	defer cache.Close()
+
+	for i := 0; i < 20; i++ {
+		cache.Set(i, i, 0)
+	}
+	// 10% от 20 — освобождается сразу два места
+	cache.Set(20, 20, 0)
+	assert.Equal(t, 19, len(cache.GetAll()))
+	assert.Equal(t, uint64(2), cache.Metrics().Evictions)
+	_, err := cache.Get(1)
+	assert.Error(t, err)
+
+	cache.Set(21, 21, 0)
+	assert.Equal(t, uint64(2), cache.Metrics().Evictions)
+
+	sized := New(Config{ClearingInterval: -1, Capacity: 5, EvictBatchSize: 3})
+	defer sized.Close()
+	for i := 0; i < 6; i++ {
+		sized.Set(i, i, 0)
+	}
+	assert.Equal(t, 3, len(sized.GetAll()))
+}
+
+func TestCacher_SetEResult(t *testing.T) {
+	cache := New(Config{Capacity: 2, EvictionPolicy: LRU})
+	defer cache.Close()
+
+	res, err := cache.SetE("a", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, SetResult{Stored: true}, res)
+
+	res, err = cache.SetE("a", 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, SetResult{Stored: true, Replaced: true}, res)
+
+	cache.Set("b", 1, time.Minute)
+	res, err = cache.SetE("c", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, SetResult{Stored: true, Evicted: []interface{}{"a"}}, res)
+
+	// Истёкшее значение не считается перезаписанным
+	cache.Set("d", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	res, err = cache.SetE("d", 2, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, res.Replaced)
+}
+
+func TestCacher_SetEDoorkeeper(t *testing.T) {
+	cache := New(Config{}, WithDoorkeeper(100, 0.01))
+	defer cache.Close()
+
+	// Новый ключ сохраняется только со второй записи
+	res, err := cache.SetE("k", 1, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, res.Stored)
+
+	res, err = cache.SetE("k", 1, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, res.Stored)
+}
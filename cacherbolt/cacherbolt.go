@@ -0,0 +1,242 @@
+// Package cacherbolt is a cacher.Backend that stores items on disk with bbolt.
+//
+// Used as the overflow store of a cache, it lets the cache hold more items than
+// fit in memory: the in-memory cache keeps the hot set, evicted items move to disk
+// and are restored on Get. Items on disk survive restarts, since the store lists
+// its keys to the cache when it is created.
+package cacherbolt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/danRulev/cacher"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	itemsBucket = []byte("items") // Encoded key -> entry
+	orderBucket = []byte("order") // Write sequence -> encoded key, oldest first
+)
+
+// entry is the on-disk representation of an item.
+type entry struct {
+	Key       interface{}
+	Value     interface{}
+	ExpiresAt time.Time // Zero if the item never expires
+	Seq       uint64    // Write sequence, for removing the oldest items first
+}
+
+// Store is a cacher.Backend backed by a bbolt database file.
+// Keys and values of custom types must be registered with gob.Register.
+type Store struct {
+	db       *bolt.DB
+	maxBytes int64
+	size     atomic.Int64 // Bytes of stored entries
+	writeMu  sync.Mutex   // Serializes writes with their size changes
+}
+
+// Open opens or creates a store in the database file at path. If maxBytes is positive,
+// the oldest items are removed when the stored entries take more than maxBytes.
+// The database file itself does not shrink when items are removed.
+func Open(path string, maxBytes int64) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open disk store: %w", err)
+	}
+
+	s := &Store{db: db, maxBytes: maxBytes}
+	err = db.Update(func(tx *bolt.Tx) error {
+		items, err := tx.CreateBucketIfNotExists(itemsBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(orderBucket); err != nil {
+			return err
+		}
+		return items.ForEach(func(_, data []byte) error {
+			s.size.Add(int64(len(data)))
+			return nil
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open disk store: %w", err)
+	}
+	return s, nil
+}
+
+// WithDiskStore opens a Store at path and returns an Option that makes it the
+// overflow store of a cache. Close the Store after closing the cache.
+func WithDiskStore(path string, maxDiskBytes int64) (cacher.Option, *Store, error) {
+	s, err := Open(path, maxDiskBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(cfg *cacher.Config) { cfg.Overflow = s }, s, nil
+}
+
+// Get reads an item. Expired items are removed and reported as missing.
+func (s *Store) Get(key interface{}) (interface{}, time.Duration, bool, error) {
+	var e entry
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(itemsBucket).Get(encodeKey(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return decode(data, &e)
+	})
+	if err != nil || !found {
+		return nil, 0, false, err
+	}
+
+	var ttl time.Duration
+	if !e.ExpiresAt.IsZero() {
+		ttl = time.Until(e.ExpiresAt)
+		if ttl <= 0 {
+			return nil, 0, false, s.Delete(key)
+		}
+	}
+	return e.Value, ttl, true, nil
+}
+
+// Set writes an item, replacing any previous value, and removes the oldest items
+// if the store grows beyond its limit.
+func (s *Store) Set(key, value interface{}, ttl time.Duration) error {
+	return s.update(func(tx *bolt.Tx, delta *int64) error {
+		items, order := tx.Bucket(itemsBucket), tx.Bucket(orderBucket)
+		id := encodeKey(key)
+		freed, err := s.remove(items, order, id)
+		if err != nil {
+			return err
+		}
+		*delta -= freed
+
+		seq, err := order.NextSequence()
+		if err != nil {
+			return err
+		}
+		e := entry{Key: key, Value: value, Seq: seq}
+		if ttl != 0 {
+			e.ExpiresAt = time.Now().Add(ttl)
+		}
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(&e); err != nil {
+			return fmt.Errorf("encode %v: %w", key, err)
+		}
+		if err := items.Put(id, buf.Bytes()); err != nil {
+			return err
+		}
+		if err := order.Put(encodeSeq(seq), id); err != nil {
+			return err
+		}
+		*delta += int64(buf.Len())
+
+		for s.maxBytes > 0 && s.size.Load()+*delta > s.maxBytes {
+			_, oldest := order.Cursor().First()
+			if oldest == nil {
+				break
+			}
+			freed, err := s.remove(items, order, oldest)
+			if err != nil {
+				return err
+			}
+			*delta -= freed
+		}
+		return nil
+	})
+}
+
+// Delete removes an item. Deleting a missing key is not an error.
+func (s *Store) Delete(key interface{}) error {
+	return s.update(func(tx *bolt.Tx, delta *int64) error {
+		freed, err := s.remove(tx.Bucket(itemsBucket), tx.Bucket(orderBucket), encodeKey(key))
+		*delta -= freed
+		return err
+	})
+}
+
+// update runs fn in a write transaction. The size change fn reports in delta
+// is applied only once the transaction has committed.
+func (s *Store) update(fn func(tx *bolt.Tx, delta *int64) error) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var delta int64
+	if err := s.db.Update(func(tx *bolt.Tx) error { return fn(tx, &delta) }); err != nil {
+		return err
+	}
+	s.size.Add(delta)
+	return nil
+}
+
+// Keys returns the keys of all unexpired items. The cache calls it when it is created,
+// so items written before a restart can be restored.
+func (s *Store) Keys() ([]interface{}, error) {
+	var keys []interface{}
+	now := time.Now()
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(itemsBucket).ForEach(func(_, data []byte) error {
+			var e entry
+			if err := decode(data, &e); err != nil {
+				return err
+			}
+			if e.ExpiresAt.IsZero() || e.ExpiresAt.After(now) {
+				keys = append(keys, e.Key)
+			}
+			return nil
+		})
+	})
+	return keys, err
+}
+
+// Size returns the number of bytes taken by the stored items.
+func (s *Store) Size() int64 {
+	return s.size.Load()
+}
+
+// Close closes the database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// remove deletes an item by its encoded key from both buckets and returns
+// the bytes it took. Must be called in a write transaction.
+func (s *Store) remove(items, order *bolt.Bucket, id []byte) (int64, error) {
+	data := items.Get(id)
+	if data == nil {
+		return 0, nil
+	}
+	var e entry
+	if err := decode(data, &e); err != nil {
+		return 0, err
+	}
+	size := int64(len(data))
+	if err := order.Delete(encodeSeq(e.Seq)); err != nil {
+		return 0, err
+	}
+	return size, items.Delete(id)
+}
+
+// encodeKey returns the database key for a cache key.
+func encodeKey(key interface{}) []byte {
+	return fmt.Appendf(nil, "%T:%v", key, key)
+}
+
+// encodeSeq encodes a write sequence so that database keys sort in write order.
+func encodeSeq(seq uint64) []byte {
+	return fmt.Appendf(nil, "%020d", seq)
+}
+
+func decode(data []byte, e *entry) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(e); err != nil {
+		return fmt.Errorf("decode disk store entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cacherbolt
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_SetGetDelete(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "cache.db"), 0)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Set("k1", "v1", 0))
+	require.NoError(t, s.Set(2, []byte("v2"), time.Hour))
+
+	value, ttl, ok, err := s.Get("k1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", value)
+	assert.Zero(t, ttl)
+
+	value, ttl, ok, err = s.Get(2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v2"), value)
+	assert.InDelta(t, float64(time.Hour), float64(ttl), float64(time.Second))
+
+	require.NoError(t, s.Delete("k1"))
+	require.NoError(t, s.Delete("missing"))
+	_, _, ok, err = s.Get("k1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// истёкшие элементы удаляются при чтении
+	require.NoError(t, s.Set("short", "v", time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, _, ok, err = s.Get("short")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	keys, err := s.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{2}, keys)
+}
+
+func TestStore_MaxBytes(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "cache.db"), 0)
+	require.NoError(t, err)
+	require.NoError(t, s.Set("probe", "v0", 0))
+	entrySize := s.Size()
+	require.NoError(t, s.Close())
+
+	s, err = Open(filepath.Join(t.TempDir(), "cache.db"), 3*entrySize+entrySize/2)
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, s.Set(fmt.Sprintf("key%d", i), fmt.Sprintf("v%d", i), 0))
+	}
+	require.NoError(t, s.Set("key3", "v3", 0)) // перезапись делает ключ самым новым
+	require.NoError(t, s.Set("key6", "v6", 0))
+
+	keys, err := s.Keys()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []interface{}{"key3", "key5", "key6"}, keys)
+	assert.LessOrEqual(t, s.Size(), 3*entrySize+entrySize/2)
+}
+
+func TestStore_SizeAfterFailedWrite(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "cache.db"), 0)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Set("k1", "v1", 0))
+	size := s.Size()
+
+	// Откаченная транзакция не меняет размер
+	assert.Error(t, s.Set("k1", make(chan int), 0))
+	assert.Equal(t, size, s.Size())
+	value, _, ok, err := s.Get("k1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", value)
+}
+
+func TestWithDiskStore_Restart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	opt, s, err := WithDiskStore(path, 0)
+	require.NoError(t, err)
+	cache := cacher.New(cacher.Config{Capacity: 1}, opt)
+	cache.Set("k1", "v1", time.Hour)
+	cache.Set("k2", "v2", time.Hour) // k1 уходит на диск
+	cache.Close()
+	require.NoError(t, s.Close())
+
+	// после перезапуска k1 восстанавливается с диска
+	opt, s, err = WithDiskStore(path, 0)
+	require.NoError(t, err)
+	defer s.Close()
+	cache = cacher.New(cacher.Config{Capacity: 1}, opt)
+	defer cache.Close()
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+	_, err = cache.Get("k2")
+	assert.Error(t, err)
+}
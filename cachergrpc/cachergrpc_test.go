@@ -0,0 +1,94 @@
+package cachergrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) (*cacher.Cacher, *Client) {
+	cache := cacher.New(cacher.Config{Capacity: 10})
+	lis := bufconn.Listen(1 << 20)
+
+	srv := grpc.NewServer()
+	Register(srv, cache)
+	go srv.Serve(lis)
+
+	client, err := Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		client.Close()
+		srv.Stop()
+		cache.Close()
+	})
+	return cache, client
+}
+
+func TestClient_SetGetDelete(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.Set(ctx, "k1", []byte("v1"), time.Second))
+
+	got, err := client.Get(ctx, "k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), got)
+
+	require.NoError(t, client.Delete(ctx, "k1"))
+	_, err = client.Get(ctx, "k1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, client.Delete(ctx, "k1"), ErrNotFound)
+}
+
+func TestClient_SetTTL(t *testing.T) {
+	cache, client := newTestClient(t)
+	ctx := context.Background()
+
+	// TTL меньше миллисекунды округляется вверх, а не превращается в бессрочный
+	require.NoError(t, client.Set(ctx, "k1", []byte("v1"), 500*time.Microsecond))
+	ttl, err := cache.GetTTL("k1")
+	require.NoError(t, err)
+	assert.Equal(t, time.Millisecond, ttl)
+
+	assert.Error(t, client.Set(ctx, "k2", []byte("v2"), cacher.DefaultExpiration))
+	_, err = client.Get(ctx, "k2")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_Stats(t *testing.T) {
+	cache, client := newTestClient(t)
+	cache.Set("k1", "v1", 0)
+
+	stats, err := client.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, stats, "Items: 1")
+}
+
+func TestClient_Metrics(t *testing.T) {
+	cache, client := newTestClient(t)
+	cache.Set("k1", "v1", 0)
+	cache.Get("k1")
+	cache.Get("missing")
+
+	m, err := client.Metrics(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, m.Items)
+	assert.Equal(t, 10, m.Capacity)
+	assert.Equal(t, uint64(1), m.Hits)
+	assert.Equal(t, uint64(1), m.Misses)
+	assert.Zero(t, m.LockWait.Count) // задержки не отслеживаются
+}
@@ -0,0 +1,4 @@
+// Package cacherpb contains the protobuf messages and gRPC stubs for the cacher service.
+package cacherpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cacher.proto
@@ -0,0 +1,117 @@
+package cachergrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/cachergrpc/cacherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotFound is returned by the client when the key is not in the remote cache.
+var ErrNotFound = errors.New("cachergrpc: key not found")
+
+// Client is a client for a remote cache node.
+type Client struct {
+	rpc  cacherpb.CacherClient
+	conn *grpc.ClientConn // Set only if the client owns the connection
+}
+
+// Dial connects to a cache node at target.
+// The returned client owns the connection and must be closed.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{rpc: cacherpb.NewCacherClient(conn), conn: conn}, nil
+}
+
+// NewClient creates a client on an existing connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{rpc: cacherpb.NewCacherClient(conn)}
+}
+
+// Get returns the value for a key, or ErrNotFound.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := c.rpc.Get(ctx, &cacherpb.GetRequest{Key: key})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return resp.GetValue(), nil
+}
+
+// Set stores a value with a TTL. A TTL of 0 means no expiration. TTLs are sent
+// in milliseconds, rounded up so a short TTL does not become no expiration.
+// Negative TTLs, including cacher.DefaultExpiration, are rejected: the default
+// TTL of the remote cache is not known.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("cachergrpc: ttl cannot be negative: %v", ttl)
+	}
+	ms := ttl.Milliseconds()
+	if ttl%time.Millisecond != 0 {
+		ms++
+	}
+	_, err := c.rpc.Set(ctx, &cacherpb.SetRequest{Key: key, Value: value, TtlMs: ms})
+	return convertError(err)
+}
+
+// Delete removes a key, or returns ErrNotFound.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	_, err := c.rpc.Delete(ctx, &cacherpb.DeleteRequest{Key: key})
+	return convertError(err)
+}
+
+// Stats returns the remote cache statistics.
+func (c *Client) Stats(ctx context.Context) (string, error) {
+	resp, err := c.rpc.Stats(ctx, &cacherpb.StatsRequest{})
+	if err != nil {
+		return "", convertError(err)
+	}
+	return resp.GetStats(), nil
+}
+
+// Metrics returns the item count, capacity, hits, misses and lock wait
+// of the remote cache. Other fields are zero.
+func (c *Client) Metrics(ctx context.Context) (cacher.Metrics, error) {
+	resp, err := c.rpc.Metrics(ctx, &cacherpb.MetricsRequest{})
+	if err != nil {
+		return cacher.Metrics{}, convertError(err)
+	}
+	wait := resp.GetLockWait()
+	return cacher.Metrics{
+		Items:    int(resp.GetItems()),
+		Capacity: int(resp.GetCapacity()),
+		Hits:     resp.GetHits(),
+		Misses:   resp.GetMisses(),
+		LockWait: cacher.LatencyStats{
+			Count: wait.GetCount(),
+			P50:   time.Duration(wait.GetP50Ns()),
+			P95:   time.Duration(wait.GetP95Ns()),
+			P99:   time.Duration(wait.GetP99Ns()),
+			Max:   time.Duration(wait.GetMaxNs()),
+		},
+	}, nil
+}
+
+// Close closes the connection if it was opened by Dial.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// convertError maps gRPC NotFound to ErrNotFound.
+func convertError(err error) error {
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package cachergrpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// CacheOptions configures the response caching interceptors.
+type CacheOptions struct {
+	// Methods maps full method names, e.g. "/shop.v1.Catalog/GetProduct", to the TTL
+	// of their responses. Only these methods are cached, so list only idempotent ones.
+	// A TTL of 0 means no expiration.
+	Methods map[string]time.Duration
+}
+
+// responseKey identifies a cached response by method and request hash.
+type responseKey struct {
+	method string
+	hash   [sha256.Size]byte
+}
+
+// newResponseKey hashes the deterministic encoding of a request.
+func newResponseKey(method string, req interface{}) (responseKey, bool) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return responseKey{}, false
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return responseKey{}, false
+	}
+	return responseKey{method: method, hash: sha256.Sum256(data)}, true
+}
+
+// UnaryClientInterceptor returns a client interceptor that serves responses of the
+// configured methods from cache, keyed by method and request. Errors are not cached.
+// Metadata is not part of the key, so cached methods must not answer differently per caller.
+func UnaryClientInterceptor(cache *cacher.Cacher, opts CacheOptions) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ttl, ok := opts.Methods[method]
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		key, ok := newResponseKey(method, req)
+		out, isMsg := reply.(proto.Message)
+		if !ok || !isMsg {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+
+		if value, err := cache.Get(key); err == nil {
+			if cached, ok := value.(proto.Message); ok {
+				proto.Reset(out)
+				proto.Merge(out, cached)
+				return nil
+			}
+		}
+
+		if err := invoker(ctx, method, req, reply, cc, callOpts...); err != nil {
+			return err
+		}
+		cache.Set(key, proto.Clone(out), ttl)
+		return nil
+	}
+}
+
+// UnaryServerInterceptor returns a server interceptor that answers the configured
+// methods from cache before calling their handlers. See UnaryClientInterceptor.
+func UnaryServerInterceptor(cache *cacher.Cacher, opts CacheOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ttl, ok := opts.Methods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		key, ok := newResponseKey(info.FullMethod, req)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		if value, err := cache.Get(key); err == nil {
+			if cached, ok := value.(proto.Message); ok {
+				return proto.Clone(cached), nil
+			}
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if msg, ok := resp.(proto.Message); ok {
+			cache.Set(key, proto.Clone(msg), ttl)
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,108 @@
+package cachergrpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/cachergrpc/cacherpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startCountingServer serves a cache and counts the Get calls reaching it.
+func startCountingServer(t *testing.T, opts ...grpc.ServerOption) (*cacher.Cacher, *bufconn.Listener, *atomic.Int32) {
+	backend := cacher.New(cacher.Config{})
+	lis := bufconn.Listen(1 << 20)
+
+	var calls atomic.Int32
+	counter := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod == cacherpb.Cacher_Get_FullMethodName {
+			calls.Add(1)
+		}
+		return handler(ctx, req)
+	}
+	srv := grpc.NewServer(append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(counter)}, opts...)...)
+	Register(srv, backend)
+	go srv.Serve(lis)
+
+	t.Cleanup(func() {
+		srv.Stop()
+		backend.Close()
+	})
+	return backend, lis, &calls
+}
+
+func dialBufconn(t *testing.T, lis *bufconn.Listener, opts ...grpc.DialOption) *Client {
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	client, err := Dial("passthrough:///bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	backend, lis, calls := startCountingServer(t)
+	local := cacher.New(cacher.Config{})
+	defer local.Close()
+
+	client := dialBufconn(t, lis, grpc.WithUnaryInterceptor(UnaryClientInterceptor(local, CacheOptions{
+		Methods: map[string]time.Duration{cacherpb.Cacher_Get_FullMethodName: time.Minute},
+	})))
+	ctx := context.Background()
+	backend.Set("k1", "v1", 0)
+
+	for i := 0; i < 3; i++ {
+		got, err := client.Get(ctx, "k1")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v1"), got)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Другой запрос — другой ключ, ошибки не кэшируются
+	_, err := client.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = client.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Методы не из списка не кэшируются
+	_, err = client.Stats(ctx)
+	require.NoError(t, err)
+	_, err = client.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, local.Metrics().Items)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	local := cacher.New(cacher.Config{})
+	defer local.Close()
+	backend, lis, calls := startCountingServer(t, grpc.ChainUnaryInterceptor(UnaryServerInterceptor(local, CacheOptions{
+		Methods: map[string]time.Duration{cacherpb.Cacher_Get_FullMethodName: time.Minute},
+	})))
+	client := dialBufconn(t, lis)
+	ctx := context.Background()
+	backend.Set("k1", "v1", 0)
+
+	_, err := client.Get(ctx, "k1")
+	require.NoError(t, err)
+	backend.Set("k1", "v2", 0)
+
+	// Ответ берётся из кэша, поэтому обновление ещё не видно
+	got, err := client.Get(ctx, "k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), got)
+	assert.Equal(t, int32(2), calls.Load()) // счётчик стоит перед кэширующим перехватчиком
+	assert.Equal(t, 1, local.Metrics().Items)
+}
@@ -0,0 +1,99 @@
+// Package cachergrpc exposes a *cacher.Cacher over gRPC and provides a matching client,
+// so several services can share one cache node.
+//
+// Values are transferred as bytes. The server stores them in the cache as []byte.
+package cachergrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/cachergrpc/cacherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements cacherpb.CacherServer on top of a Cacher.
+type Server struct {
+	cacherpb.UnimplementedCacherServer
+	cache *cacher.Cacher
+}
+
+// NewServer creates a gRPC service backed by the given cache.
+func NewServer(cache *cacher.Cacher) *Server {
+	return &Server{cache: cache}
+}
+
+// Register creates a service for the cache and registers it on s.
+func Register(s grpc.ServiceRegistrar, cache *cacher.Cacher) {
+	cacherpb.RegisterCacherServer(s, NewServer(cache))
+}
+
+// Get returns the value for a key or a NotFound status.
+func (s *Server) Get(_ context.Context, req *cacherpb.GetRequest) (*cacherpb.GetResponse, error) {
+	value, err := s.cache.Get(req.GetKey())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &cacherpb.GetResponse{Value: toBytes(value, s.cache.Codec())}, nil
+}
+
+// Set stores a value with the requested TTL.
+func (s *Server) Set(_ context.Context, req *cacherpb.SetRequest) (*cacherpb.SetResponse, error) {
+	if req.GetTtlMs() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ttl cannot be negative: %d", req.GetTtlMs())
+	}
+	s.cache.Set(req.GetKey(), req.GetValue(), time.Duration(req.GetTtlMs())*time.Millisecond)
+	return &cacherpb.SetResponse{}, nil
+}
+
+// Delete removes a key or returns a NotFound status.
+func (s *Server) Delete(_ context.Context, req *cacherpb.DeleteRequest) (*cacherpb.DeleteResponse, error) {
+	if err := s.cache.Delete(req.GetKey()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &cacherpb.DeleteResponse{}, nil
+}
+
+// Stats returns the cache statistics string.
+func (s *Server) Stats(context.Context, *cacherpb.StatsRequest) (*cacherpb.StatsResponse, error) {
+	return &cacherpb.StatsResponse{Stats: s.cache.Stats()}, nil
+}
+
+// Metrics returns the cache counters and lock wait.
+func (s *Server) Metrics(context.Context, *cacherpb.MetricsRequest) (*cacherpb.MetricsResponse, error) {
+	m := s.cache.Metrics()
+	return &cacherpb.MetricsResponse{
+		Items:    int64(m.Items),
+		Capacity: int64(m.Capacity),
+		Hits:     m.Hits,
+		Misses:   m.Misses,
+		LockWait: &cacherpb.Latency{
+			Count: m.LockWait.Count,
+			P50Ns: int64(m.LockWait.P50),
+			P95Ns: int64(m.LockWait.P95),
+			P99Ns: int64(m.LockWait.P99),
+			MaxNs: int64(m.LockWait.Max),
+		},
+	}, nil
+}
+
+// toBytes converts a cached value to bytes for transport.
+// Other types are encoded with the cache codec if it has one.
+func toBytes(value interface{}, codec cacher.Codec) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	}
+	if codec != nil {
+		if data, err := codec.Marshal(value); err == nil {
+			return data
+		}
+	}
+	return []byte(fmt.Sprint(value))
+}
@@ -0,0 +1,103 @@
+// Package cacherhttp exposes a *cacher.Cacher over HTTP for administration and inspection,
+// e.g. with cmd/cacherctl.
+//
+// Routes:
+//
+//	GET    /stats      metrics as JSON
+//	GET    /keys/{key} the entry as JSON, 404 if it is missing
+//	DELETE /keys/{key} removes the key, 404 if it is missing
+//	GET    /dump       all entries as a JSON array
+//
+// Only string keys can be addressed. Values are written as JSON; []byte values
+// holding valid UTF-8 are written as strings, and values JSON cannot encode with fmt.Sprint.
+// Reading entries does not count as an access.
+package cacherhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/danRulev/cacher"
+)
+
+// Entry is the JSON form of a cached item.
+type Entry struct {
+	Key        string      `json:"key"`
+	Value      interface{} `json:"value"`
+	TTL        int64       `json:"ttl_ms"`       // Configured TTL in milliseconds, 0 if none
+	Remaining  int64       `json:"remaining_ms"` // Milliseconds until expiration, 0 if the item never expires
+	Counter    int         `json:"counter"`
+	CreatedAt  time.Time   `json:"created_at"`
+	LastUsedAt time.Time   `json:"last_used_at"`
+}
+
+// Handler returns an http.Handler serving the admin routes for the cache.
+// Mount it behind authentication: it can read and delete any key.
+func Handler(cache *cacher.Cacher) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, cache.Metrics())
+	})
+	mux.HandleFunc("GET /keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		info, err := cache.Entry(r.PathValue("key"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, newEntry(info))
+	})
+	mux.HandleFunc("DELETE /keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		if err := cache.Delete(r.PathValue("key")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /dump", func(w http.ResponseWriter, r *http.Request) {
+		keys, _ := cache.Keys()
+		entries := make([]Entry, 0, len(keys))
+		for _, key := range keys {
+			// Keys may expire or be deleted while the dump is built
+			if info, err := cache.Entry(key); err == nil {
+				entries = append(entries, newEntry(info))
+			}
+		}
+		writeJSON(w, entries)
+	})
+	return mux
+}
+
+// newEntry converts entry metadata to its JSON form.
+func newEntry(info cacher.EntryInfo) Entry {
+	return Entry{
+		Key:        fmt.Sprint(info.Key),
+		Value:      jsonValue(info.Value),
+		TTL:        info.TTL.Milliseconds(),
+		Remaining:  info.Remaining.Milliseconds(),
+		Counter:    info.Counter,
+		CreatedAt:  info.CreatedAt,
+		LastUsedAt: info.LastUsedAt,
+	}
+}
+
+// jsonValue returns a value that encodes as readable JSON.
+func jsonValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok && utf8.Valid(b) {
+		return string(b)
+	}
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return value
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,82 @@
+package cacherhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*cacher.Cacher, *httptest.Server) {
+	cache := cacher.New(cacher.Config{Capacity: 10})
+	srv := httptest.NewServer(Handler(cache))
+	t.Cleanup(func() {
+		srv.Close()
+		cache.Close()
+	})
+	return cache, srv
+}
+
+func TestHandler_Keys(t *testing.T) {
+	cache, srv := newTestServer(t)
+	cache.Set("k1", []byte("v1"), time.Minute)
+
+	resp, err := http.Get(srv.URL + "/keys/k1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var entry Entry
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&entry))
+	assert.Equal(t, "k1", entry.Key)
+	assert.Equal(t, "v1", entry.Value) // []byte с UTF-8 выводится строкой
+	assert.Equal(t, time.Minute.Milliseconds(), entry.TTL)
+
+	// Чтение через админку не считается обращением
+	counter, err := cache.GetCounter("k1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, counter)
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/keys/k1", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/keys/k1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_StatsAndDump(t *testing.T) {
+	cache, srv := newTestServer(t)
+	cache.Set("k1", "v1", 0)
+	cache.Set("k2", make(chan int), 0)
+
+	resp, err := http.Get(srv.URL + "/stats")
+	require.NoError(t, err)
+	var metrics cacher.Metrics
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&metrics))
+	resp.Body.Close()
+	assert.Equal(t, 2, metrics.Items)
+
+	resp, err = http.Get(srv.URL + "/dump")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var entries []Entry
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+	require.Len(t, entries, 2)
+
+	values := map[string]interface{}{}
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+	}
+	assert.Equal(t, "v1", values["k1"])
+	assert.IsType(t, "", values["k2"]) // канал не кодируется в JSON и выводится через fmt.Sprint
+}
@@ -0,0 +1,402 @@
+// Package cachermemcache serves a subset of the memcached text protocol backed by a *cacher.Cacher,
+// so existing memcached clients can use the cache over TCP.
+//
+// Supported commands: get, gets (without CAS support), set, delete, touch, flush_all, version, quit.
+// Values are stored as []byte, or as Item if the client sets non-zero flags.
+package cachermemcache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+const (
+	maxKeyLength = 250
+	maxLineBytes = 2048
+
+	// relativeLimit is the largest expiration time in seconds taken as relative
+	// to now. Larger values are absolute Unix timestamps, as in memcached.
+	relativeLimit = 60 * 60 * 24 * 30
+
+	// DefaultMaxItemSize is the largest value accepted by set if Server.MaxItemSize is 0.
+	DefaultMaxItemSize = 1 << 20
+)
+
+// ErrServerClosed is returned by Serve after Close is called.
+var ErrServerClosed = errors.New("cachermemcache: server closed")
+
+// errClose ends a connection after its reply is written.
+var errClose = errors.New("close connection")
+
+// Item is a value stored with non-zero client flags, which clients use to record
+// how the value was serialized.
+type Item struct {
+	Flags uint32
+	Value []byte
+}
+
+// Server is a memcached text protocol server backed by a Cacher.
+type Server struct {
+	// MaxItemSize limits the size of values accepted by set (default: DefaultMaxItemSize).
+	MaxItemSize int
+
+	cache *cacher.Cacher
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+	flush     *time.Timer // Pending delayed flush_all, nil if none
+}
+
+// New creates a server that serves the given cache.
+func New(cache *cacher.Cacher) *Server {
+	return &Server{
+		cache:     cache,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves connections.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener until Close is called.
+// It always returns a non-nil error.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.listeners, l)
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Close stops all listeners, closes open connections, cancels a pending delayed
+// flush_all and waits for handlers to return.
+// The underlying cache is not closed.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	if s.flush != nil {
+		s.flush.Stop()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// handle serves commands from a single connection.
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReaderSize(conn, maxLineBytes)
+	w := bufio.NewWriter(conn)
+	for {
+		line, err := r.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			w.WriteString("CLIENT_ERROR line too long\r\n")
+			w.Flush()
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(string(line))
+		if len(fields) == 0 {
+			w.WriteString("ERROR\r\n")
+		} else if err := s.exec(r, w, fields); err != nil {
+			w.Flush()
+			return
+		}
+
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// exec runs a single command and writes its reply.
+// Returns an error if the connection must be closed.
+func (s *Server) exec(r *bufio.Reader, w *bufio.Writer, fields []string) error {
+	name, args := fields[0], fields[1:]
+	switch name {
+	case "get", "gets":
+		if len(args) == 0 {
+			w.WriteString("ERROR\r\n")
+			return nil
+		}
+		for _, key := range args {
+			s.get(w, key)
+		}
+		w.WriteString("END\r\n")
+	case "set":
+		return s.set(r, w, args)
+	case "delete":
+		args, noreply := noReply(args)
+		if len(args) != 1 {
+			w.WriteString("ERROR\r\n")
+			return nil
+		}
+		if s.cache.Delete(args[0]) != nil {
+			reply(w, noreply, "NOT_FOUND")
+			return nil
+		}
+		reply(w, noreply, "DELETED")
+	case "touch":
+		args, noreply := noReply(args)
+		if len(args) != 2 {
+			w.WriteString("ERROR\r\n")
+			return nil
+		}
+		deadline, expired, err := parseExptime(args[1])
+		if err != nil {
+			w.WriteString("CLIENT_ERROR bad command line format\r\n")
+			return nil
+		}
+		if expired {
+			if s.cache.Delete(args[0]) != nil {
+				reply(w, noreply, "NOT_FOUND")
+				return nil
+			}
+			reply(w, noreply, "TOUCHED")
+			return nil
+		}
+		if s.cache.ExpireAt(args[0], deadline) != nil {
+			reply(w, noreply, "NOT_FOUND")
+			return nil
+		}
+		s.cache.SetTTL(args[0], 0)
+		reply(w, noreply, "TOUCHED")
+	case "flush_all":
+		args, noreply := noReply(args)
+		if len(args) > 1 {
+			w.WriteString("ERROR\r\n")
+			return nil
+		}
+		if len(args) == 1 {
+			delay, err := strconv.ParseUint(args[0], 10, 32)
+			if err != nil {
+				w.WriteString("CLIENT_ERROR bad command line format\r\n")
+				return nil
+			}
+			if delay > 0 {
+				s.scheduleFlush(time.Duration(delay) * time.Second)
+				reply(w, noreply, "OK")
+				return nil
+			}
+		}
+		s.scheduleFlush(0)
+		s.cache.Clear()
+		reply(w, noreply, "OK")
+	case "version":
+		w.WriteString("VERSION cacher\r\n")
+	case "quit":
+		return errClose
+	default:
+		w.WriteString("ERROR\r\n")
+	}
+	return nil
+}
+
+// get writes a VALUE line and data block for a key if it is cached.
+func (s *Server) get(w *bufio.Writer, key string) {
+	value, err := s.cache.Get(key)
+	if err != nil {
+		return
+	}
+	flags, data := s.toBytes(value)
+	fmt.Fprintf(w, "VALUE %s %d %d\r\n", key, flags, len(data))
+	w.Write(data)
+	w.WriteString("\r\n")
+}
+
+// set handles set <key> <flags> <exptime> <bytes> [noreply] followed by a data block.
+func (s *Server) set(r *bufio.Reader, w *bufio.Writer, args []string) error {
+	args, noreply := noReply(args)
+	if len(args) != 4 {
+		w.WriteString("ERROR\r\n")
+		return nil
+	}
+	flags, err1 := strconv.ParseUint(args[1], 10, 32)
+	deadline, expired, err2 := parseExptime(args[2])
+	size, err3 := strconv.Atoi(args[3])
+	if err1 != nil || err2 != nil || err3 != nil || size < 0 {
+		// The data block length is unknown, so the stream cannot be resynchronized
+		w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		return errClose
+	}
+
+	maxSize := s.MaxItemSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxItemSize
+	}
+	if size > maxSize {
+		if _, err := r.Discard(size + 2); err != nil {
+			return err
+		}
+		w.WriteString("SERVER_ERROR object too large for cache\r\n")
+		return nil
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	if string(data[size:]) != "\r\n" {
+		w.WriteString("CLIENT_ERROR bad data chunk\r\n")
+		return errClose
+	}
+	key := args[0]
+	if len(key) > maxKeyLength {
+		w.WriteString("CLIENT_ERROR key too long\r\n")
+		return nil
+	}
+
+	if expired {
+		// A past expiration time stores a value that is never visible
+		_ = s.cache.Delete(key)
+		reply(w, noreply, "STORED")
+		return nil
+	}
+	var value interface{} = data[:size:size]
+	if flags != 0 {
+		value = Item{Flags: uint32(flags), Value: data[:size:size]}
+	}
+	if deadline.IsZero() {
+		s.cache.Set(key, value, 0)
+	} else {
+		s.cache.SetWithDeadline(key, value, deadline) // Reads must not extend it
+	}
+	reply(w, noreply, "STORED")
+	return nil
+}
+
+// toBytes returns the flags and data for a cached value.
+// Other types than Item, []byte and string are encoded with the cache codec if it has one.
+func (s *Server) toBytes(value interface{}) (uint32, []byte) {
+	switch v := value.(type) {
+	case Item:
+		return v.Flags, v.Value
+	case []byte:
+		return 0, v
+	case string:
+		return 0, []byte(v)
+	}
+	if codec := s.cache.Codec(); codec != nil {
+		if data, err := codec.Marshal(value); err == nil {
+			return 0, data
+		}
+	}
+	return 0, []byte(fmt.Sprint(value))
+}
+
+// scheduleFlush replaces the pending delayed flush_all with one after delay.
+// A zero delay only cancels the pending one.
+func (s *Server) scheduleFlush(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.flush != nil {
+		s.flush.Stop()
+		s.flush = nil
+	}
+	if delay > 0 && !s.closed {
+		s.flush = time.AfterFunc(delay, s.cache.Clear)
+	}
+}
+
+// parseExptime converts a memcached expiration time to an absolute deadline,
+// zero if the item never expires. Reads do not extend it, as in memcached.
+// expired is true for negative times and absolute times in the past.
+func parseExptime(s string) (deadline time.Time, expired bool, err error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	switch {
+	case n == 0:
+		return time.Time{}, false, nil
+	case n < 0:
+		return time.Time{}, true, nil
+	case n <= relativeLimit:
+		return time.Now().Add(time.Duration(n) * time.Second), false, nil
+	}
+	deadline = time.Unix(n, 0)
+	return deadline, !deadline.After(time.Now()), nil
+}
+
+// noReply strips a trailing noreply argument.
+func noReply(args []string) ([]string, bool) {
+	if len(args) > 0 && args[len(args)-1] == "noreply" {
+		return args[:len(args)-1], true
+	}
+	return args, false
+}
+
+// reply writes a status line unless the client asked for no reply.
+func reply(w *bufio.Writer, noreply bool, status string) {
+	if !noreply {
+		w.WriteString(status + "\r\n")
+	}
+}
@@ -0,0 +1,145 @@
+package cachermemcache
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T, maxItemSize int) (*cacher.Cacher, net.Conn, *bufio.Reader) {
+	cache := cacher.New(cacher.Config{Capacity: 10})
+	srv := New(cache)
+	srv.MaxItemSize = maxItemSize
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Close()
+		cache.Close()
+	})
+	return cache, conn, bufio.NewReader(conn)
+}
+
+func send(t *testing.T, conn net.Conn, r *bufio.Reader, cmd string, lines int) []string {
+	_, err := conn.Write([]byte(cmd))
+	require.NoError(t, err)
+
+	reply := make([]string, 0, lines)
+	for i := 0; i < lines; i++ {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		reply = append(reply, strings.TrimSuffix(line, "\r\n"))
+	}
+	return reply
+}
+
+func TestServer_SetGet(t *testing.T) {
+	cache, conn, r := startServer(t, 0)
+
+	assert.Equal(t, []string{"STORED"}, send(t, conn, r, "set k1 0 0 2\r\nv1\r\n", 1))
+	assert.Equal(t, []string{"STORED"}, send(t, conn, r, "set k2 42 60 5\r\nhello\r\n", 1))
+	assert.Equal(t, []string{"VALUE k1 0 2", "v1", "VALUE k2 42 5", "hello", "END"},
+		send(t, conn, r, "get k1 missing k2\r\n", 5))
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), got)
+
+	// Флаги сохраняются вместе со значением
+	got, err = cache.Get("k2")
+	require.NoError(t, err)
+	assert.Equal(t, Item{Flags: 42, Value: []byte("hello")}, got)
+	// Срок жизни абсолютный, как в memcached: чтения его не продлевают
+	entry, err := cache.Entry("k2")
+	require.NoError(t, err)
+	assert.Zero(t, entry.TTL)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), entry.Deadline, time.Second)
+
+	// Значения, заданные из Go, тоже читаются
+	cache.Set("k3", "v3", 0)
+	assert.Equal(t, []string{"VALUE k3 0 2", "v3", "END"}, send(t, conn, r, "gets k3\r\n", 3))
+}
+
+func TestServer_DeleteTouchFlush(t *testing.T) {
+	cache, conn, r := startServer(t, 0)
+	cache.Set("k1", []byte("v1"), 0)
+	cache.Set("k2", []byte("v2"), 0)
+
+	assert.Equal(t, []string{"DELETED"}, send(t, conn, r, "delete k1\r\n", 1))
+	assert.Equal(t, []string{"NOT_FOUND"}, send(t, conn, r, "delete k1\r\n", 1))
+
+	assert.Equal(t, []string{"TOUCHED"}, send(t, conn, r, "touch k2 100\r\n", 1))
+	entry, err := cache.Entry("k2")
+	require.NoError(t, err)
+	assert.Zero(t, entry.TTL)
+	assert.WithinDuration(t, time.Now().Add(100*time.Second), entry.Deadline, time.Second)
+	assert.Equal(t, []string{"NOT_FOUND"}, send(t, conn, r, "touch k1 100\r\n", 1))
+
+	// Абсолютное время в прошлом удаляет ключ
+	past := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	assert.Equal(t, []string{"TOUCHED"}, send(t, conn, r, "touch k2 "+past+"\r\n", 1))
+	_, err = cache.Get("k2")
+	assert.Error(t, err)
+
+	cache.Set("k3", []byte("v3"), 0)
+	assert.Equal(t, []string{"OK"}, send(t, conn, r, "flush_all\r\n", 1))
+	assert.Equal(t, 0, cache.Metrics().Items)
+}
+
+func TestServer_CloseStopsDelayedFlush(t *testing.T) {
+	cache := cacher.New(cacher.Config{Capacity: 10})
+	defer cache.Close()
+	srv := New(cache)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(l)
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cache.Set("k1", []byte("v1"), 0)
+	assert.Equal(t, []string{"OK"}, send(t, conn, bufio.NewReader(conn), "flush_all 1\r\n", 1))
+	require.NoError(t, srv.Close())
+
+	// Отложенная очистка не срабатывает после закрытия сервера
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(t, 1, cache.Metrics().Items)
+}
+
+func TestServer_NoReply(t *testing.T) {
+	cache, conn, r := startServer(t, 0)
+
+	// Ответ приходит только на version
+	assert.Equal(t, []string{"VERSION cacher"}, send(t, conn, r, "set k1 0 0 2 noreply\r\nv1\r\ndelete k2 noreply\r\nversion\r\n", 1))
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), got)
+}
+
+func TestServer_Errors(t *testing.T) {
+	_, conn, r := startServer(t, 4)
+
+	assert.Equal(t, []string{"ERROR"}, send(t, conn, r, "unknown\r\n", 1))
+	assert.Equal(t, []string{"ERROR"}, send(t, conn, r, "get\r\n", 1))
+	assert.Equal(t, []string{"SERVER_ERROR object too large for cache"}, send(t, conn, r, "set k1 0 0 5\r\nhello\r\n", 1))
+	assert.Equal(t, []string{"END"}, send(t, conn, r, "get k1\r\n", 1))
+
+	// После неверного блока данных соединение закрывается
+	assert.Equal(t, []string{"CLIENT_ERROR bad data chunk"}, send(t, conn, r, "set k1 0 0 2\r\nabcd\r\n", 1))
+	_, err := r.ReadString('\n')
+	assert.Error(t, err)
+}
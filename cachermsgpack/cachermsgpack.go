@@ -0,0 +1,57 @@
+// Package cachermsgpack is a cacher.Codec using MessagePack, a compact binary
+// encoding with libraries for most languages.
+//
+// Unlike gob, custom types need no registration, and struct fields are named by
+// their json tags. Decoded values have generic types: maps are map[string]interface{},
+// arrays []interface{} and integers int64 or uint64. Use Typed to decode into a known type instead.
+package cachermsgpack
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec encodes values with MessagePack. The zero value is ready to use.
+type Codec struct {
+	typ reflect.Type // Type to decode into, nil for generic types
+}
+
+// Typed returns a Codec that decodes values into the type of example,
+// e.g. Typed(User{}) returns User values.
+func Typed(example interface{}) Codec {
+	return Codec{typ: reflect.TypeOf(example)}
+}
+
+// Marshal encodes a value.
+func (c Codec) Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(value); err != nil {
+		return nil, fmt.Errorf("msgpack encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes a value encoded by Marshal.
+func (c Codec) Unmarshal(data []byte) (interface{}, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	if c.typ == nil {
+		dec.UseLooseInterfaceDecoding(true)
+		value, err := dec.DecodeInterfaceLoose()
+		if err != nil {
+			return nil, fmt.Errorf("msgpack decode: %w", err)
+		}
+		return value, nil
+	}
+
+	ptr := reflect.New(c.typ)
+	if err := dec.Decode(ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("msgpack decode %v: %w", c.typ, err)
+	}
+	return ptr.Elem().Interface(), nil
+}
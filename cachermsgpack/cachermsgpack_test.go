@@ -0,0 +1,55 @@
+package cachermsgpack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestCodec(t *testing.T) {
+	var codec Codec
+
+	data, err := codec.Marshal(user{Name: "bob", Age: 30})
+	require.NoError(t, err)
+
+	// Без типа структура декодируется в map с именами из json-тегов
+	got, err := codec.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "bob", "age": int64(30)}, got)
+
+	_, err = codec.Unmarshal([]byte{0xc1})
+	assert.Error(t, err)
+
+	_, err = codec.Marshal(make(chan int))
+	assert.Error(t, err)
+}
+
+func TestTyped(t *testing.T) {
+	codec := Typed(user{})
+
+	data, err := codec.Marshal(user{Name: "bob", Age: 30})
+	require.NoError(t, err)
+
+	got, err := codec.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, user{Name: "bob", Age: 30}, got)
+}
+
+func TestCodec_Cacher(t *testing.T) {
+	cache := cacher.New(cacher.Config{Codec: Typed(user{})})
+	defer cache.Close()
+
+	cache.Set("k1", user{Name: "bob"}, time.Minute)
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, user{Name: "bob"}, got)
+}
@@ -0,0 +1,89 @@
+// Package cachernats is a replication.InvalidationBus on a NATS subject, so replicas
+// drop stale keys when one of them writes:
+//
+//	bus := cachernats.NewBus(conn, "cache.invalidations")
+//	r, err := replication.New(cache, replication.BusTransport(bus), replication.Options{})
+//
+// It is a separate module, so the cacher module does not depend on the NATS client.
+package cachernats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/danRulev/cacher/replication"
+	"github.com/nats-io/nats.go"
+)
+
+// Bus publishes invalidations as JSON on a NATS subject.
+// Core NATS does not store messages, so replicas miss invalidations sent while they are disconnected.
+type Bus struct {
+	conn    *nats.Conn
+	subject string
+
+	mu  sync.Mutex
+	sub *nats.Subscription
+}
+
+var _ replication.InvalidationBus = (*Bus)(nil)
+
+// NewBus creates a bus on a NATS subject. The connection is not closed by the bus.
+func NewBus(conn *nats.Conn, subject string) *Bus {
+	return &Bus{conn: conn, subject: subject}
+}
+
+// Publish sends an invalidation to the subject and flushes it to the server,
+// within the context deadline or the connection's default timeout.
+func (b *Bus) Publish(ctx context.Context, inv replication.Invalidation) error {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	if err := b.conn.Publish(b.subject, data); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return b.conn.Flush()
+	}
+	return b.conn.FlushWithContext(ctx)
+}
+
+// Subscribe subscribes to the subject and calls handler for every invalidation
+// in a single goroutine. Malformed messages are skipped. It can be called once.
+func (b *Bus) Subscribe(handler func(replication.Invalidation)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sub != nil {
+		return errors.New("cachernats: bus already subscribed")
+	}
+
+	sub, err := b.conn.Subscribe(b.subject, func(msg *nats.Msg) {
+		var inv replication.Invalidation
+		if err := json.Unmarshal(msg.Data, &inv); err != nil {
+			return
+		}
+		handler(inv)
+	})
+	if err != nil {
+		return err
+	}
+	// Make sure the server has the subscription before returning
+	if err := b.conn.Flush(); err != nil {
+		sub.Unsubscribe()
+		return err
+	}
+	b.sub = sub
+	return nil
+}
+
+// Close unsubscribes from the subject.
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sub == nil {
+		return nil
+	}
+	return b.sub.Unsubscribe()
+}
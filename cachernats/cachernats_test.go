@@ -0,0 +1,184 @@
+package cachernats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/replication"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// broker is a minimal NATS server for tests: it supports CONNECT, PING, SUB,
+// UNSUB and PUB with exact subjects, without queue groups or wildcards.
+type broker struct {
+	listener net.Listener
+
+	mu   sync.Mutex
+	subs map[string]map[*brokerConn]map[string]struct{} // Subject -> connection -> sids
+}
+
+// brokerConn is a client connection. Writes are serialized by mu.
+type brokerConn struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (c *brokerConn) write(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.Write([]byte(s))
+}
+
+func runBroker(t *testing.T) *broker {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := &broker{listener: l, subs: make(map[string]map[*brokerConn]map[string]struct{})}
+	go b.accept()
+	t.Cleanup(func() { l.Close() })
+	return b
+}
+
+func (b *broker) ClientURL() string {
+	return "nats://" + b.listener.Addr().String()
+}
+
+func (b *broker) accept() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.serve(&brokerConn{conn: conn})
+	}
+}
+
+func (b *broker) serve(c *brokerConn) {
+	defer c.conn.Close()
+	c.write(`INFO {"server_id":"test","version":"2.10.0","proto":1,"max_payload":1048576}` + "\r\n")
+
+	r := bufio.NewReader(c.conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			b.unsubscribeAll(c)
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			c.write("PONG\r\n")
+		case "SUB":
+			b.subscribe(c, fields[1], fields[len(fields)-1])
+		case "UNSUB":
+			b.unsubscribe(c, fields[1])
+		case "PUB":
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			b.publish(fields[1], payload[:size])
+		}
+	}
+}
+
+func (b *broker) subscribe(c *brokerConn, subject, sid string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[subject] == nil {
+		b.subs[subject] = make(map[*brokerConn]map[string]struct{})
+	}
+	if b.subs[subject][c] == nil {
+		b.subs[subject][c] = make(map[string]struct{})
+	}
+	b.subs[subject][c][sid] = struct{}{}
+}
+
+func (b *broker) unsubscribe(c *brokerConn, sid string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, conns := range b.subs {
+		delete(conns[c], sid)
+	}
+}
+
+func (b *broker) unsubscribeAll(c *brokerConn) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, conns := range b.subs {
+		delete(conns, c)
+	}
+}
+
+func (b *broker) publish(subject string, payload []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c, sids := range b.subs[subject] {
+		for sid := range sids {
+			c.write(fmt.Sprintf("MSG %s %s %d\r\n%s\r\n", subject, sid, len(payload), payload))
+		}
+	}
+}
+
+func TestBus(t *testing.T) {
+	srv := runBroker(t)
+
+	newReplica := func() *replication.Replicator {
+		conn, err := nats.Connect(srv.ClientURL())
+		require.NoError(t, err)
+		r, err := replication.New(cacher.New(cacher.Config{}), replication.BusTransport(NewBus(conn, "cache.invalidations")), replication.Options{})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			r.Close()
+			r.Cache().Close()
+			conn.Close()
+		})
+		return r
+	}
+	a, b := newReplica(), newReplica()
+
+	b.Cache().Set("k1", "old", 0)
+	b.Cache().Set("k2", "v2", 0)
+	require.NoError(t, a.Set("k1", "new", 0))
+
+	assert.Eventually(t, func() bool {
+		_, err := b.Cache().Get("k1")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// Своё сообщение реплика игнорирует
+	got, err := a.Cache().Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "new", got)
+
+	require.NoError(t, a.Clear())
+	assert.Eventually(t, func() bool {
+		_, err := b.Cache().Get("k2")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBus_SubscribeTwice(t *testing.T) {
+	srv := runBroker(t)
+	conn, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	bus := NewBus(conn, "cache.invalidations")
+	defer bus.Close()
+	require.NoError(t, bus.Subscribe(func(replication.Invalidation) {}))
+	assert.Error(t, bus.Subscribe(func(replication.Invalidation) {}))
+}
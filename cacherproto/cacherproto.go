@@ -0,0 +1,43 @@
+// Package cacherproto is a cacher.Codec for protocol buffer messages.
+//
+// Each value is stored as a google.protobuf.Any, so it is decoded back into
+// its own message type, which must be linked into the program. Values that
+// are not proto.Message, including strings and []byte, cannot be encoded;
+// wrap them in wrapperspb types.
+package cacherproto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Codec encodes proto.Message values. The zero value is ready to use.
+type Codec struct{}
+
+// Marshal encodes a message with its type URL.
+func (Codec) Marshal(value interface{}) ([]byte, error) {
+	msg, ok := value.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("proto encode: %T is not a proto.Message", value)
+	}
+	wrapped, err := anypb.New(msg)
+	if err != nil {
+		return nil, fmt.Errorf("proto encode: %w", err)
+	}
+	return proto.Marshal(wrapped)
+}
+
+// Unmarshal decodes a message encoded by Marshal into a new message of its type.
+func (Codec) Unmarshal(data []byte) (interface{}, error) {
+	var wrapped anypb.Any
+	if err := proto.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("proto decode: %w", err)
+	}
+	msg, err := wrapped.UnmarshalNew()
+	if err != nil {
+		return nil, fmt.Errorf("proto decode %s: %w", wrapped.GetTypeUrl(), err)
+	}
+	return msg, nil
+}
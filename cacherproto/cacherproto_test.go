@@ -0,0 +1,33 @@
+package cacherproto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCodec(t *testing.T) {
+	var codec Codec
+
+	data, err := codec.Marshal(wrapperspb.String("value"))
+	require.NoError(t, err)
+
+	// Тип сообщения восстанавливается по type URL
+	got, err := codec.Unmarshal(data)
+	require.NoError(t, err)
+	require.IsType(t, &wrapperspb.StringValue{}, got)
+	assert.True(t, proto.Equal(wrapperspb.String("value"), got.(proto.Message)))
+}
+
+func TestCodec_Errors(t *testing.T) {
+	var codec Codec
+
+	_, err := codec.Marshal("value")
+	assert.Error(t, err)
+
+	_, err = codec.Unmarshal([]byte("garbage"))
+	assert.Error(t, err)
+}
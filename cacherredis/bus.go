@@ -0,0 +1,84 @@
+package cacherredis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/danRulev/cacher/replication"
+	"github.com/redis/go-redis/v9"
+)
+
+// Bus is a replication.InvalidationBus on a Redis pub/sub channel.
+// Invalidations are published as JSON. Redis does not store pub/sub messages,
+// so replicas miss invalidations sent while they are disconnected.
+type Bus struct {
+	client  redis.UniversalClient
+	channel string
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+	wg     sync.WaitGroup
+}
+
+var _ replication.InvalidationBus = (*Bus)(nil)
+
+// NewBus creates a bus on a Redis channel. The client is not closed by the bus.
+func NewBus(client redis.UniversalClient, channel string) *Bus {
+	return &Bus{client: client, channel: channel}
+}
+
+// Publish sends an invalidation to the channel.
+func (b *Bus) Publish(ctx context.Context, inv replication.Invalidation) error {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, data).Err()
+}
+
+// Subscribe subscribes to the channel and calls handler for every invalidation
+// in a single goroutine. Malformed messages are skipped. It can be called once.
+func (b *Bus) Subscribe(handler func(replication.Invalidation)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pubsub != nil {
+		return errors.New("cacherredis: bus already subscribed")
+	}
+
+	pubsub := b.client.Subscribe(context.Background(), b.channel)
+	// Wait for the subscription, so invalidations published after Subscribe returns are received
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		pubsub.Close()
+		return err
+	}
+	b.pubsub = pubsub
+
+	ch := pubsub.Channel()
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for msg := range ch {
+			var inv replication.Invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				continue
+			}
+			handler(inv)
+		}
+	}()
+	return nil
+}
+
+// Close unsubscribes and waits for the handler to return.
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	pubsub := b.pubsub
+	b.mu.Unlock()
+	if pubsub == nil {
+		return nil
+	}
+	err := pubsub.Close()
+	b.wg.Wait()
+	return err
+}
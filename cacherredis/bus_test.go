@@ -0,0 +1,54 @@
+package cacherredis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/replication"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	newReplica := func() *replication.Replicator {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		r, err := replication.New(cacher.New(cacher.Config{}), replication.BusTransport(NewBus(client, "invalidations")), replication.Options{})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			r.Close()
+			r.Cache().Close()
+			client.Close()
+		})
+		return r
+	}
+	a, b := newReplica(), newReplica()
+
+	b.Cache().Set("k1", "old", 0)
+	require.NoError(t, a.Set("k1", "new", 0))
+
+	assert.Eventually(t, func() bool {
+		_, err := b.Cache().Get("k1")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// Своё сообщение реплика игнорирует
+	got, err := a.Cache().Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "new", got)
+}
+
+func TestBus_SubscribeTwice(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	bus := NewBus(client, "invalidations")
+	defer bus.Close()
+	require.NoError(t, bus.Subscribe(func(replication.Invalidation) {}))
+	assert.Error(t, bus.Subscribe(func(replication.Invalidation) {}))
+}
@@ -0,0 +1,107 @@
+// Package cacherredis is a cacher.Backend on Redis, so a cache can use Redis as
+// its second level: items evicted from memory move to Redis with their remaining
+// TTL and are restored on Get. Bus carries replication invalidations over Redis pub/sub.
+//
+// It is a separate module, so the cacher module does not depend on go-redis.
+// Values are gob-encoded, so custom types must be registered with gob.Register.
+package cacherredis
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/redis/go-redis/v9"
+)
+
+var defaultTimeout = time.Second
+
+// Options configures a Backend.
+type Options struct {
+	// Prefix is prepended to every Redis key, e.g. "cache:", to share a database with other data.
+	Prefix string
+
+	// Timeout bounds each Redis call. If 0, defaults to 1 second.
+	Timeout time.Duration
+}
+
+// entry is the stored representation of a value.
+type entry struct {
+	Value interface{}
+}
+
+// Backend stores cache items in Redis. Safe for concurrent use.
+type Backend struct {
+	client  redis.UniversalClient
+	prefix  string
+	timeout time.Duration
+}
+
+var _ cacher.Backend = (*Backend)(nil)
+
+// New creates a Backend over a Redis client, e.g. redis.NewClient.
+// The client is not closed by the Backend.
+func New(client redis.UniversalClient, opts Options) *Backend {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+	return &Backend{client: client, prefix: opts.Prefix, timeout: opts.Timeout}
+}
+
+// Get returns the value and remaining TTL stored for key.
+func (b *Backend) Get(key interface{}) (interface{}, time.Duration, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, b.key(key))
+		pttl = pipe.PTTL(ctx, b.key(key))
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, 0, false, err
+	}
+	var e entry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return nil, 0, false, fmt.Errorf("decode %v: %w", key, err)
+	}
+	return e.Value, max(pttl.Val(), 0), true, nil
+}
+
+// Set stores a value with a TTL. A TTL of 0 means no expiration.
+func (b *Backend) Set(key, value interface{}, ttl time.Duration) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&entry{Value: value}); err != nil {
+		return fmt.Errorf("encode %v: %w", key, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	return b.client.Set(ctx, b.key(key), buf.Bytes(), ttl).Err()
+}
+
+// Delete removes a key. Deleting a missing key is not an error.
+func (b *Backend) Delete(key interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	return b.client.Del(ctx, b.key(key)).Err()
+}
+
+// key returns the Redis key for a cache key.
+func (b *Backend) key(key interface{}) string {
+	return fmt.Sprintf("%s%T:%v", b.prefix, key, key)
+}
@@ -0,0 +1,77 @@
+package cacherredis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/danRulev/cacher"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackend(t *testing.T) (*miniredis.Miniredis, *Backend) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, New(client, Options{Prefix: "test:"})
+}
+
+func TestBackend_SetGetDelete(t *testing.T) {
+	server, b := newTestBackend(t)
+
+	require.NoError(t, b.Set("k1", "v1", 0))
+	require.NoError(t, b.Set(2, []byte("v2"), time.Minute))
+	assert.True(t, server.Exists("test:string:k1"))
+
+	value, ttl, ok, err := b.Get("k1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", value)
+	assert.Zero(t, ttl)
+
+	value, ttl, ok, err = b.Get(2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v2"), value)
+	assert.Equal(t, time.Minute, ttl)
+
+	// TTL истекает на стороне Redis
+	server.FastForward(2 * time.Minute)
+	_, _, ok, err = b.Get(2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, b.Delete("k1"))
+	require.NoError(t, b.Delete("missing"))
+	_, _, ok, err = b.Get("k1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestBackend_Overflow(t *testing.T) {
+	_, b := newTestBackend(t)
+
+	cache := cacher.New(cacher.Config{Capacity: 1, Overflow: b})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", time.Hour)
+	cache.Set("k2", "v2", time.Hour) // k1 уходит в Redis
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+	_, _, ok, err := b.Get("k1") // восстановленный ключ удаляется из Redis
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestBackend_Unavailable(t *testing.T) {
+	server, b := newTestBackend(t)
+	server.Close()
+
+	assert.Error(t, b.Set("k1", "v1", 0))
+	_, _, _, err := b.Get("k1")
+	assert.Error(t, err)
+}
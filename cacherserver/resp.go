@@ -0,0 +1,100 @@
+package cacherserver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxBulkLen limits the size of a single bulk string read from a client.
+const maxBulkLen = 512 << 20
+
+// maxArgs limits the number of arguments of a command, as in Redis.
+const maxArgs = 1024 * 1024
+
+var errProtocol = errors.New("protocol error")
+
+// readCommand reads one command from r, either as a RESP array of bulk
+// strings or as an inline command (space separated words).
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, nil
+	}
+	if line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 || n > maxArgs {
+		return nil, errProtocol
+	}
+
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if header == "" || header[0] != '$' {
+			return nil, errProtocol
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, errProtocol
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// readLine reads a line terminated by \r\n (or \n) without the terminator.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// writer encodes RESP replies.
+type writer struct {
+	*bufio.Writer
+}
+
+func (w writer) simple(s string) {
+	fmt.Fprintf(w, "+%s\r\n", s)
+}
+
+func (w writer) error(s string) {
+	fmt.Fprintf(w, "-%s\r\n", s)
+}
+
+func (w writer) integer(n int64) {
+	fmt.Fprintf(w, ":%d\r\n", n)
+}
+
+func (w writer) bulk(s string) {
+	fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+}
+
+func (w writer) null() {
+	w.WriteString("$-1\r\n")
+}
+
+func (w writer) array(items []string) {
+	fmt.Fprintf(w, "*%d\r\n", len(items))
+	for _, item := range items {
+		w.bulk(item)
+	}
+}
@@ -0,0 +1,319 @@
+// Package cacherserver serves a subset of the Redis protocol (RESP) backed by a *cacher.Cacher,
+// so processes written in other languages can share the cache over TCP.
+//
+// Supported commands: PING, GET, SET (with EX/PX), DEL, EXPIRE, TTL, KEYS, FLUSHALL.
+// Keys and values are stored in the cache as strings.
+package cacherserver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+// ErrServerClosed is returned by Serve after Close is called.
+var ErrServerClosed = errors.New("cacherserver: server closed")
+
+// Server is a RESP server backed by a Cacher.
+type Server struct {
+	cache *cacher.Cacher
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// New creates a server that serves the given cache.
+func New(cache *cacher.Cacher) *Server {
+	return &Server{
+		cache:     cache,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves connections.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener until Close is called.
+// It always returns a non-nil error.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.listeners, l)
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Close stops all listeners, closes open connections and waits for handlers to return.
+// The underlying cache is not closed.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// handle serves commands from a single connection.
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := writer{bufio.NewWriter(conn)}
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				w.error("ERR protocol error")
+				w.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		if strings.ToUpper(args[0]) == "QUIT" {
+			w.simple("OK")
+			w.Flush()
+			return
+		}
+		s.exec(w, args)
+
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// exec runs a single command and writes its reply.
+func (s *Server) exec(w writer, args []string) {
+	name := strings.ToUpper(args[0])
+	args = args[1:]
+
+	switch name {
+	case "PING":
+		if len(args) > 0 {
+			w.bulk(args[0])
+			return
+		}
+		w.simple("PONG")
+	case "GET":
+		if len(args) != 1 {
+			wrongArgs(w, name)
+			return
+		}
+		value, err := s.cache.Get(args[0])
+		if err != nil {
+			w.null()
+			return
+		}
+		w.bulk(toString(value, s.cache.Codec()))
+	case "SET":
+		s.set(w, args)
+	case "DEL":
+		if len(args) == 0 {
+			wrongArgs(w, name)
+			return
+		}
+		var n int64
+		for _, key := range args {
+			if s.cache.Delete(key) == nil {
+				n++
+			}
+		}
+		w.integer(n)
+	case "EXPIRE":
+		if len(args) != 2 {
+			wrongArgs(w, name)
+			return
+		}
+		seconds, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			w.error("ERR value is not an integer or out of range")
+			return
+		}
+		if seconds <= 0 {
+			if s.cache.Delete(args[0]) == nil {
+				w.integer(1)
+				return
+			}
+			w.integer(0)
+			return
+		}
+		ttl, ok := expireDuration(seconds, time.Second)
+		if !ok {
+			w.error("ERR invalid expire time in 'expire' command")
+			return
+		}
+		// Redis expiry is absolute: reads must not extend it
+		if err := s.cache.ExpireAt(args[0], time.Now().Add(ttl)); err != nil {
+			w.integer(0)
+			return
+		}
+		s.cache.SetTTL(args[0], 0)
+		w.integer(1)
+	case "TTL":
+		if len(args) != 1 {
+			wrongArgs(w, name)
+			return
+		}
+		// Time left, rounded to seconds as Redis does
+		entry, err := s.cache.Entry(args[0])
+		switch {
+		case err != nil || entry.Negative:
+			w.integer(-2)
+		case entry.Remaining == 0:
+			w.integer(-1)
+		default:
+			w.integer(int64((entry.Remaining + time.Second/2) / time.Second))
+		}
+	case "KEYS":
+		if len(args) != 1 {
+			wrongArgs(w, name)
+			return
+		}
+		s.keys(w, args[0])
+	case "FLUSHALL", "FLUSHDB":
+		s.cache.Clear()
+		w.simple("OK")
+	default:
+		w.error(fmt.Sprintf("ERR unknown command '%s'", strings.ToLower(name)))
+	}
+}
+
+// set handles SET key value [EX seconds | PX milliseconds].
+func (s *Server) set(w writer, args []string) {
+	if len(args) != 2 && len(args) != 4 {
+		wrongArgs(w, "SET")
+		return
+	}
+
+	if len(args) == 2 {
+		s.cache.Set(args[0], args[1], 0)
+		w.simple("OK")
+		return
+	}
+
+	var unit time.Duration
+	switch strings.ToUpper(args[2]) {
+	case "EX":
+		unit = time.Second
+	case "PX":
+		unit = time.Millisecond
+	default:
+		w.error("ERR syntax error")
+		return
+	}
+	n, err := strconv.ParseInt(args[3], 10, 64)
+	ttl, ok := expireDuration(n, unit)
+	if err != nil || !ok {
+		w.error("ERR invalid expire time in 'set' command")
+		return
+	}
+
+	// Redis expiry is absolute: reads must not extend it
+	s.cache.SetWithDeadline(args[0], args[1], time.Now().Add(ttl))
+	w.simple("OK")
+}
+
+// expireDuration converts an expire time of n units to a duration.
+// ok is false if n is not positive or the duration overflows.
+func expireDuration(n int64, unit time.Duration) (time.Duration, bool) {
+	if n <= 0 || n > math.MaxInt64/int64(unit) {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// keys handles KEYS pattern using Redis-style glob matching on string keys.
+func (s *Server) keys(w writer, pattern string) {
+	keys := s.cache.KeysMatching(pattern)
+	matched := make([]string, 0, len(keys))
+	for _, key := range keys {
+		matched = append(matched, key.(string))
+	}
+	w.array(matched)
+}
+
+func wrongArgs(w writer, name string) {
+	w.error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+}
+
+// toString formats a cached value as a RESP bulk string.
+// Other types are encoded with the cache codec if it has one.
+func toString(value interface{}, codec cacher.Codec) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	if codec != nil {
+		if data, err := codec.Marshal(value); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,142 @@
+package cacherserver
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T) (*cacher.Cacher, net.Conn, *bufio.Reader) {
+	cache := cacher.New(cacher.Config{Capacity: 10})
+	srv := New(cache)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Close()
+		cache.Close()
+	})
+	return cache, conn, bufio.NewReader(conn)
+}
+
+func send(t *testing.T, conn net.Conn, r *bufio.Reader, cmd string, lines int) []string {
+	_, err := conn.Write([]byte(cmd))
+	require.NoError(t, err)
+
+	reply := make([]string, 0, lines)
+	for i := 0; i < lines; i++ {
+		line, err := readLine(r)
+		require.NoError(t, err)
+		reply = append(reply, line)
+	}
+	return reply
+}
+
+func TestServer_SetGet(t *testing.T) {
+	_, conn, r := startServer(t)
+
+	assert.Equal(t, []string{"+OK"}, send(t, conn, r, "*3\r\n$3\r\nSET\r\n$2\r\nk1\r\n$2\r\nv1\r\n", 1))
+	assert.Equal(t, []string{"$2", "v1"}, send(t, conn, r, "*2\r\n$3\r\nGET\r\n$2\r\nk1\r\n", 2))
+	assert.Equal(t, []string{"$-1"}, send(t, conn, r, "GET missing\r\n", 1))
+}
+
+func TestServer_DelAndFlush(t *testing.T) {
+	cache, conn, r := startServer(t)
+	cache.Set("k1", "v1", 0)
+	cache.Set("k2", "v2", 0)
+
+	assert.Equal(t, []string{":1"}, send(t, conn, r, "DEL k1 missing\r\n", 1))
+	assert.Equal(t, []string{"+OK"}, send(t, conn, r, "FLUSHALL\r\n", 1))
+
+	_, err := cache.Get("k2")
+	assert.Error(t, err)
+}
+
+func TestServer_ExpireAndTTL(t *testing.T) {
+	cache, conn, r := startServer(t)
+	cache.Set("k1", "v1", 0)
+
+	assert.Equal(t, []string{":-1"}, send(t, conn, r, "TTL k1\r\n", 1))
+	assert.Equal(t, []string{":1"}, send(t, conn, r, "EXPIRE k1 10\r\n", 1))
+	assert.Equal(t, []string{":10"}, send(t, conn, r, "TTL k1\r\n", 1))
+	assert.Equal(t, []string{":-2"}, send(t, conn, r, "TTL missing\r\n", 1))
+
+	// TTL возвращает оставшееся время, а не заданное
+	cache.Set("k3", "v3", 1500*time.Millisecond)
+	time.Sleep(600 * time.Millisecond)
+	assert.Equal(t, []string{":1"}, send(t, conn, r, "TTL k3\r\n", 1))
+
+	assert.Equal(t, []string{"+OK"}, send(t, conn, r, "SET k2 v2 PX 10\r\n", 1))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, []string{"$-1"}, send(t, conn, r, "GET k2\r\n", 1))
+}
+
+func TestServer_ExpireIsAbsolute(t *testing.T) {
+	cache, conn, r := startServer(t)
+
+	// Чтения не продлевают срок жизни, заданный в SET и EXPIRE
+	assert.Equal(t, []string{"+OK"}, send(t, conn, r, "SET k1 v1 PX 150\r\n", 1))
+	cache.Set("k2", "v2", time.Minute)
+	assert.Equal(t, []string{":1"}, send(t, conn, r, "EXPIRE k2 1\r\n", 1))
+	for i := 0; i < 4; i++ {
+		time.Sleep(50 * time.Millisecond)
+		_, err := cache.Get("k1")
+		if i < 2 {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, []string{"$-1"}, send(t, conn, r, "GET k1\r\n", 1))
+	entry, err := cache.Entry("k2")
+	require.NoError(t, err)
+	assert.Zero(t, entry.TTL)
+	assert.False(t, entry.Deadline.IsZero())
+
+	assert.Equal(t, []string{"-ERR invalid expire time in 'set' command"},
+		send(t, conn, r, "SET k3 v3 EX 9223372036854775807\r\n", 1))
+	assert.Equal(t, []string{"-ERR invalid expire time in 'expire' command"},
+		send(t, conn, r, "EXPIRE k2 9223372036854775807\r\n", 1))
+}
+
+func TestServer_Keys(t *testing.T) {
+	cache, conn, r := startServer(t)
+	cache.Set("user:1", "a", 0)
+	cache.Set("order:1", "b", 0)
+
+	assert.Equal(t, []string{"*1", "$6", "user:1"}, send(t, conn, r, "KEYS user:*\r\n", 3))
+
+	// Как в Redis, * совпадает и с символом /
+	cache.Delete("order:1")
+	cache.Set("user/2", "c", 0)
+	reply := send(t, conn, r, "KEYS *\r\n", 5)
+	assert.Equal(t, "*2", reply[0])
+	assert.ElementsMatch(t, []string{"$6", "user:1", "$6", "user/2"}, reply[1:])
+}
+
+func TestServer_TooManyArgs(t *testing.T) {
+	_, conn, r := startServer(t)
+
+	// Заявленное число аргументов больше лимита отклоняется до выделения памяти
+	assert.Equal(t, []string{"-ERR protocol error"}, send(t, conn, r, "*2000000000\r\n", 1))
+
+	_, err := readCommand(bufio.NewReader(strings.NewReader("*1048577\r\n")))
+	assert.ErrorIs(t, err, errProtocol)
+}
+
+func TestServer_UnknownCommand(t *testing.T) {
+	_, conn, r := startServer(t)
+
+	reply := send(t, conn, r, "HGET a b\r\n", 1)
+	assert.Equal(t, "-ERR unknown command 'hget'", reply[0])
+}
@@ -0,0 +1,278 @@
+// Package cachersession keeps HTTP sessions in a *cacher.Cacher. A session expires
+// after an idle timeout without requests and, optionally, after a maximum lifetime;
+// the cookie holds only the session ID.
+//
+// Manager is server-side session middleware for net/http:
+//
+//	sessions := cachersession.NewManager(cache, cachersession.Options{IdleTimeout: 30 * time.Minute})
+//	mux.Handle("/", sessions.Middleware(handler))
+//	...
+//	s := cachersession.FromContext(r.Context())
+//	s.Set("user", userID)
+//
+// Store implements the gorilla/sessions Store interface.
+//
+// Sessions are not locked across requests: if two requests change the same session
+// at the same time, the last one to finish wins.
+package cachersession
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+// DefaultCookieName is the name of the session cookie if Options.CookieName is empty.
+const DefaultCookieName = "session_id"
+
+// Options configures a Manager or a Store.
+type Options struct {
+	// CookieName is the name of the Manager's session cookie. If empty, defaults
+	// to DefaultCookieName. Store uses the session names instead.
+	CookieName string
+
+	// IdleTimeout is how long a session lives without requests. 0 means no idle timeout.
+	IdleTimeout time.Duration
+
+	// MaxLifetime is how long a session lives after it is created, however active it is.
+	// It is also the Max-Age of the cookie. 0 means no limit and a browser session cookie.
+	MaxLifetime time.Duration
+
+	// Cookie attributes. Path defaults to "/". Cookies are always HttpOnly.
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// sessionKey is the cache key of a session. Its type keeps sessions apart
+// from other keys in a shared cacher.
+type sessionKey string
+
+// Manager loads and saves the sessions of the requests passing its middleware.
+type Manager struct {
+	cache *cacher.Cacher
+	opts  Options
+}
+
+// NewManager creates a Manager keeping sessions in cache.
+func NewManager(cache *cacher.Cacher, opts Options) *Manager {
+	if opts.CookieName == "" {
+		opts.CookieName = DefaultCookieName
+	}
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	return &Manager{cache: cache, opts: opts}
+}
+
+// Session is the session of a request. It is safe for concurrent use.
+type Session struct {
+	mu      sync.Mutex
+	id      string
+	oldID   string // ID replaced by Renew, deleted on save
+	values  map[string]interface{}
+	dirty   bool
+	cookie  bool // the client holds a cookie with the current ID
+	deleted bool
+}
+
+type contextKey struct{}
+
+// FromContext returns the session of a request handled by Manager.Middleware,
+// or nil if there is none.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(contextKey{}).(*Session)
+	return s
+}
+
+// ID returns the session ID. It is empty for a new session until it is saved.
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+// Get returns a session value, or nil if it is not set.
+func (s *Session) Get(name string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[name]
+}
+
+// Set sets a session value.
+func (s *Session) Set(name string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = value
+	s.dirty = true
+	s.deleted = false
+}
+
+// Delete removes a session value.
+func (s *Session) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[name]; ok {
+		delete(s.values, name)
+		s.dirty = true
+	}
+}
+
+// Renew moves the session to a new ID, e.g. after a login to prevent session fixation.
+// Like Destroy, it must be called before the response is written.
+func (s *Session) Renew() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.oldID == "" {
+		s.oldID = s.id
+	}
+	s.id = ""
+	s.cookie = false
+	s.dirty = true
+}
+
+// Destroy removes all values, deletes the session from the cache and expires its cookie.
+// Setting a value afterwards starts a new session.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.oldID == "" {
+		s.oldID = s.id
+	}
+	s.id = ""
+	s.values = make(map[string]interface{})
+	s.cookie = false
+	s.dirty = false
+	s.deleted = true
+}
+
+// Middleware returns middleware that loads the session of each request into its context
+// and saves it when the response is written and when the handler returns.
+// A session is stored and its cookie set only once a value is set.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := m.load(r)
+		sw := &writer{ResponseWriter: w, m: m, s: s}
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), contextKey{}, s)))
+		// Once the response is written, changes are still stored but no cookie can be set
+		m.save(w, s, !sw.wrote)
+	})
+}
+
+// load returns the stored session of a request, or a new one.
+func (m *Manager) load(r *http.Request) *Session {
+	if c, err := r.Cookie(m.opts.CookieName); err == nil && c.Value != "" {
+		// Get counts as an access, so every request extends the idle timeout
+		if value, err := m.cache.Get(sessionKey(c.Value)); err == nil {
+			if values, ok := value.(map[string]interface{}); ok {
+				return &Session{id: c.Value, values: copyValues(values), cookie: true}
+			}
+		}
+	}
+	return &Session{values: make(map[string]interface{})}
+}
+
+// save stores a changed session or deletes a destroyed one.
+// If setCookie is true, it also sets or expires the cookie.
+func (m *Manager) save(w http.ResponseWriter, s *Session, setCookie bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.oldID != "" && (s.dirty || s.deleted) {
+		m.cache.Delete(sessionKey(s.oldID))
+		s.oldID = ""
+	}
+	if s.deleted {
+		if setCookie {
+			m.setCookie(w, "", -1)
+			s.deleted = false
+		}
+		return
+	}
+	if !s.dirty {
+		return
+	}
+
+	if s.id == "" {
+		s.id = newID()
+	}
+	store(m.cache, sessionKey(s.id), copyValues(s.values), m.opts)
+	s.dirty = false
+	if setCookie && !s.cookie {
+		m.setCookie(w, s.id, int(m.opts.MaxLifetime/time.Second))
+		s.cookie = true
+	}
+}
+
+func (m *Manager) setCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.opts.CookieName,
+		Value:    value,
+		Path:     m.opts.Path,
+		Domain:   m.opts.Domain,
+		MaxAge:   maxAge,
+		Secure:   m.opts.Secure,
+		HttpOnly: true,
+		SameSite: m.opts.SameSite,
+	})
+}
+
+// writer saves the session before the response headers are sent,
+// so the session cookie can still be set.
+type writer struct {
+	http.ResponseWriter
+	m     *Manager
+	s     *Session
+	wrote bool
+}
+
+func (w *writer) commit() {
+	if !w.wrote {
+		w.m.save(w.ResponseWriter, w.s, true)
+		w.wrote = true
+	}
+}
+
+func (w *writer) WriteHeader(code int) {
+	w.commit()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *writer) Write(data []byte) (int, error) {
+	w.commit()
+	return w.ResponseWriter.Write(data)
+}
+
+// Unwrap lets http.ResponseController reach the client connection.
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// store saves session values, keeping the expiration of an existing session:
+// Update keeps the lifetime deadline, and reads extend the idle timeout.
+func store(cache *cacher.Cacher, key sessionKey, values interface{}, opts Options) {
+	if err := cache.Update(key, values); err != nil {
+		cache.SetWithLimits(key, values, opts.IdleTimeout, opts.MaxLifetime)
+	}
+}
+
+// newID returns a random URL-safe session ID.
+func newID() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package cachersession
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves handlers using the session behind the session middleware.
+func newTestServer(t *testing.T, opts Options) (*httptest.Server, *cacher.Cacher) {
+	cache := cacher.New(cacher.Config{})
+	m := NewManager(cache, opts)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/set", func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Set("user", r.URL.Query().Get("user"))
+	})
+	mux.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, FromContext(r.Context()).Get("user"))
+	})
+	mux.HandleFunc("/renew", func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Renew()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Destroy()
+	})
+	srv := httptest.NewServer(m.Middleware(mux))
+
+	t.Cleanup(func() {
+		srv.Close()
+		cache.Close()
+	})
+	return srv, cache
+}
+
+// newClient returns a client keeping cookies like a browser.
+func newClient(t *testing.T) *http.Client {
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	return &http.Client{Jar: jar}
+}
+
+func request(t *testing.T, client *http.Client, url string) (string, *http.Response) {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp
+}
+
+func sessionCookie(resp *http.Response) *http.Cookie {
+	for _, c := range resp.Cookies() {
+		if c.Name == DefaultCookieName {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestManager_Session(t *testing.T) {
+	srv, cache := newTestServer(t, Options{IdleTimeout: time.Minute})
+	client := newClient(t)
+
+	// Пустая сессия не сохраняется и не ставит куку
+	body, resp := request(t, client, srv.URL+"/get")
+	assert.Equal(t, "<nil>", body)
+	assert.Nil(t, sessionCookie(resp))
+	assert.Equal(t, 0, cache.Metrics().Items)
+
+	_, resp = request(t, client, srv.URL+"/set?user=alice")
+	c := sessionCookie(resp)
+	require.NotNil(t, c)
+	assert.True(t, c.HttpOnly)
+	assert.Equal(t, "/", c.Path)
+
+	body, resp = request(t, client, srv.URL+"/get")
+	assert.Equal(t, "alice", body)
+	assert.Nil(t, sessionCookie(resp)) // кука уже есть у клиента
+
+	// Новый ID после Renew, старый удаляется
+	_, resp = request(t, client, srv.URL+"/renew")
+	renewed := sessionCookie(resp)
+	require.NotNil(t, renewed)
+	assert.NotEqual(t, c.Value, renewed.Value)
+	_, err := cache.Get(sessionKey(c.Value))
+	assert.Error(t, err)
+	body, _ = request(t, client, srv.URL+"/get")
+	assert.Equal(t, "alice", body)
+
+	_, resp = request(t, client, srv.URL+"/logout")
+	expired := sessionCookie(resp)
+	require.NotNil(t, expired)
+	assert.Equal(t, -1, expired.MaxAge)
+	assert.Equal(t, 0, cache.Metrics().Items)
+	body, _ = request(t, client, srv.URL+"/get")
+	assert.Equal(t, "<nil>", body)
+}
+
+func TestManager_IdleTimeout(t *testing.T) {
+	srv, _ := newTestServer(t, Options{IdleTimeout: 150 * time.Millisecond})
+	client := newClient(t)
+
+	request(t, client, srv.URL+"/set?user=bob")
+
+	// Каждый запрос продлевает сессию
+	for i := 0; i < 4; i++ {
+		time.Sleep(60 * time.Millisecond)
+		body, _ := request(t, client, srv.URL+"/get")
+		require.Equal(t, "bob", body)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	body, _ := request(t, client, srv.URL+"/get")
+	assert.Equal(t, "<nil>", body)
+}
+
+func TestManager_MaxLifetime(t *testing.T) {
+	srv, _ := newTestServer(t, Options{IdleTimeout: time.Minute, MaxLifetime: 2 * time.Second})
+	client := newClient(t)
+
+	_, resp := request(t, client, srv.URL+"/set?user=carol")
+	assert.Equal(t, 2, sessionCookie(resp).MaxAge)
+}
+
+func TestSession_Copy(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	m := NewManager(cache, Options{})
+
+	var s *Session
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s = FromContext(r.Context())
+		s.Set("a", 1)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Изменения после запроса не попадают в кэш
+	s.Set("a", 2)
+	value, err := cache.Get(sessionKey(s.ID()))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1}, value)
+}
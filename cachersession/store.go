@@ -0,0 +1,110 @@
+package cachersession
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+// Store is a gorilla/sessions Store keeping session values in a *cacher.Cacher
+// and only the signed session ID in the cookie:
+//
+//	store := cachersession.NewStore(cache, cachersession.Options{IdleTimeout: 30 * time.Minute}, hashKey)
+//	session, err := store.Get(r, "app")
+//	session.Values["user"] = userID
+//	err = session.Save(r, w)
+//
+// Setting Options.MaxAge of a session to a negative value and saving it deletes the session.
+type Store struct {
+	Codecs  []securecookie.Codec
+	Options *sessions.Options // Default options of new sessions
+
+	cache *cacher.Cacher
+	opts  Options
+}
+
+// NewStore creates a Store keeping sessions in cache. keyPairs are
+// hash and encryption key pairs for the session ID cookie, see securecookie.CodecsFromPairs.
+func NewStore(cache *cacher.Cacher, opts Options, keyPairs ...[]byte) *Store {
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	return &Store{
+		Codecs: securecookie.CodecsFromPairs(keyPairs...),
+		Options: &sessions.Options{
+			Path:     opts.Path,
+			Domain:   opts.Domain,
+			MaxAge:   int(opts.MaxLifetime / time.Second),
+			Secure:   opts.Secure,
+			HttpOnly: true,
+			SameSite: opts.SameSite,
+		},
+		cache: cache,
+		opts:  opts,
+	}
+}
+
+// Get returns the session of a request, caching it in the request's registry.
+func (s *Store) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New returns the stored session named by the request's cookie, or a new session.
+// If the cookie cannot be decoded, it returns a new session and the decoding error.
+func (s *Store) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	c, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	var id string
+	if err := securecookie.DecodeMulti(name, c.Value, &id, s.Codecs...); err != nil {
+		return session, err
+	}
+	// Get counts as an access, so every request extends the idle timeout
+	if value, err := s.cache.Get(sessionKey(id)); err == nil {
+		if values, ok := value.(map[interface{}]interface{}); ok {
+			session.ID = id
+			session.IsNew = false
+			for k, v := range values {
+				session.Values[k] = v
+			}
+		}
+	}
+	return session, nil
+}
+
+// Save stores the session and sets its cookie, or deletes the session
+// and expires its cookie if Options.MaxAge is negative.
+func (s *Store) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			s.cache.Delete(sessionKey(session.ID))
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if session.ID == "" {
+		session.ID = newID()
+	}
+	values := make(map[interface{}]interface{}, len(session.Values))
+	for k, v := range session.Values {
+		values[k] = v
+	}
+	store(s.cache, sessionKey(session.ID), values, s.opts)
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.Codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
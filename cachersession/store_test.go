@@ -0,0 +1,88 @@
+package cachersession
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ sessions.Store = (*Store)(nil)
+
+func TestStore(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	store := NewStore(cache, Options{IdleTimeout: time.Minute}, []byte("hash-key-hash-key-hash-key-12345"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	session, err := store.Get(req, "app")
+	require.NoError(t, err)
+	assert.True(t, session.IsNew)
+	session.Values["user"] = "alice"
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, session.Save(req, rec))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.NotContains(t, cookies[0].Value, "alice") // в куке только подписанный ID
+	assert.True(t, cookies[0].HttpOnly)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	loaded, err := store.Get(req, "app")
+	require.NoError(t, err)
+	assert.False(t, loaded.IsNew)
+	assert.Equal(t, session.ID, loaded.ID)
+	assert.Equal(t, "alice", loaded.Values["user"])
+
+	// Отрицательный MaxAge удаляет сессию
+	loaded.Options.MaxAge = -1
+	rec = httptest.NewRecorder()
+	require.NoError(t, loaded.Save(req, rec))
+	assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+	assert.Equal(t, 0, cache.Metrics().Items)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	loaded, err = store.New(req, "app")
+	require.NoError(t, err)
+	assert.True(t, loaded.IsNew)
+	assert.Empty(t, loaded.Values)
+}
+
+func TestStore_InvalidCookie(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	store := NewStore(cache, Options{}, []byte("hash-key-hash-key-hash-key-12345"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "app", Value: "forged"})
+	session, err := store.New(req, "app")
+	assert.Error(t, err)
+	assert.True(t, session.IsNew)
+	assert.Empty(t, session.ID)
+}
+
+func TestStore_IdleTimeout(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	store := NewStore(cache, Options{IdleTimeout: 100 * time.Millisecond}, []byte("hash-key-hash-key-hash-key-12345"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	session, _ := store.New(req, "app")
+	session.Values["user"] = "bob"
+	rec := httptest.NewRecorder()
+	require.NoError(t, store.Save(req, rec, session))
+
+	time.Sleep(200 * time.Millisecond)
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	loaded, err := store.New(req, "app")
+	require.NoError(t, err)
+	assert.True(t, loaded.IsNew)
+}
@@ -0,0 +1,232 @@
+// Package cachersql is a cacher.Backend that stores items in a SQL table,
+// for durable caching in an embedded SQLite database without running a separate server.
+//
+// Items are kept with their expiration time, and expired rows are deleted
+// periodically. Keys and values are gob-encoded, so custom types must be
+// registered with gob.Register.
+package cachersql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+var (
+	defaultTable          = "cache"
+	defaultVacuumInterval = time.Minute
+)
+
+// Options configures a Store.
+type Options struct {
+	// Table is the name of the table holding the items. It is created if it does not exist.
+	// If empty, defaults to "cache".
+	Table string
+
+	// VacuumInterval is how often expired rows are deleted.
+	// If 0, defaults to 1 minute. If negative, expired rows are only deleted when read.
+	VacuumInterval time.Duration
+}
+
+// entry is the stored representation of a key and its value.
+type entry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// Store is a cacher.Backend over a SQL database with SQLite syntax.
+type Store struct {
+	db      *sql.DB
+	ownsDB  bool
+	queries queries
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// queries are the statements for the configured table.
+type queries struct {
+	get, set, delete, keys, vacuum string
+}
+
+// Open opens or creates a SQLite database file at path and stores items in it.
+func Open(path string, opts Options) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite store: %w", err)
+	}
+	db.SetMaxOpenConns(1) // SQLite allows a single writer
+	s, err := New(db, opts)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s.ownsDB = true
+	return s, nil
+}
+
+// New stores items in an open database, creating the table if needed.
+// The database is not closed by Close. Limit SQLite databases to one open
+// connection or set a busy timeout, since concurrent writes otherwise fail with SQLITE_BUSY.
+func New(db *sql.DB, opts Options) (*Store, error) {
+	if opts.Table == "" {
+		opts.Table = defaultTable
+	}
+	if opts.VacuumInterval == 0 {
+		opts.VacuumInterval = defaultVacuumInterval
+	}
+
+	table := `"` + strings.ReplaceAll(opts.Table, `"`, `""`) + `"`
+	schema := "CREATE TABLE IF NOT EXISTS " + table + ` (
+		key TEXT PRIMARY KEY,
+		value BLOB NOT NULL,
+		expires_at INTEGER NOT NULL DEFAULT 0
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("create table %s: %w", table, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Store{
+		db: db,
+		queries: queries{
+			get: "SELECT value, expires_at FROM " + table + " WHERE key = ?",
+			set: "INSERT INTO " + table + " (key, value, expires_at) VALUES (?, ?, ?) " +
+				"ON CONFLICT (key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at",
+			delete: "DELETE FROM " + table + " WHERE key = ?",
+			keys:   "SELECT value FROM " + table + " WHERE expires_at = 0 OR expires_at > ?",
+			vacuum: "DELETE FROM " + table + " WHERE expires_at != 0 AND expires_at <= ?",
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	if opts.VacuumInterval > 0 {
+		go s.startVacuum(ctx, opts.VacuumInterval)
+	} else {
+		close(s.done)
+	}
+	return s, nil
+}
+
+// Get reads an item. Expired items are deleted and reported as missing.
+func (s *Store) Get(key interface{}) (interface{}, time.Duration, bool, error) {
+	var data []byte
+	var expiresAt int64
+	err := s.db.QueryRow(s.queries.get, encodeKey(key)).Scan(&data, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	var ttl time.Duration
+	if expiresAt != 0 {
+		ttl = time.Until(time.Unix(0, expiresAt))
+		if ttl <= 0 {
+			return nil, 0, false, s.Delete(key)
+		}
+	}
+	var e entry
+	if err := decode(data, &e); err != nil {
+		return nil, 0, false, err
+	}
+	return e.Value, ttl, true, nil
+}
+
+// Set writes an item, replacing any previous value.
+func (s *Store) Set(key, value interface{}, ttl time.Duration) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&entry{Key: key, Value: value}); err != nil {
+		return fmt.Errorf("encode %v: %w", key, err)
+	}
+	var expiresAt int64
+	if ttl != 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+	_, err := s.db.Exec(s.queries.set, encodeKey(key), buf.Bytes(), expiresAt)
+	return err
+}
+
+// Delete removes an item. Deleting a missing key is not an error.
+func (s *Store) Delete(key interface{}) error {
+	_, err := s.db.Exec(s.queries.delete, encodeKey(key))
+	return err
+}
+
+// Keys returns the keys of all unexpired items. The cache calls it when it is created,
+// so items written before a restart can be restored.
+func (s *Store) Keys() ([]interface{}, error) {
+	rows, err := s.db.Query(s.queries.keys, time.Now().UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []interface{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var e entry
+		if err := decode(data, &e); err != nil {
+			return nil, err
+		}
+		keys = append(keys, e.Key)
+	}
+	return keys, rows.Err()
+}
+
+// Vacuum deletes expired rows and returns how many were deleted.
+func (s *Store) Vacuum(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, s.queries.vacuum, time.Now().UnixNano())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Close stops the periodic vacuum and closes the database if it was opened by Open.
+func (s *Store) Close() error {
+	s.cancel()
+	<-s.done
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// startVacuum deletes expired rows every interval until ctx is canceled.
+func (s *Store) startVacuum(ctx context.Context, interval time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Vacuum(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// encodeKey returns the column value for a cache key.
+func encodeKey(key interface{}) string {
+	return fmt.Sprintf("%T:%v", key, key)
+}
+
+func decode(data []byte, e *entry) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(e); err != nil {
+		return fmt.Errorf("decode sqlite store entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package cachersql
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_SetGetDelete(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "cache.db"), Options{})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Set("k1", "v1", 0))
+	require.NoError(t, s.Set(2, []byte("v2"), time.Hour))
+	require.NoError(t, s.Set("k1", "v1 updated", 0))
+
+	value, ttl, ok, err := s.Get("k1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v1 updated", value)
+	assert.Zero(t, ttl)
+
+	value, ttl, ok, err = s.Get(2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v2"), value)
+	assert.InDelta(t, float64(time.Hour), float64(ttl), float64(time.Second))
+
+	_, _, ok, err = s.Get("2") // ключи разных типов не совпадают
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, s.Delete("k1"))
+	require.NoError(t, s.Delete("missing"))
+	_, _, ok, err = s.Get("k1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	keys, err := s.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{2}, keys)
+}
+
+func TestStore_Vacuum(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	s, err := New(db, Options{Table: "items", VacuumInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, s.Set("short", "v", time.Millisecond))
+	require.NoError(t, s.Set("long", "v", time.Hour))
+
+	// фоновая очистка удаляет истёкшие строки
+	assert.Eventually(t, func() bool {
+		var count int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM "items"`).Scan(&count))
+		return count == 1
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Close())
+
+	removed, err := s.Vacuum(context.Background()) // New не закрывает чужую базу
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
+
+func TestStore_CacheRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	s, err := Open(path, Options{})
+	require.NoError(t, err)
+	cache := cacher.New(cacher.Config{Capacity: 1, Overflow: s})
+	cache.Set("k1", "v1", time.Hour)
+	cache.Set("k2", "v2", time.Hour) // k1 уходит в SQLite
+	cache.Close()
+	require.NoError(t, s.Close())
+
+	s, err = Open(path, Options{})
+	require.NoError(t, err)
+	defer s.Close()
+	cache = cacher.New(cacher.Config{Capacity: 1, Overflow: s})
+	defer cache.Close()
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+}
@@ -0,0 +1,281 @@
+// Package cacherstr is a cache specialized for string keys and []byte values.
+//
+// Entries are copied into large preallocated byte buffers and indexed by key hash,
+// so the cache holds no per-entry pointers for the garbage collector to scan and
+// Set does not allocate. When a buffer is full, the oldest entries are overwritten
+// (FIFO eviction). Expired entries are removed lazily on access. Keys are identified
+// by a 64-bit hash, so two keys with the same hash replace each other.
+package cacherstr
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	defaultMaxBytes = 64 << 20
+	defaultShards   = 16
+)
+
+// headerSize is the size of an entry header: expiration (8), hash (8), key length (2), value length (4).
+const headerSize = 22
+
+// ErrTooLarge is returned by Set when an entry does not fit in a shard.
+var ErrTooLarge = errors.New("cacherstr: entry too large")
+
+// Config holds configuration for the cache.
+type Config struct {
+	// MaxBytes is the total size of the entry buffers, split evenly between shards.
+	// Each entry takes 22 bytes plus the length of its key and value.
+	// If 0, defaults to 64 MiB.
+	MaxBytes int
+
+	// Shards is the number of independently locked buffers.
+	// More shards reduce lock contention. If 0, defaults to 16.
+	Shards int
+}
+
+// Cache is a thread-safe cache of []byte values by string key.
+type Cache struct {
+	shards []*shard
+}
+
+// shard is a ring buffer of entries with an index by key hash.
+type shard struct {
+	mu      sync.RWMutex
+	index   map[uint64]int // Key hash -> entry offset
+	buf     []byte
+	head    int  // Offset of the oldest entry
+	tail    int  // Offset to write the next entry at
+	wrapEnd int  // End of the entries before the wrap, if wrapped
+	wrapped bool // Entries continue from the start of buf
+	entries int  // Entries in buf, including overwritten and deleted ones
+}
+
+// New creates a cache with the given configuration.
+func New(cfg Config) *Cache {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultMaxBytes
+	}
+	if cfg.Shards <= 0 {
+		cfg.Shards = defaultShards
+	}
+
+	c := &Cache{shards: make([]*shard, cfg.Shards)}
+	for i := range c.shards {
+		c.shards[i] = &shard{
+			index: make(map[uint64]int),
+			buf:   make([]byte, cfg.MaxBytes/cfg.Shards),
+		}
+	}
+	return c
+}
+
+// Get returns a copy of the value for a key.
+// ok is false if the key is not found or has expired.
+func (c *Cache) Get(key string) (value []byte, ok bool) {
+	value, ok = c.Append(nil, key)
+	if ok && value == nil {
+		value = []byte{}
+	}
+	return value, ok
+}
+
+// Append appends the value for a key to dst and returns the extended slice,
+// so callers can reuse a buffer instead of allocating on every read.
+// ok is false if the key is not found or has expired.
+func (c *Cache) Append(dst []byte, key string) ([]byte, bool) {
+	hash := hashString(key)
+	s := c.shard(hash)
+
+	s.mu.RLock()
+	offset, ok := s.index[hash]
+	if !ok {
+		s.mu.RUnlock()
+		return dst, false
+	}
+	entry := s.entry(offset)
+	if !hasKey(entry, key) {
+		s.mu.RUnlock()
+		return dst, false
+	}
+	if expired(entry, time.Now()) {
+		s.mu.RUnlock()
+		s.remove(hash, offset)
+		return dst, false
+	}
+	dst = append(dst, entryValue(entry)...)
+	s.mu.RUnlock()
+	return dst, true
+}
+
+// Set stores a copy of the value with a TTL. A TTL of 0 means no expiration.
+// Returns ErrTooLarge if the entry does not fit in a shard.
+func (c *Cache) Set(key string, value []byte, ttl time.Duration) error {
+	if len(key) > 0xffff {
+		return ErrTooLarge
+	}
+	hash := hashString(key)
+	s := c.shard(hash)
+	size := headerSize + len(key) + len(value)
+
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if size > len(s.buf) {
+		return ErrTooLarge
+	}
+	offset := s.reserve(size)
+	entry := s.buf[offset : offset+size]
+	binary.LittleEndian.PutUint64(entry[0:], uint64(expiresAt))
+	binary.LittleEndian.PutUint64(entry[8:], hash)
+	binary.LittleEndian.PutUint16(entry[16:], uint16(len(key)))
+	binary.LittleEndian.PutUint32(entry[18:], uint32(len(value)))
+	copy(entry[headerSize:], key)
+	copy(entry[headerSize+len(key):], value)
+
+	s.index[hash] = offset
+	s.entries++
+	return nil
+}
+
+// Delete removes a key. Reports whether the key was present.
+func (c *Cache) Delete(key string) bool {
+	hash := hashString(key)
+	s := c.shard(hash)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, ok := s.index[hash]
+	if !ok || !hasKey(s.entry(offset), key) {
+		return false
+	}
+	delete(s.index, hash)
+	return true
+}
+
+// Len returns the number of keys in the cache, including expired ones not yet removed.
+func (c *Cache) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		s.mu.RLock()
+		n += len(s.index)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Clear removes all entries.
+func (c *Cache) Clear() {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		clear(s.index)
+		s.head, s.tail, s.wrapEnd, s.wrapped, s.entries = 0, 0, 0, false, 0
+		s.mu.Unlock()
+	}
+}
+
+// shard returns the shard for a key hash.
+func (c *Cache) shard(hash uint64) *shard {
+	return c.shards[hash%uint64(len(c.shards))]
+}
+
+// reserve returns the offset of size free bytes, overwriting the oldest entries if needed.
+func (s *shard) reserve(size int) int {
+	for {
+		if s.entries == 0 {
+			s.head, s.tail, s.wrapEnd, s.wrapped = 0, 0, 0, false
+		}
+
+		free := len(s.buf) - s.tail
+		if s.wrapped {
+			free = s.head - s.tail
+		}
+		if free >= size {
+			offset := s.tail
+			s.tail += size
+			return offset
+		}
+
+		if !s.wrapped {
+			s.wrapEnd, s.tail, s.wrapped = s.tail, 0, true
+			continue
+		}
+		s.evictHead()
+	}
+}
+
+// evictHead drops the oldest entry.
+func (s *shard) evictHead() {
+	entry := s.entry(s.head)
+	if offset, ok := s.index[entryHash(entry)]; ok && offset == s.head {
+		delete(s.index, entryHash(entry))
+	}
+	s.head += len(entry)
+	s.entries--
+
+	if s.head >= s.wrapEnd {
+		s.head, s.wrapped = 0, false
+	}
+}
+
+// remove deletes an expired entry if the index still points to it.
+func (s *shard) remove(hash uint64, offset int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.index[hash]; ok && current == offset {
+		delete(s.index, hash)
+	}
+}
+
+// entry returns the bytes of the entry at offset.
+func (s *shard) entry(offset int) []byte {
+	header := s.buf[offset:]
+	keyLen := int(binary.LittleEndian.Uint16(header[16:]))
+	valueLen := int(binary.LittleEndian.Uint32(header[18:]))
+	return s.buf[offset : offset+headerSize+keyLen+valueLen]
+}
+
+func entryHash(entry []byte) uint64 {
+	return binary.LittleEndian.Uint64(entry[8:])
+}
+
+// hasKey reports whether an entry is stored under key. The comparison does not allocate.
+func hasKey(entry []byte, key string) bool {
+	keyLen := int(binary.LittleEndian.Uint16(entry[16:]))
+	return string(entry[headerSize:headerSize+keyLen]) == key
+}
+
+func entryValue(entry []byte) []byte {
+	keyLen := int(binary.LittleEndian.Uint16(entry[16:]))
+	return entry[headerSize+keyLen:]
+}
+
+// expired reports whether an entry has expired at now.
+func expired(entry []byte, now time.Time) bool {
+	expiresAt := int64(binary.LittleEndian.Uint64(entry[0:]))
+	return expiresAt != 0 && now.UnixNano() > expiresAt
+}
+
+// hashString returns the FNV-1a hash of a string without allocating.
+func hashString(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	hash := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint64(s[i])
+		hash *= prime64
+	}
+	return hash
+}
@@ -0,0 +1,140 @@
+package cacherstr
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_SetGetDelete(t *testing.T) {
+	c := New(Config{MaxBytes: 1 << 20, Shards: 4})
+
+	require.NoError(t, c.Set("key1", []byte("value1"), 0))
+	got, ok := c.Get("key1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("value1"), got)
+
+	// Перезапись ключа
+	require.NoError(t, c.Set("key1", []byte("value2"), 0))
+	got, ok = c.Get("key1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("value2"), got)
+	assert.Equal(t, 1, c.Len())
+
+	buf, ok := c.Append([]byte("v="), "key1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("v=value2"), buf)
+
+	assert.True(t, c.Delete("key1"))
+	assert.False(t, c.Delete("key1"))
+	_, ok = c.Get("key1")
+	assert.False(t, ok)
+}
+
+func TestCache_TTL(t *testing.T) {
+	c := New(Config{MaxBytes: 1 << 20})
+
+	require.NoError(t, c.Set("key1", []byte("value1"), 10*time.Millisecond))
+	_, ok := c.Get("key1")
+	assert.True(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = c.Get("key1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_EvictsOldest(t *testing.T) {
+	// Один шард на 10 записей по 32 байта
+	c := New(Config{MaxBytes: 320, Shards: 1})
+
+	for i := 0; i < 25; i++ {
+		require.NoError(t, c.Set(fmt.Sprintf("key%02d", i), []byte("01234"), 0))
+	}
+	assert.Equal(t, 10, c.Len())
+	for i := 0; i < 15; i++ {
+		_, ok := c.Get(fmt.Sprintf("key%02d", i))
+		assert.False(t, ok, i)
+	}
+	for i := 15; i < 25; i++ {
+		got, ok := c.Get(fmt.Sprintf("key%02d", i))
+		assert.True(t, ok, i)
+		assert.Equal(t, []byte("01234"), got)
+	}
+
+	assert.ErrorIs(t, c.Set("big", make([]byte, 400), 0), ErrTooLarge)
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_MixedSizes(t *testing.T) {
+	c := New(Config{MaxBytes: 1000, Shards: 1})
+
+	// Записи разного размера заставляют буфер переходить через край
+	for i := 0; i < 500; i++ {
+		key := strconv.Itoa(i)
+		value := make([]byte, i%97)
+		for j := range value {
+			value[j] = byte(i)
+		}
+		require.NoError(t, c.Set(key, value, 0))
+
+		got, ok := c.Get(key)
+		require.True(t, ok, i)
+		assert.Equal(t, value, got)
+	}
+}
+
+func BenchmarkCacherstr_Set(b *testing.B) {
+	c := New(Config{MaxBytes: 32 << 20})
+	keys := benchmarkKeys(10000)
+	value := make([]byte, 128)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.Set(keys[i%len(keys)], value, time.Minute)
+	}
+}
+
+func BenchmarkCacherstr_Get(b *testing.B) {
+	c := New(Config{MaxBytes: 32 << 20})
+	keys := benchmarkKeys(10000)
+	for _, key := range keys {
+		_ = c.Set(key, make([]byte, 128), time.Minute)
+	}
+	buf := make([]byte, 0, 128)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = c.Append(buf[:0], keys[i%len(keys)])
+	}
+}
+
+func BenchmarkCacher_Set(b *testing.B) {
+	c := cacher.New(cacher.Config{Capacity: 10000, ClearingInterval: -1})
+	defer c.Close()
+	keys := benchmarkKeys(10000)
+	value := make([]byte, 128)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Set(keys[i%len(keys)], value, time.Minute)
+	}
+}
+
+func benchmarkKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "key:" + strconv.Itoa(i)
+	}
+	return keys
+}
@@ -0,0 +1,26 @@
+package cacher
+
+// ItemFunc is called with the key and value of an item removed from the cache.
+type ItemFunc func(key, value interface{})
+
+// notifyEvicted calls OnEvicted for an item evicted by capacity.
+func (c *Cacher) notifyEvicted(key interface{}, item cache) {
+	c.notify(c.onEvicted, key, item)
+}
+
+// notifyExpired calls OnExpired for an item removed because it expired.
+func (c *Cacher) notifyExpired(key interface{}, item cache) {
+	c.notify(c.onExpired, key, item)
+}
+
+// notify queues an item callback to run outside the lock, so it may use the cache.
+// Negative entries have no value and are not reported.
+func (c *Cacher) notify(fn ItemFunc, key interface{}, item cache) {
+	if fn == nil || item.negative {
+		return
+	}
+	value := c.load(item)
+	c.dispatcher.dispatch(func() {
+		fn(key, value)
+	})
+}
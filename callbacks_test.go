@@ -0,0 +1,121 @@
+package cacher
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects callback calls.
+type recorder struct {
+	mu    sync.Mutex
+	items map[interface{}]interface{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{items: make(map[interface{}]interface{})}
+}
+
+func (r *recorder) record(key, value interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[key] = value
+}
+
+func (r *recorder) get() map[interface{}]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make(map[interface{}]interface{}, len(r.items))
+	for k, v := range r.items {
+		items[k] = v
+	}
+	return items
+}
+
+func TestCacher_OnEvictedOnExpired(t *testing.T) {
+	evicted, expired := newRecorder(), newRecorder()
+	cache := New(Config{
+		Capacity:         1,
+		ClearingInterval: -1,
+		OnEvicted:        evicted.record,
+		OnExpired:        expired.record,
+	})
+	defer cache.Close()
+
+	cache.Set("key1", "value1", 0)
+	cache.Set("key2", "value2", time.Millisecond) // вытесняет key1
+	time.Sleep(5 * time.Millisecond)
+	cache.DeleteExpired()
+
+	require.NoError(t, cache.Shutdown(context.Background()))
+	assert.Equal(t, map[interface{}]interface{}{"key1": "value1"}, evicted.get())
+	assert.Equal(t, map[interface{}]interface{}{"key2": "value2"}, expired.get())
+}
+
+func TestCacher_Shutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	evicted := newRecorder()
+	cache := New(Config{
+		ClearingInterval: time.Millisecond,
+		OnEvicted:        evicted.record,
+		FlushOnShutdown:  true,
+		SnapshotPath:     path,
+	})
+
+	cache.Set("key1", "value1", 0)
+	cache.Set("key2", "value2", 0)
+	require.NoError(t, cache.Shutdown(context.Background()))
+	require.NoError(t, cache.Shutdown(context.Background()))
+
+	// Все записи переданы в OnEvicted и удалены
+	assert.Len(t, evicted.get(), 2)
+	assert.Empty(t, cache.Items())
+
+	// Снимок сохранён до очистки
+	restored := New(Config{ClearingInterval: -1})
+	defer restored.Close()
+	require.NoError(t, restored.WarmFromFile(path))
+	assert.Len(t, restored.Items(), 2)
+}
+
+func TestCacher_ShutdownDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	cache := New(Config{
+		ClearingInterval: -1,
+		FlushOnShutdown:  true,
+		OnEvicted:        func(key, value interface{}) { <-release },
+	})
+	cache.Set("key1", "value1", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, cache.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestCacher_ExpiryTimer(t *testing.T) {
+	fired := make(chan interface{}, 3)
+	cache := New(Config{
+		ClearingInterval: time.Hour,
+		ExpiryTimer:      true,
+		OnExpired: func(key, value interface{}) {
+			fired <- value
+		},
+	})
+	defer cache.Close()
+
+	start := time.Now()
+	cache.Set("later", "v2", 60*time.Millisecond)
+	cache.Set("soon", "v1", 30*time.Millisecond) // становится первым в очереди
+
+	assert.Equal(t, "v1", <-fired)
+	assert.InDelta(t, 30*time.Millisecond, time.Since(start), float64(20*time.Millisecond))
+	assert.Equal(t, "v2", <-fired)
+	assert.InDelta(t, 60*time.Millisecond, time.Since(start), float64(20*time.Millisecond))
+	assert.Equal(t, 0, cache.Metrics().Items)
+}
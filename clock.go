@@ -0,0 +1,34 @@
+package cacher
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// now returns the current time, from the coarse clock if one is configured.
+func (c *Cacher) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return time.Unix(0, c.clock.Load())
+}
+
+// startClock starts a coarse clock updated every resolution until the cache is closed.
+func (c *Cacher) startClock(resolution time.Duration) {
+	c.clock = new(atomic.Int64)
+	c.clock.Store(time.Now().UnixNano())
+
+	go func() {
+		ticker := time.NewTicker(resolution)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				c.clock.Store(now.UnixNano())
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,136 @@
+package cacher
+
+import (
+	"bytes"
+	"slices"
+	"time"
+)
+
+// MergeFunc resolves a key present in both caches during Merge.
+// It receives the current value and the value from the other cache and returns the value to keep.
+type MergeFunc func(key, current, other interface{}) interface{}
+
+// Clone returns a new cache with the same configuration, including runtime changes
+// to the capacity, eviction policy, default TTL and clearing interval, and a copy of
+// the items with their TTLs, access order and statistics. []byte values and values
+// stored encoded or compressed are copied; other values are copied with the Cloner,
+// if any, and shared otherwise. Secondary indexes are rebuilt. Items in the overflow store, scheduled values and
+// tombstones are not copied. The clone has no overflow store, write-ahead log,
+// snapshots or Store, so it does not write to the files and stores of c.
+func (c *Cacher) Clone() *Cacher {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cfg := c.cfg
+	cfg.Capacity = c.capacity
+	cfg.EvictionPolicy = c.evictionPolicy
+	cfg.DefaultTTL = time.Duration(c.defaultTTL.Load())
+	cfg.ClearingInterval = c.clearingInterval
+	cfg.AdaptiveClearing = c.adaptiveClearing
+	cfg.MinClearingInterval = c.minClearing
+	cfg.MaxClearingInterval = c.maxClearing
+	cfg.Overflow = nil
+	cfg.WALPath = "" // Its writes would mix into the log of c
+	cfg.SnapshotPath = ""
+	cfg.SnapshotInterval = 0
+	cfg.SnapshotKeep = 0
+	cfg.Store = nil
+	clone := New(cfg)
+
+	clone.mu.Lock()
+	defer clone.mu.Unlock()
+
+	for e := c.keys.Back(); e != nil; e = e.Prev() {
+		key := e.Value
+		item := c.cache[key]
+		item.value = c.copyValue(item)
+		item.history = slices.Clone(item.history)
+		item.element = clone.keys.PushFront(key)
+
+		if item.protected {
+			clone.protectedCount++
+		}
+		if !item.pinned {
+			clone.trackPriority(item.priority, 1)
+		}
+		clone.cache[key] = item
+		clone.trackExpiry(key)
+	}
+	for name, idx := range c.indexes {
+		clone.indexes[name] = newIndex(idx.fn)
+	}
+	for key, item := range clone.cache {
+		clone.reindex(key, item)
+	}
+	clone.version = c.version
+	clone.counters = c.counters
+	clone.checkWatermarks()
+	return clone
+}
+
+// Merge copies the unexpired items of other into the cache, replacing items with
+// the same key, or keeping the value returned by resolve if it is not nil.
+// Merged items keep their TTLs and may evict items when the capacity is reached.
+func (c *Cacher) Merge(other *Cacher, resolve MergeFunc) {
+	if other == c {
+		return
+	}
+
+	type mergedItem struct {
+		key   interface{}
+		item  cache
+		value interface{}
+	}
+	other.mu.RLock()
+	items := make([]mergedItem, 0, len(other.cache))
+	for e := other.keys.Back(); e != nil; e = e.Prev() {
+		item := other.cache[e.Value]
+		if other.checkExpiration(item) != nil {
+			continue
+		}
+		items = append(items, mergedItem{key: e.Value, item: item, value: other.load(item)})
+	}
+	other.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, m := range items {
+		value := m.value
+		if old, ok := c.cache[m.key]; ok && resolve != nil && c.checkExpiration(old) == nil {
+			var current interface{}
+			if !old.negative {
+				current = c.load(old)
+			}
+			value = resolve(m.key, current, value)
+		}
+
+		item := c.pack(cache{
+			ttl:        m.item.ttl,
+			deadline:   m.item.deadline,
+			negative:   m.item.negative && value == nil,
+			recompute:  m.item.recompute,
+			counter:    m.item.counter,
+			createdAt:  m.item.createdAt,
+			lastUsedAt: m.item.lastUsedAt,
+			priority:   m.item.priority,
+			cloner:     m.item.cloner,
+		}, value)
+		c.dropSpilled(m.key)
+		c.set(m.key, item)
+	}
+}
+
+// copyValue returns a copy of an item's stored value.
+func (c *Cacher) copyValue(item cache) interface{} {
+	if b, ok := item.value.([]byte); ok {
+		return bytes.Clone(b)
+	}
+	if item.cloner != nil {
+		return item.cloner(item.value)
+	}
+	if c.cloner != nil {
+		return c.cloner(item.value)
+	}
+	return item.value
+}
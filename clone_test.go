@@ -0,0 +1,136 @@
+package cacher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Clone(t *testing.T) {
+	cache := New(Config{Capacity: 3, EvictionPolicy: LRU})
+	defer cache.Close()
+
+	data := []byte("abc")
+	cache.Set("k1", data, time.Minute)
+	cache.Set("k2", "v2", 20*time.Millisecond)
+	cache.Set("k3", "v3", 0)
+	cache.Get("k1") // k1 становится самым свежим
+	require.NoError(t, cache.SetCapacity(4))
+
+	clone := cache.Clone()
+	defer clone.Close()
+
+	assert.Equal(t, 4, clone.GetCapacity())
+	assert.Equal(t, "LRU", clone.GetEvictionPolicy())
+
+	got, err := clone.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abc"), got)
+	data[0] = 'x' // копия не зависит от исходного среза
+	got, _ = clone.Get("k1")
+	assert.Equal(t, []byte("abc"), got)
+
+	// изменения клона не затрагивают оригинал
+	require.NoError(t, clone.Delete("k3"))
+	_, err = cache.Get("k3")
+	assert.NoError(t, err)
+
+	// порядок доступа сохраняется: вытесняется k2, а не k1
+	require.NoError(t, clone.SetCapacity(2))
+	clone.Set("k4", "v4", 0)
+	_, err = clone.Get("k2")
+	assert.Error(t, err)
+	_, err = clone.Get("k1")
+	assert.NoError(t, err)
+
+	// TTL копируется
+	time.Sleep(30 * time.Millisecond)
+	expired := cache.Clone()
+	defer expired.Close()
+	_, err = expired.Get("k2")
+	assert.Error(t, err)
+}
+
+func TestCacher_CloneWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+	cache := New(Config{WALPath: path, WALSync: WALSyncAlways})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 0)
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// клон не пишет в журнал оригинала
+	clone := cache.Clone()
+	clone.Set("k2", "v2", 0)
+	require.NoError(t, clone.Delete("k1"))
+	clone.Close()
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestCacher_CloneSnapshotsAndStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+	store := newMemStore()
+	cache := New(Config{Store: store}, WithAutoSnapshot(time.Hour, path, 2))
+	defer cache.Close()
+
+	require.NoError(t, cache.Write("k1", "v1", 0))
+
+	// снимки и запись в Store остаются за оригиналом
+	clone := cache.Clone()
+	assert.ErrorIs(t, clone.Write("k2", "v2", 0), ErrNoStore)
+	require.NoError(t, clone.Shutdown(t.Context()))
+
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	_, ok := store.value("k2")
+	assert.False(t, ok)
+
+	got, err := clone.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+}
+
+func TestCacher_Merge(t *testing.T) {
+	a := New(Config{})
+	defer a.Close()
+	b := New(Config{})
+	defer b.Close()
+
+	a.Set("hits", 2, 0)
+	a.Set("only-a", "a", 0)
+	b.Set("hits", 3, 0)
+	b.Set("only-b", "b", 0)
+	b.Set("expired", "b", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	a.Merge(b, func(key, current, other interface{}) interface{} {
+		return current.(int) + other.(int)
+	})
+
+	got, err := a.Get("hits")
+	require.NoError(t, err)
+	assert.Equal(t, 5, got)
+	got, err = a.Get("only-b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", got)
+	_, err = a.Get("only-a")
+	assert.NoError(t, err)
+	_, err = a.Get("expired")
+	assert.Error(t, err)
+
+	// без функции значение другого кэша заменяет текущее
+	b.Set("hits", 10, 0)
+	a.Merge(b, nil)
+	got, _ = a.Get("hits")
+	assert.Equal(t, 10, got)
+
+	a.Merge(a, nil) // слияние с собой ничего не делает
+}
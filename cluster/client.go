@@ -0,0 +1,198 @@
+// Package cluster shards keys across several remote cache nodes using consistent hashing,
+// so the cache can grow beyond the memory of a single process.
+//
+// Each key is owned by one node. When the owner is unreachable, it is skipped for a while
+// and requests fail over to the next node on the ring.
+//
+// Pool uses the same ring in the other direction: instances with local caches ask
+// the owner of a key to load it on a miss, so the fleet loads each key once.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/danRulev/cacher/cachergrpc"
+	"google.golang.org/grpc"
+)
+
+var (
+	defaultVirtualNodes = 100
+	defaultRetryAfter   = 5 * time.Second
+)
+
+// ErrNoNodes is returned when no node is available to serve a request.
+var ErrNoNodes = errors.New("cluster: no available nodes")
+
+// Node is a remote cache instance. *cachergrpc.Client implements it.
+type Node interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Options configures a Client.
+type Options struct {
+	// VirtualNodes is the number of points each node takes on the ring.
+	// If 0, defaults to 100.
+	VirtualNodes int
+
+	// RetryAfter is how long a failed node is skipped before it is tried again.
+	// If 0, defaults to 5 seconds.
+	RetryAfter time.Duration
+}
+
+// Client routes cache operations to the node owning each key.
+type Client struct {
+	ring       *ring
+	nodes      map[string]Node
+	counters   map[string]*nodeCounters // Fixed at creation, so read without the lock
+	retryAfter time.Duration
+
+	mu   sync.Mutex
+	down map[string]time.Time // Node name -> time it may be retried
+}
+
+// New creates a client over named nodes.
+func New(nodes map[string]Node, opts Options) *Client {
+	if opts.VirtualNodes == 0 {
+		opts.VirtualNodes = defaultVirtualNodes
+	}
+	if opts.RetryAfter == 0 {
+		opts.RetryAfter = defaultRetryAfter
+	}
+
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	counters := make(map[string]*nodeCounters, len(nodes))
+	for name := range nodes {
+		counters[name] = new(nodeCounters)
+	}
+
+	return &Client{
+		ring:       newRing(names, opts.VirtualNodes),
+		nodes:      nodes,
+		counters:   counters,
+		retryAfter: opts.RetryAfter,
+		down:       make(map[string]time.Time),
+	}
+}
+
+// Dial connects to gRPC cache nodes at the given addresses.
+// Node names are their addresses.
+func Dial(addrs []string, opts Options, dialOpts ...grpc.DialOption) (*Client, error) {
+	nodes := make(map[string]Node, len(addrs))
+	for _, addr := range addrs {
+		client, err := cachergrpc.Dial(addr, dialOpts...)
+		if err != nil {
+			for _, node := range nodes {
+				node.(io.Closer).Close()
+			}
+			return nil, err
+		}
+		nodes[addr] = client
+	}
+	return New(nodes, opts), nil
+}
+
+// NodeFor returns the name of the node that owns key, ignoring failures.
+func (c *Client) NodeFor(key string) string {
+	owners := c.ring.lookup(key)
+	if len(owners) == 0 {
+		return ""
+	}
+	return owners[0]
+}
+
+// Get returns the value for a key from its node.
+// Returns cachergrpc.ErrNotFound if the key is not cached.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := c.do(ctx, key, func(node Node) error {
+		var err error
+		value, err = node.Get(ctx, key)
+		return err
+	})
+	return value, err
+}
+
+// Set stores a value on the node owning key.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.do(ctx, key, func(node Node) error {
+		return node.Set(ctx, key, value, ttl)
+	})
+}
+
+// Delete removes a key from its node.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	return c.do(ctx, key, func(node Node) error {
+		return node.Delete(ctx, key)
+	})
+}
+
+// Close closes every node that implements io.Closer.
+func (c *Client) Close() error {
+	var errs []error
+	for _, node := range c.nodes {
+		if closer, ok := node.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// do runs op on the first healthy node for key, failing over on node errors.
+func (c *Client) do(ctx context.Context, key string, op func(Node) error) error {
+	lastErr := ErrNoNodes
+	for i, name := range c.ring.lookup(key) {
+		if !c.available(name) {
+			continue
+		}
+
+		counters := c.counters[name]
+		counters.requests.Add(1)
+		if i > 0 {
+			counters.failovers.Add(1)
+		}
+		err := op(c.nodes[name])
+		if err == nil || errors.Is(err, cachergrpc.ErrNotFound) || ctx.Err() != nil {
+			return err
+		}
+		counters.errors.Add(1)
+		c.markDown(name)
+		lastErr = err
+	}
+	return lastErr
+}
+
+// available reports whether a node may be used.
+func (c *Client) available(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	until, ok := c.down[name]
+	if !ok {
+		return true
+	}
+	if time.Now().After(until) {
+		delete(c.down, name)
+		return true
+	}
+	return false
+}
+
+// markDown excludes a node until the retry period elapses.
+func (c *Client) markDown(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.down[name] = time.Now().Add(c.retryAfter)
+}
@@ -0,0 +1,116 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher/cachergrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNode is an in-memory Node that can be switched off.
+type fakeNode struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	failed bool
+}
+
+func newFakeNode() *fakeNode {
+	return &fakeNode{values: make(map[string][]byte)}
+}
+
+func (n *fakeNode) Get(_ context.Context, key string) ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failed {
+		return nil, errors.New("unavailable")
+	}
+	value, ok := n.values[key]
+	if !ok {
+		return nil, cachergrpc.ErrNotFound
+	}
+	return value, nil
+}
+
+func (n *fakeNode) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failed {
+		return errors.New("unavailable")
+	}
+	n.values[key] = value
+	return nil
+}
+
+func (n *fakeNode) Delete(_ context.Context, key string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failed {
+		return errors.New("unavailable")
+	}
+	delete(n.values, key)
+	return nil
+}
+
+func TestClient_Sharding(t *testing.T) {
+	nodes := map[string]*fakeNode{"a": newFakeNode(), "b": newFakeNode(), "c": newFakeNode()}
+	client := New(map[string]Node{"a": nodes["a"], "b": nodes["b"], "c": nodes["c"]}, Options{})
+	ctx := context.Background()
+
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		require.NoError(t, client.Set(ctx, key, []byte(key), 0))
+		_, ok := nodes[client.NodeFor(key)].values[key]
+		assert.True(t, ok)
+	}
+	for name, node := range nodes {
+		assert.NotEmpty(t, node.values, "node %s has no keys", name)
+	}
+
+	got, err := client.Get(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key-1"), got)
+
+	_, err = client.Get(ctx, "missing")
+	assert.ErrorIs(t, err, cachergrpc.ErrNotFound)
+}
+
+func TestClient_Failover(t *testing.T) {
+	a, b := newFakeNode(), newFakeNode()
+	client := New(map[string]Node{"a": a, "b": b}, Options{RetryAfter: time.Hour})
+	ctx := context.Background()
+
+	owner := map[string]*fakeNode{"a": a, "b": b}[client.NodeFor("k1")]
+	owner.failed = true
+
+	require.NoError(t, client.Set(ctx, "k1", []byte("v1"), 0))
+	got, err := client.Get(ctx, "k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), got)
+
+	a.failed, b.failed = true, true
+	_, err = client.Get(ctx, "k1")
+	assert.EqualError(t, err, "unavailable")
+	_, err = client.Get(ctx, "k1") // оба узла помечены как недоступные
+	assert.ErrorIs(t, err, ErrNoNodes)
+}
+
+func TestRing_MinimalRemap(t *testing.T) {
+	before := newRing([]string{"a", "b", "c", "d"}, 100)
+	after := newRing([]string{"a", "b", "c"}, 100)
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		owner := before.lookup(key)[0]
+		if owner != "d" && after.lookup(key)[0] != owner {
+			moved++
+		}
+	}
+	assert.Zero(t, moved) // ключи оставшихся узлов не перемещаются
+}
@@ -0,0 +1,267 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+const defaultBasePath = "/_cacher/"
+
+// ErrPeerLoad is returned by Pool.Get when the peer owning a key failed to load it.
+var ErrPeerLoad = errors.New("cluster: peer failed to load key")
+
+// Getter loads the value for a key from the system of record.
+type Getter func(ctx context.Context, key string) ([]byte, error)
+
+// PoolOptions configures a Pool.
+type PoolOptions struct {
+	// Self is the base URL of this node as its peers reach it, e.g. "http://10.0.0.1:8000".
+	Self string
+
+	// BasePath is the path the pool is served under. If empty, defaults to "/_cacher/".
+	BasePath string
+
+	// TTL is the TTL of values stored in the local cache. 0 means no expiration.
+	TTL time.Duration
+
+	// VirtualNodes is the number of points each peer takes on the ring.
+	// If 0, defaults to 100.
+	VirtualNodes int
+
+	// Client sends requests to peers. If nil, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// Pool fills cache misses cooperatively across a fleet of instances, in the style of groupcache.
+//
+// Each key is owned by one peer on a consistent hash ring. On a miss, the Pool asks the owner
+// over HTTP and only the owner calls the Getter, so the system of record is loaded once per key
+// for the whole fleet instead of once per instance. Concurrent misses for a key share a single
+// peer request, and concurrent requests from peers share a single Getter call.
+// If the owner cannot be reached, the value is loaded locally.
+//
+// The Pool is an http.Handler and must be served at Self + BasePath.
+type Pool struct {
+	cache    *cacher.Cacher
+	getter   Getter
+	self     string
+	basePath string
+	ttl      time.Duration
+	replicas int
+	client   *http.Client
+
+	mu   sync.RWMutex
+	ring *ring
+
+	fills flightGroup // Local misses
+	loads flightGroup // Getter calls
+}
+
+// NewPool creates a pool filling cache with getter. Until Set is called,
+// the node owns every key.
+func NewPool(cache *cacher.Cacher, getter Getter, opts PoolOptions) *Pool {
+	if opts.BasePath == "" {
+		opts.BasePath = defaultBasePath
+	}
+	if opts.VirtualNodes == 0 {
+		opts.VirtualNodes = defaultVirtualNodes
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &Pool{
+		cache:    cache,
+		getter:   getter,
+		self:     strings.TrimSuffix(opts.Self, "/"),
+		basePath: opts.BasePath,
+		ttl:      opts.TTL,
+		replicas: opts.VirtualNodes,
+		client:   opts.Client,
+		ring:     newRing(nil, opts.VirtualNodes),
+	}
+}
+
+// Set replaces the peers by their base URLs. The list should include Self
+// and be the same on every node, so they agree on the owner of each key.
+func (p *Pool) Set(peers ...string) {
+	names := make([]string, len(peers))
+	for i, peer := range peers {
+		names[i] = strings.TrimSuffix(peer, "/")
+	}
+	r := newRing(names, p.replicas)
+
+	p.mu.Lock()
+	p.ring = r
+	p.mu.Unlock()
+}
+
+// Owner returns the base URL of the peer owning key, or Self if there are no peers.
+func (p *Pool) Owner(key string) string {
+	p.mu.RLock()
+	owners := p.ring.lookup(key)
+	p.mu.RUnlock()
+
+	if len(owners) == 0 {
+		return p.self
+	}
+	return owners[0]
+}
+
+// Get returns the value for key from the local cache, the peer owning it or the Getter.
+// Errors are not cached.
+func (p *Pool) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, ok := p.cached(key); ok {
+		return value, nil
+	}
+	return p.fills.do(key, func() ([]byte, error) {
+		if value, ok := p.cached(key); ok {
+			return value, nil
+		}
+		owner := p.Owner(key)
+		if owner == p.self {
+			return p.load(ctx, key)
+		}
+
+		value, err := p.fetch(ctx, owner, key)
+		if errors.Is(err, ErrPeerLoad) || ctx.Err() != nil {
+			return nil, err
+		}
+		if err != nil {
+			// The owner is unreachable, so load the value here instead
+			return p.load(ctx, key)
+		}
+		p.cache.Set(key, value, p.ttl)
+		return value, nil
+	})
+}
+
+// ServeHTTP serves GET requests from peers for keys this node owns.
+// The value is loaded here even if this node does not consider itself the owner,
+// so peers that disagree about the ring cannot forward requests in a loop.
+func (p *Pool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	escaped, ok := strings.CutPrefix(r.URL.EscapedPath(), p.basePath)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	key, err := url.PathUnescape(escaped)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	value, ok := p.cached(key)
+	if !ok {
+		if value, err = p.load(r.Context(), key); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(value)
+}
+
+// cached returns a value from the local cache.
+func (p *Pool) cached(key string) ([]byte, bool) {
+	value, err := p.cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	b, ok := value.([]byte)
+	return b, ok
+}
+
+// load calls the Getter once for concurrent requests and caches the value.
+func (p *Pool) load(ctx context.Context, key string) ([]byte, error) {
+	return p.loads.do(key, func() ([]byte, error) {
+		if value, ok := p.cached(key); ok {
+			return value, nil
+		}
+		value, err := p.getter(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		p.cache.Set(key, value, p.ttl)
+		return value, nil
+	})
+}
+
+// fetch requests a key from a peer. Returns an error wrapping ErrPeerLoad
+// if the peer answered but failed to load the value.
+func (p *Pool) fetch(ctx context.Context, peer, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, peer+p.basePath+url.PathEscape(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: %s: %s", ErrPeerLoad, peer, strings.TrimSpace(string(body)))
+	}
+	return nil, fmt.Errorf("%s: %s", peer, resp.Status)
+}
+
+// flightCall is an in-flight or completed shared call.
+type flightCall struct {
+	wg    sync.WaitGroup
+	value []byte
+	err   error
+}
+
+// flightGroup runs one call per key at a time.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// do runs fn once per key at a time. Concurrent callers with the same key
+// wait for the running call and receive its result.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if cl, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		cl.wg.Wait()
+		return cl.value, cl.err
+	}
+	cl := &flightCall{err: errors.New("cluster: shared call panicked")}
+	cl.wg.Add(1)
+	g.calls[key] = cl
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		cl.wg.Done()
+	}()
+
+	cl.value, cl.err = fn()
+	return cl.value, cl.err
+}
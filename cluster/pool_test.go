@@ -0,0 +1,165 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingGetter counts Getter calls per key.
+type countingGetter struct {
+	mu    sync.Mutex
+	calls map[string]int
+	delay time.Duration
+}
+
+func (g *countingGetter) get(_ context.Context, key string) ([]byte, error) {
+	time.Sleep(g.delay)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls[key]++
+	if key == "broken" {
+		return nil, errors.New("backend failed")
+	}
+	return []byte("value:" + key), nil
+}
+
+func (g *countingGetter) count(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.calls[key]
+}
+
+func newTestPools(t *testing.T, n int, getter *countingGetter) ([]*Pool, []*httptest.Server) {
+	pools := make([]*Pool, n)
+	servers := make([]*httptest.Server, n)
+	urls := make([]string, n)
+	for i := range pools {
+		mux := http.NewServeMux()
+		servers[i] = httptest.NewServer(mux)
+		urls[i] = servers[i].URL
+
+		cache := cacher.New(cacher.Config{})
+		pools[i] = NewPool(cache, getter.get, PoolOptions{Self: urls[i], TTL: time.Minute})
+		mux.Handle(defaultBasePath, pools[i])
+
+		t.Cleanup(func() {
+			servers[i].Close()
+			cache.Close()
+		})
+	}
+	for _, pool := range pools {
+		pool.Set(urls...)
+	}
+	return pools, servers
+}
+
+func TestPool_LoadsOncePerFleet(t *testing.T) {
+	getter := &countingGetter{calls: make(map[string]int)}
+	pools, _ := newTestPools(t, 3, getter)
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key %d/x", i)
+		for _, pool := range pools {
+			value, err := pool.Get(t.Context(), key)
+			require.NoError(t, err)
+			assert.Equal(t, "value:"+key, string(value))
+		}
+		// Загружает только владелец ключа
+		assert.Equal(t, 1, getter.count(key))
+	}
+
+	// Все узлы согласны, кто владелец
+	owner := pools[0].Owner("key 0/x")
+	for _, pool := range pools {
+		assert.Equal(t, owner, pool.Owner("key 0/x"))
+	}
+}
+
+func TestPool_ConcurrentMisses(t *testing.T) {
+	getter := &countingGetter{calls: make(map[string]int), delay: 20 * time.Millisecond}
+	pools, _ := newTestPools(t, 3, getter)
+
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := pools[i%3].Get(t.Context(), "hot"); err != nil {
+				failed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Zero(t, failed.Load())
+	assert.Equal(t, 1, getter.count("hot"))
+}
+
+func TestPool_OwnerDown(t *testing.T) {
+	getter := &countingGetter{calls: make(map[string]int)}
+	pools, servers := newTestPools(t, 2, getter)
+
+	// Ищем ключ, которым владеет второй узел, и выключаем его
+	var key string
+	for i := 0; key == ""; i++ {
+		if k := fmt.Sprint("k", i); pools[0].Owner(k) == servers[1].URL {
+			key = k
+		}
+	}
+	servers[1].Close()
+
+	value, err := pools[0].Get(t.Context(), key)
+	require.NoError(t, err)
+	assert.Equal(t, "value:"+key, string(value))
+	assert.Equal(t, 1, getter.count(key))
+}
+
+func TestPool_GetterError(t *testing.T) {
+	getter := &countingGetter{calls: make(map[string]int)}
+	pools, _ := newTestPools(t, 2, getter)
+
+	for _, pool := range pools {
+		_, err := pool.Get(t.Context(), "broken")
+		assert.Error(t, err)
+	}
+	// Ошибка владельца не приводит к повторной загрузке на другом узле
+	assert.Equal(t, 2, getter.count("broken"))
+
+	owner, other := pools[0], pools[1]
+	if owner.Owner("broken") != owner.self {
+		owner, other = other, owner
+	}
+	_, err := other.Get(t.Context(), "broken")
+	assert.ErrorIs(t, err, ErrPeerLoad)
+	assert.Equal(t, 3, getter.count("broken"))
+	_, _ = owner.Get(t.Context(), "broken")
+	assert.Equal(t, 4, getter.count("broken")) // ошибки не кэшируются
+}
+
+func TestPool_NoPeers(t *testing.T) {
+	getter := &countingGetter{calls: make(map[string]int)}
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	pool := NewPool(cache, getter.get, PoolOptions{Self: "http://self"})
+
+	assert.Equal(t, "http://self", pool.Owner("k1"))
+	value, err := pool.Get(t.Context(), "k1")
+	require.NoError(t, err)
+	assert.Equal(t, "value:k1", string(value))
+
+	_, err = pool.Get(t.Context(), "k1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, getter.count("k1"))
+}
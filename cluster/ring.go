@@ -0,0 +1,61 @@
+package cluster
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// ring is a consistent hash ring with virtual nodes.
+type ring struct {
+	hashes []uint32          // Sorted virtual node hashes
+	owners map[uint32]string // Virtual node hash -> node name
+	nodes  int               // Number of distinct nodes
+}
+
+// newRing places each node on the ring replicas times.
+func newRing(nodes []string, replicas int) *ring {
+	r := &ring{
+		owners: make(map[uint32]string, len(nodes)*replicas),
+		nodes:  len(nodes),
+	}
+	for _, node := range nodes {
+		for i := 0; i < replicas; i++ {
+			h := hashKey(node + "#" + strconv.Itoa(i))
+			if _, ok := r.owners[h]; ok {
+				continue
+			}
+			r.owners[h] = node
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// lookup returns the distinct nodes responsible for key in ring order:
+// the owner first, followed by the nodes to fail over to.
+func (r *ring) lookup(key string) []string {
+	if len(r.hashes) == 0 {
+		return nil
+	}
+
+	h := hashKey(key)
+	start := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+
+	result := make([]string, 0, r.nodes)
+	seen := make(map[string]struct{}, r.nodes)
+	for i := 0; i < len(r.hashes) && len(result) < r.nodes; i++ {
+		node := r.owners[r.hashes[(start+i)%len(r.hashes)]]
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		seen[node] = struct{}{}
+		result = append(result, node)
+	}
+	return result
+}
+
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
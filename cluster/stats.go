@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/danRulev/cacher"
+)
+
+// MetricsNode is a Node that reports the metrics of its cache.
+// *cachergrpc.Client implements it.
+type MetricsNode interface {
+	Node
+	Metrics(ctx context.Context) (cacher.Metrics, error)
+}
+
+// nodeCounters counts the operations routed to a node.
+type nodeCounters struct {
+	requests  atomic.Uint64
+	errors    atomic.Uint64
+	failovers atomic.Uint64
+}
+
+// NodeStats describes the traffic a node received from a Client.
+type NodeStats struct {
+	Name      string
+	Requests  uint64 // Operations sent to the node
+	Errors    uint64 // Operations that failed on the node and were failed over
+	Failovers uint64 // Operations sent to the node because the key owner was down
+	Down      bool   // The node is skipped until its retry period elapses
+
+	// Items and LockWait are the item count of the node and the time its operations
+	// waited for the cache lock (empty unless the node tracks latency).
+	// Filled by ShardStats for nodes implementing MetricsNode.
+	Items    int
+	LockWait cacher.LatencyStats
+}
+
+// Stats returns per-node statistics sorted by node name.
+// Uneven request counts show keys hashing unevenly across the nodes.
+func (c *Client) Stats() []NodeStats {
+	stats := make([]NodeStats, 0, len(c.counters))
+	for name, counters := range c.counters {
+		stats = append(stats, NodeStats{
+			Name:      name,
+			Requests:  counters.requests.Load(),
+			Errors:    counters.errors.Load(),
+			Failovers: counters.failovers.Load(),
+			Down:      !c.available(name),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// ShardStats is like Stats, but also asks the nodes implementing MetricsNode
+// for their item counts and lock contention, concurrently. Nodes that are down
+// are not asked. Failed requests are returned as a joined error, together with
+// the stats of all nodes.
+func (c *Client) ShardStats(ctx context.Context) ([]NodeStats, error) {
+	stats := c.Stats()
+	errs := make([]error, len(stats))
+
+	var wg sync.WaitGroup
+	for i := range stats {
+		node, ok := c.nodes[stats[i].Name].(MetricsNode)
+		if !ok || stats[i].Down {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, err := node.Metrics(ctx)
+			if err != nil {
+				errs[i] = fmt.Errorf("cluster: node %s: %w", stats[i].Name, err)
+				return
+			}
+			stats[i].Items = m.Items
+			stats[i].LockWait = m.LockWait
+		}()
+	}
+	wg.Wait()
+	return stats, errors.Join(errs...)
+}
+
+// Hottest returns the name of the node that received the most requests,
+// or "" if no requests were made.
+func (c *Client) Hottest() string {
+	var hottest string
+	var most uint64
+	for _, node := range c.Stats() {
+		if node.Requests > most {
+			hottest, most = node.Name, node.Requests
+		}
+	}
+	return hottest
+}
@@ -0,0 +1,85 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Stats(t *testing.T) {
+	a, b := newFakeNode(), newFakeNode()
+	client := New(map[string]Node{"a": a, "b": b}, Options{RetryAfter: time.Hour})
+	ctx := context.Background()
+	assert.Equal(t, "", client.Hottest())
+
+	perNode := map[string]uint64{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		require.NoError(t, client.Set(ctx, key, []byte(key), 0))
+		perNode[client.NodeFor(key)]++
+	}
+
+	stats := client.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, NodeStats{Name: "a", Requests: perNode["a"]}, stats[0])
+	assert.Equal(t, NodeStats{Name: "b", Requests: perNode["b"]}, stats[1])
+
+	hottest := "a"
+	if perNode["b"] > perNode["a"] {
+		hottest = "b"
+	}
+	assert.Equal(t, hottest, client.Hottest())
+
+	// ключ владельца "a" уходит на "b" после ошибки
+	var key string
+	for i := 0; client.NodeFor(key) != "a"; i++ {
+		key = fmt.Sprintf("other-%d", i)
+	}
+	a.failed = true
+	require.NoError(t, client.Set(ctx, key, []byte("v"), 0))
+
+	stats = client.Stats()
+	assert.Equal(t, NodeStats{Name: "a", Requests: perNode["a"] + 1, Errors: 1, Down: true}, stats[0])
+	assert.Equal(t, NodeStats{Name: "b", Requests: perNode["b"] + 1, Failovers: 1}, stats[1])
+}
+
+// metricsNode is a fakeNode reporting its item count and lock wait.
+type metricsNode struct {
+	*fakeNode
+	err error
+}
+
+func (n metricsNode) Metrics(context.Context) (cacher.Metrics, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return cacher.Metrics{Items: len(n.values), LockWait: cacher.LatencyStats{Count: 1, Max: time.Millisecond}}, n.err
+}
+
+func TestClient_ShardStats(t *testing.T) {
+	a, b, c := metricsNode{fakeNode: newFakeNode()}, metricsNode{fakeNode: newFakeNode(), err: errors.New("timeout")}, newFakeNode()
+	client := New(map[string]Node{"a": a, "b": b, "c": c}, Options{})
+	ctx := context.Background()
+
+	perNode := map[string]int{}
+	for i := 0; i < 30; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		require.NoError(t, client.Set(ctx, key, []byte(key), 0))
+		perNode[client.NodeFor(key)]++
+	}
+
+	// Узел без Metrics пропускается, ошибка узла возвращается вместе со статистикой
+	stats, err := client.ShardStats(ctx)
+	assert.ErrorContains(t, err, "node b: timeout")
+	require.Len(t, stats, 3)
+	assert.Equal(t, perNode["a"], stats[0].Items)
+	assert.Equal(t, time.Millisecond, stats[0].LockWait.Max)
+	assert.Zero(t, stats[1].Items)
+	assert.Zero(t, stats[2].Items)
+	assert.Equal(t, uint64(perNode["c"]), stats[2].Requests)
+}
@@ -0,0 +1,174 @@
+// Command cacherctl inspects a running cache through its HTTP admin handler
+// (package cacherhttp) or its gRPC service (package cachergrpc).
+//
+// Usage:
+//
+//	cacherctl [-addr URL | -grpc HOST:PORT] [-timeout D] stats
+//	cacherctl [-addr URL | -grpc HOST:PORT] [-timeout D] get KEY
+//	cacherctl [-addr URL | -grpc HOST:PORT] [-timeout D] del KEY
+//	cacherctl [-addr URL] [-timeout D] dump > snap.json
+//
+// dump needs the HTTP admin handler, the gRPC service cannot list keys.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/danRulev/cacher/cachergrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// admin is the surface of a running cache used by the commands.
+type admin interface {
+	Stats(ctx context.Context, w io.Writer) error
+	Get(ctx context.Context, key string, w io.Writer) error
+	Delete(ctx context.Context, key string) error
+	Dump(ctx context.Context, w io.Writer) error
+	Close() error
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "cacherctl:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments and executes a command, writing its output to w.
+func run(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("cacherctl", flag.ContinueOnError)
+	addr := flags.String("addr", "http://localhost:8080", "base URL of the HTTP admin handler")
+	grpcAddr := flags.String("grpc", "", "address of the gRPC service, used instead of -addr")
+	timeout := flags.Duration("timeout", 5*time.Second, "request timeout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: cacherctl [flags] stats | get KEY | del KEY | dump")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var target admin
+	if *grpcAddr != "" {
+		client, err := cachergrpc.Dial(*grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return err
+		}
+		target = grpcAdmin{client}
+	} else {
+		target = httpAdmin{base: strings.TrimSuffix(*addr, "/"), client: http.DefaultClient}
+	}
+	defer target.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	cmd := flags.Args()
+	if len(cmd) == 0 {
+		flags.Usage()
+		return errors.New("missing command")
+	}
+	switch {
+	case cmd[0] == "stats" && len(cmd) == 1:
+		return target.Stats(ctx, w)
+	case cmd[0] == "get" && len(cmd) == 2:
+		return target.Get(ctx, cmd[1], w)
+	case cmd[0] == "del" && len(cmd) == 2:
+		return target.Delete(ctx, cmd[1])
+	case cmd[0] == "dump" && len(cmd) == 1:
+		return target.Dump(ctx, w)
+	}
+	flags.Usage()
+	return fmt.Errorf("invalid command: %s", strings.Join(cmd, " "))
+}
+
+// httpAdmin talks to a cacherhttp handler.
+type httpAdmin struct {
+	base   string
+	client *http.Client
+}
+
+func (a httpAdmin) Stats(ctx context.Context, w io.Writer) error {
+	return a.do(ctx, http.MethodGet, "/stats", w)
+}
+
+func (a httpAdmin) Get(ctx context.Context, key string, w io.Writer) error {
+	return a.do(ctx, http.MethodGet, "/keys/"+url.PathEscape(key), w)
+}
+
+func (a httpAdmin) Delete(ctx context.Context, key string) error {
+	return a.do(ctx, http.MethodDelete, "/keys/"+url.PathEscape(key), io.Discard)
+}
+
+func (a httpAdmin) Dump(ctx context.Context, w io.Writer) error {
+	return a.do(ctx, http.MethodGet, "/dump", w)
+}
+
+func (a httpAdmin) Close() error {
+	return nil
+}
+
+// do sends a request and copies a successful response body to w.
+func (a httpAdmin) do(ctx context.Context, method, path string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, method, a.base+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// grpcAdmin talks to a cachergrpc service.
+type grpcAdmin struct {
+	client *cachergrpc.Client
+}
+
+func (a grpcAdmin) Stats(ctx context.Context, w io.Writer) error {
+	stats, err := a.client.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, stats)
+	return err
+}
+
+func (a grpcAdmin) Get(ctx context.Context, key string, w io.Writer) error {
+	value, err := a.client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", value)
+	return err
+}
+
+func (a grpcAdmin) Delete(ctx context.Context, key string) error {
+	return a.client.Delete(ctx, key)
+}
+
+func (a grpcAdmin) Dump(context.Context, io.Writer) error {
+	return errors.New("dump is not supported over gRPC, use -addr")
+}
+
+func (a grpcAdmin) Close() error {
+	return a.client.Close()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/cachergrpc"
+	"github.com/danRulev/cacher/cacherhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestRun_HTTP(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	srv := httptest.NewServer(cacherhttp.Handler(cache))
+	defer srv.Close()
+
+	cache.Set("k1", "v1", time.Minute)
+	cache.Set("k 2", "v2", 0)
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"-addr", srv.URL, "get", "k 2"}, &out))
+	assert.Contains(t, out.String(), `"value": "v2"`)
+
+	out.Reset()
+	require.NoError(t, run([]string{"-addr", srv.URL, "stats"}, &out))
+	assert.Contains(t, out.String(), `"Items": 2`)
+
+	out.Reset()
+	require.NoError(t, run([]string{"-addr", srv.URL, "dump"}, &out))
+	var entries []cacherhttp.Entry
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+	assert.Len(t, entries, 2)
+
+	require.NoError(t, run([]string{"-addr", srv.URL, "del", "k1"}, &out))
+	// Повторное удаление возвращает 404
+	assert.Error(t, run([]string{"-addr", srv.URL, "del", "k1"}, &out))
+
+	assert.Error(t, run([]string{"-addr", srv.URL}, &out))
+	assert.Error(t, run([]string{"-addr", srv.URL, "get"}, &out))
+}
+
+func TestRun_GRPC(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	cachergrpc.Register(srv, cache)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	cache.Set("k1", "v1", 0)
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"-grpc", lis.Addr().String(), "get", "k1"}, &out))
+	assert.Equal(t, "v1\n", out.String())
+
+	require.NoError(t, run([]string{"-grpc", lis.Addr().String(), "del", "k1"}, &out))
+	_, err = cache.Get("k1")
+	assert.Error(t, err)
+
+	// Через gRPC нельзя получить список ключей
+	assert.Error(t, run([]string{"-grpc", lis.Addr().String(), "dump"}, &out))
+}
@@ -0,0 +1,78 @@
+package cacher
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// Codec encodes values to bytes and back.
+// Implementations must be safe for concurrent use.
+type Codec interface {
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte) (interface{}, error)
+}
+
+// GobCodec is a Codec using encoding/gob.
+// Values of custom types must be registered with gob.Register.
+type GobCodec struct{}
+
+// Marshal encodes a value with its type information.
+func (GobCodec) Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes a value encoded by Marshal.
+func (GobCodec) Unmarshal(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Codec returns the codec values are stored with, nil if they are stored as is.
+// Servers and replication use it to encode values for other processes.
+func (c *Cacher) Codec() Codec {
+	return c.codec
+}
+
+// encode marshals a value with the configured codec.
+// Returns the value unchanged if there is no codec or encoding fails.
+func (c *Cacher) encode(value interface{}) (interface{}, bool) {
+	if c.codec == nil {
+		return value, false
+	}
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return value, false
+	}
+	return data, true
+}
+
+// Cloner returns a deep copy of a value.
+type Cloner func(value interface{}) interface{}
+
+// load returns the value of an item, decompressing and decoding it if needed.
+// Values that are not encoded are copied with the item or cache Cloner, if any.
+func (c *Cacher) load(item cache) interface{} {
+	value := item.unpack()
+	if !item.encoded {
+		if item.cloner != nil {
+			return item.cloner(value)
+		}
+		if c.cloner != nil {
+			return c.cloner(value)
+		}
+		return value
+	}
+
+	decoded, err := c.codec.Unmarshal(value.([]byte))
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
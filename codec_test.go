@@ -0,0 +1,96 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Codec(t *testing.T) {
+	cfg := Config{Capacity: 10, Codec: GobCodec{}}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("k1", []int{1, 2, 3}, 5*time.Second)
+
+	cache.mu.RLock()
+	assert.True(t, cache.cache["k1"].encoded)
+	assert.IsType(t, []byte{}, cache.cache["k1"].value)
+	cache.mu.RUnlock()
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	got.([]int)[0] = 100 // изменение копии не влияет на кэш
+
+	got, err = cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, got)
+}
+
+func TestCacher_CodecUnencodable(t *testing.T) {
+	cfg := Config{Capacity: 10, Codec: GobCodec{}}
+	cache := New(cfg)
+	defer cache.Close()
+
+	ch := make(chan int)
+	cache.Set("k1", ch, 5*time.Second)
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, ch, got)
+}
+
+func TestGobCodec(t *testing.T) {
+	var codec GobCodec
+
+	data, err := codec.Marshal("value")
+	require.NoError(t, err)
+
+	got, err := codec.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "value", got)
+}
+
+func cloneInts(value interface{}) interface{} {
+	return append([]int(nil), value.([]int)...)
+}
+
+func TestCacher_Cloner(t *testing.T) {
+	cfg := Config{Capacity: 10, Cloner: cloneInts}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("k1", []int{1, 2, 3}, 5*time.Second)
+
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	got.([]int)[0] = 100
+
+	got, err = cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, got)
+}
+
+func TestCacher_SetWithCloner(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	cache.SetWithCloner("cloned", []int{1, 2, 3}, 5*time.Second, cloneInts)
+	cache.Set("shared", []int{1, 2, 3}, 5*time.Second)
+
+	got, err := cache.Get("cloned")
+	require.NoError(t, err)
+	got.([]int)[0] = 100
+	got, err = cache.Get("cloned")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, got)
+
+	got, err = cache.Get("shared")
+	require.NoError(t, err)
+	got.([]int)[0] = 100
+	got, err = cache.Get("shared")
+	require.NoError(t, err)
+	assert.Equal(t, []int{100, 2, 3}, got) // без клонирования значение общее
+}
@@ -0,0 +1,70 @@
+package cacher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// Kinds of compressed values, used to restore the original type.
+const (
+	uncompressed = iota
+	compressedBytes
+	compressedString
+)
+
+// compress gzips []byte and string values of at least threshold bytes.
+// Returns the value unchanged if it is not eligible or does not shrink.
+func compress(value interface{}, threshold int) (interface{}, int) {
+	if threshold <= 0 {
+		return value, uncompressed
+	}
+
+	var data []byte
+	kind := uncompressed
+	switch v := value.(type) {
+	case []byte:
+		data, kind = v, compressedBytes
+	case string:
+		data, kind = []byte(v), compressedString
+	default:
+		return value, uncompressed
+	}
+	if len(data) < threshold {
+		return value, uncompressed
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return value, uncompressed
+	}
+	if err := w.Close(); err != nil {
+		return value, uncompressed
+	}
+	if buf.Len() >= len(data) {
+		return value, uncompressed
+	}
+	return buf.Bytes(), kind
+}
+
+// unpack returns the stored value, decompressing it if needed.
+func (item cache) unpack() interface{} {
+	if item.compressed == uncompressed {
+		return item.value
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(item.value.([]byte)))
+	if err != nil {
+		return nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil
+	}
+
+	if item.compressed == compressedString {
+		return string(data)
+	}
+	return data
+}
@@ -0,0 +1,44 @@
+package cacher
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Compression(t *testing.T) {
+	cfg := Config{Capacity: 10, CompressionThreshold: 64}
+	cache := New(cfg)
+	defer cache.Close()
+
+	large := strings.Repeat(`{"id":1,"name":"value"}`, 100)
+	cache.Set("string", large, 5*time.Second)
+	cache.Set("bytes", []byte(large), 5*time.Second)
+	cache.Set("small", "tiny", 5*time.Second)
+	cache.Set("int", 42, 5*time.Second)
+
+	cache.mu.RLock()
+	assert.Equal(t, compressedString, cache.cache["string"].compressed)
+	assert.Less(t, len(cache.cache["string"].value.([]byte)), len(large))
+	assert.Equal(t, compressedBytes, cache.cache["bytes"].compressed)
+	assert.Equal(t, uncompressed, cache.cache["small"].compressed)
+	assert.Equal(t, uncompressed, cache.cache["int"].compressed)
+	cache.mu.RUnlock()
+
+	got, err := cache.Get("string")
+	require.NoError(t, err)
+	assert.Equal(t, large, got)
+
+	got, err = cache.Get("bytes")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(large), got)
+
+	got, err = cache.Get("small")
+	require.NoError(t, err)
+	assert.Equal(t, "tiny", got)
+
+	assert.Contains(t, cache.GetAll(), large)
+}
@@ -0,0 +1,89 @@
+package cacher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotModified is returned by GetConditional when the value still has the given ETag.
+var ErrNotModified = errors.New("not modified")
+
+// Validators identify the content of a value for conditional requests.
+type Validators struct {
+	ETag     string    // Quoted hash of the value, usable as an HTTP ETag
+	Modified time.Time // When the value was last set or updated
+}
+
+// GetConditional is like Get, but also returns the validators of the value.
+// If etag matches the ETag of the value, it returns no value and ErrNotModified,
+// so unchanged content need not be sent again. etag may be an If-None-Match
+// header value: a list of ETags, weak ones included, or "*".
+//
+// The ETag is a hash of the stored value, computed on the first conditional read
+// after a write. Values other than strings, []byte and values encoded with a codec
+// are hashed by their %#v formatting, so changes behind pointers are not detected.
+func (c *Cacher) GetConditional(key interface{}, etag string) (interface{}, Validators, error) {
+	key = c.key(key)
+	c.unspill(key)
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	value, err := c.get(key)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+	item, ok := c.cache[key]
+	if !ok {
+		return nil, Validators{}, fmt.Errorf("cache not found for key: %v", key)
+	}
+	if item.etag == "" {
+		item.etag = hashValue(item.value)
+		c.cache[key] = item
+	}
+
+	validators := Validators{ETag: item.etag, Modified: item.modified()}
+	if matchETag(etag, item.etag) {
+		return nil, validators, ErrNotModified
+	}
+	return value, validators, nil
+}
+
+// modified returns when the value of an item was last written.
+func (item cache) modified() time.Time {
+	if item.modifiedAt.IsZero() {
+		return item.createdAt
+	}
+	return item.modifiedAt
+}
+
+// hashValue returns a quoted hash of a stored value.
+func hashValue(value interface{}) string {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		data = fmt.Appendf(nil, "%T %#v", value, value)
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchETag reports whether an If-None-Match list matches an ETag.
+// Weak comparison is used, as for GET requests.
+func matchETag(list, etag string) bool {
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_GetConditional(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("page", "<h1>hello</h1>", time.Minute)
+
+	value, v, err := cache.GetConditional("page", "")
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>hello</h1>", value)
+	assert.Len(t, v.ETag, 34)
+	assert.False(t, v.Modified.IsZero())
+
+	value, same, err := cache.GetConditional("page", v.ETag)
+	assert.ErrorIs(t, err, ErrNotModified)
+	assert.Nil(t, value)
+	assert.Equal(t, v, same)
+
+	// Список из If-None-Match, включая слабые ETag
+	_, _, err = cache.GetConditional("page", `"other", W/`+v.ETag)
+	assert.ErrorIs(t, err, ErrNotModified)
+	_, _, err = cache.GetConditional("page", "*")
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	// Изменение значения меняет ETag и время изменения
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, cache.Update("page", "<h1>bye</h1>"))
+	value, changed, err := cache.GetConditional("page", v.ETag)
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>bye</h1>", value)
+	assert.NotEqual(t, v.ETag, changed.ETag)
+	assert.True(t, changed.Modified.After(v.Modified))
+
+	entry, err := cache.Entry("page")
+	require.NoError(t, err)
+	assert.Equal(t, changed.Modified, entry.Modified)
+
+	// Одинаковое содержимое даёт одинаковый ETag
+	cache.Set("copy", "<h1>bye</h1>", time.Minute)
+	_, _, err = cache.GetConditional("copy", changed.ETag)
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	_, _, err = cache.GetConditional("missing", "")
+	assert.Error(t, err)
+}
+
+func TestCacher_GetConditionalCodec(t *testing.T) {
+	cache := New(Config{Codec: GobCodec{}})
+	defer cache.Close()
+
+	cache.Set("k", map[string]int{"a": 1}, 0)
+	_, v, err := cache.GetConditional("k", "")
+	require.NoError(t, err)
+
+	cache.Set("k", map[string]int{"a": 2}, 0)
+	value, changed, err := cache.GetConditional("k", v.ETag)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 2}, value)
+	assert.NotEqual(t, v.ETag, changed.ETag)
+}
+
+func TestHashValue(t *testing.T) {
+	assert.Equal(t, hashValue("abc"), hashValue([]byte("abc")))
+	assert.Equal(t, hashValue(map[string]int{"a": 1, "b": 2}), hashValue(map[string]int{"b": 2, "a": 1}))
+	assert.NotEqual(t, hashValue(1), hashValue(int64(1)))
+}
@@ -0,0 +1,329 @@
+package cacher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validate reports configuration values that New would silently ignore or clamp,
+// such as a negative capacity or an unknown eviction policy.
+// Zero values are valid and mean the defaults. All problems are returned joined.
+func (cfg Config) Validate() error {
+	var errs []error
+	check := func(bad bool, format string, args ...interface{}) {
+		if bad {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(cfg.Capacity < 0, "capacity cannot be negative: %d", cfg.Capacity)
+	check(cfg.MinClearingInterval < 0, "min clearing interval cannot be negative: %v", cfg.MinClearingInterval)
+	check(cfg.MaxClearingInterval < 0, "max clearing interval cannot be negative: %v", cfg.MaxClearingInterval)
+	check(cfg.MinClearingInterval > 0 && cfg.MaxClearingInterval > 0 && cfg.MinClearingInterval > cfg.MaxClearingInterval,
+		"min clearing interval %v is greater than max %v", cfg.MinClearingInterval, cfg.MaxClearingInterval)
+	check(cfg.EvictionPolicy < 0 || cfg.EvictionPolicy >= len(policyNames),
+		"invalid eviction policy: %d (must be 0-%d)", cfg.EvictionPolicy, len(policyNames)-1)
+	check(cfg.AutoTuneInterval > 0 && cfg.Capacity == 0, "auto-tuning requires a starting capacity")
+	check(cfg.MinCapacity < 0, "min capacity cannot be negative: %d", cfg.MinCapacity)
+	check(cfg.MaxCapacity < 0, "max capacity cannot be negative: %d", cfg.MaxCapacity)
+	check(cfg.MaxCapacity > 0 && cfg.MinCapacity > cfg.MaxCapacity,
+		"min capacity %d is greater than max %d", cfg.MinCapacity, cfg.MaxCapacity)
+	check(cfg.TargetHitRatio < 0 || cfg.TargetHitRatio > 1, "target hit ratio must be between 0 and 1: %v", cfg.TargetHitRatio)
+	check(cfg.ProtectedRatio < 0 || cfg.ProtectedRatio > 1, "protected ratio must be between 0 and 1: %v", cfg.ProtectedRatio)
+	check(cfg.EvictExpiringWithin < 0, "evict expiring window cannot be negative: %v", cfg.EvictExpiringWithin)
+	check(cfg.LFUSampleSize < 0, "LFU sample size cannot be negative: %d", cfg.LFUSampleSize)
+	check(cfg.LRUKHistory < 0, "LRU-K history cannot be negative: %d", cfg.LRUKHistory)
+	check(cfg.TTLJitter < 0 || cfg.TTLJitter > 1, "TTL jitter must be between 0 and 1: %v", cfg.TTLJitter)
+	check(cfg.CompressionThreshold < 0, "compression threshold cannot be negative: %d", cfg.CompressionThreshold)
+	check(cfg.MaxValueBytes < 0, "max value bytes cannot be negative: %d", cfg.MaxValueBytes)
+	check(cfg.MaxKeyLength < 0, "max key length cannot be negative: %d", cfg.MaxKeyLength)
+	check(cfg.HistoryDepth < 0, "history depth cannot be negative: %d", cfg.HistoryDepth)
+	check(cfg.EvictBatchSize < 0, "evict batch size cannot be negative: %d", cfg.EvictBatchSize)
+	check(cfg.EvictBatchPercent < 0 || cfg.EvictBatchPercent > 100,
+		"evict batch percent must be between 0 and 100: %v", cfg.EvictBatchPercent)
+	check(cfg.HighWatermark < 0 || cfg.HighWatermark > 1, "high watermark must be between 0 and 1: %v", cfg.HighWatermark)
+	check(cfg.LowWatermark < 0 || cfg.LowWatermark > 1, "low watermark must be between 0 and 1: %v", cfg.LowWatermark)
+	check(cfg.LowWatermark > 0 && cfg.LowWatermark > cfg.HighWatermark,
+		"low watermark %v is greater than high watermark %v", cfg.LowWatermark, cfg.HighWatermark)
+	check(cfg.TombstoneTTL < 0, "tombstone TTL cannot be negative: %v", cfg.TombstoneTTL)
+	check(cfg.GracePeriod < 0, "grace period cannot be negative: %v", cfg.GracePeriod)
+	check(cfg.RefreshBeta < 0, "refresh beta cannot be negative: %v", cfg.RefreshBeta)
+	check(cfg.DoorkeeperKeys < 0, "doorkeeper keys cannot be negative: %d", cfg.DoorkeeperKeys)
+	check(cfg.DoorkeeperFalsePositiveRate < 0 || cfg.DoorkeeperFalsePositiveRate >= 1,
+		"doorkeeper false positive rate must be between 0 and 1: %v", cfg.DoorkeeperFalsePositiveRate)
+	check(cfg.GhostKeys < 0, "ghost keys cannot be negative: %d", cfg.GhostKeys)
+	check(cfg.TrackKeys < 0, "track keys cannot be negative: %d", cfg.TrackKeys)
+	check(cfg.FrequencySketchKeys < 0, "frequency sketch keys cannot be negative: %d", cfg.FrequencySketchKeys)
+	check(cfg.CoarseClock < 0, "coarse clock resolution cannot be negative: %v", cfg.CoarseClock)
+	check(cfg.WriteBehindInterval < 0, "write-behind interval cannot be negative: %v", cfg.WriteBehindInterval)
+	check(cfg.WriteRetries < 0, "write retries cannot be negative: %d", cfg.WriteRetries)
+	check(cfg.SnapshotInterval < 0, "snapshot interval cannot be negative: %v", cfg.SnapshotInterval)
+	check(cfg.SnapshotInterval > 0 && cfg.SnapshotPath == "", "snapshot interval requires a snapshot path")
+	check(cfg.SnapshotKeep < 0, "snapshot keep cannot be negative: %d", cfg.SnapshotKeep)
+	check(cfg.WALSync < WALSyncPeriodic || cfg.WALSync > WALSyncNever, "invalid wal sync policy: %d", cfg.WALSync)
+	check(cfg.WALSyncInterval < 0, "wal sync interval cannot be negative: %v", cfg.WALSyncInterval)
+	check(cfg.CallbackWorkers < 0, "callback workers cannot be negative: %d", cfg.CallbackWorkers)
+	check(cfg.CallbackQueueSize < 0, "callback queue size cannot be negative: %d", cfg.CallbackQueueSize)
+
+	return errors.Join(errs...)
+}
+
+// MustNew is like New, but panics if the configuration changed by opts is invalid.
+// Use it to fail fast on misconfiguration at startup.
+func MustNew(cfg Config, opts ...Option) *Cacher {
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		panic(fmt.Sprintf("cacher: invalid config: %v", err))
+	}
+	return New(cfg)
+}
+
+// ApplyConfig updates a running cache with the capacity, eviction policy, default TTL
+// and clearing settings (ClearingInterval, AdaptiveClearing, Min/MaxClearingInterval)
+// of cfg at once, restarting the cleaner if its interval changed.
+// Other fields of cfg are ignored. Returns the Validate error without changing anything
+// if cfg is invalid.
+func (c *Cacher) ApplyConfig(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.ClearingInterval == 0 {
+		cfg.ClearingInterval = defaultClearingInterval
+	}
+	if cfg.MinClearingInterval == 0 {
+		cfg.MinClearingInterval = cfg.ClearingInterval / 10
+	}
+	if cfg.MaxClearingInterval == 0 {
+		cfg.MaxClearingInterval = cfg.ClearingInterval * 10
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logger.Info("cache config applied",
+		"capacity", cfg.Capacity, "policy", policyName(cfg.EvictionPolicy),
+		"default_ttl", cfg.DefaultTTL, "clearing_interval", cfg.ClearingInterval)
+
+	c.capacity = cfg.Capacity
+	c.evictionPolicy = cfg.EvictionPolicy
+	c.defaultTTL.Store(int64(cfg.DefaultTTL))
+	c.adaptiveClearing = cfg.AdaptiveClearing
+	c.minClearing = cfg.MinClearingInterval
+	c.maxClearing = cfg.MaxClearingInterval
+	if cfg.ClearingInterval != c.clearingInterval || c.adaptiveClearing {
+		c.clearingInterval = cfg.ClearingInterval
+		c.restartClearing()
+	}
+	c.checkWatermarks()
+	return nil
+}
+
+// ParsePolicy returns the eviction policy with the given name, ignoring case,
+// e.g. "lru", "slru" or "lru-k".
+func ParsePolicy(name string) (int, error) {
+	normalized := strings.ReplaceAll(strings.ToUpper(name), "-", "")
+	for policy, policyName := range policyNames {
+		if strings.ReplaceAll(policyName, "-", "") == normalized {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown eviction policy: %q", name)
+}
+
+// setting parses a value and stores it in a Config field.
+type setting func(cfg *Config, value string) error
+
+// settings are the Config fields that can be loaded from the environment or a file,
+// by their names in files. Durations use time.ParseDuration syntax, e.g. "30s".
+var settings = map[string]setting{
+	"capacity":                       intSetting(func(cfg *Config) *int { return &cfg.Capacity }),
+	"auto_tune_interval":             durationSetting(func(cfg *Config) *time.Duration { return &cfg.AutoTuneInterval }),
+	"min_capacity":                   intSetting(func(cfg *Config) *int { return &cfg.MinCapacity }),
+	"max_capacity":                   intSetting(func(cfg *Config) *int { return &cfg.MaxCapacity }),
+	"target_hit_ratio":               floatSetting(func(cfg *Config) *float64 { return &cfg.TargetHitRatio }),
+	"max_heap_bytes":                 uintSetting(func(cfg *Config) *uint64 { return &cfg.MaxHeapBytes }),
+	"default_ttl":                    durationSetting(func(cfg *Config) *time.Duration { return &cfg.DefaultTTL }),
+	"clearing_interval":              durationSetting(func(cfg *Config) *time.Duration { return &cfg.ClearingInterval }),
+	"expiry_timer":                   boolSetting(func(cfg *Config) *bool { return &cfg.ExpiryTimer }),
+	"adaptive_clearing":              boolSetting(func(cfg *Config) *bool { return &cfg.AdaptiveClearing }),
+	"min_clearing_interval":          durationSetting(func(cfg *Config) *time.Duration { return &cfg.MinClearingInterval }),
+	"max_clearing_interval":          durationSetting(func(cfg *Config) *time.Duration { return &cfg.MaxClearingInterval }),
+	"eviction_policy":                policySetting,
+	"protected_ratio":                floatSetting(func(cfg *Config) *float64 { return &cfg.ProtectedRatio }),
+	"evict_expiring_within":          durationSetting(func(cfg *Config) *time.Duration { return &cfg.EvictExpiringWithin }),
+	"lfu_sample_size":                intSetting(func(cfg *Config) *int { return &cfg.LFUSampleSize }),
+	"lru_k_history":                  intSetting(func(cfg *Config) *int { return &cfg.LRUKHistory }),
+	"ttl_jitter":                     floatSetting(func(cfg *Config) *float64 { return &cfg.TTLJitter }),
+	"compression_threshold":          intSetting(func(cfg *Config) *int { return &cfg.CompressionThreshold }),
+	"max_value_bytes":                intSetting(func(cfg *Config) *int { return &cfg.MaxValueBytes }),
+	"max_key_length":                 intSetting(func(cfg *Config) *int { return &cfg.MaxKeyLength }),
+	"history_depth":                  intSetting(func(cfg *Config) *int { return &cfg.HistoryDepth }),
+	"evict_batch_size":               intSetting(func(cfg *Config) *int { return &cfg.EvictBatchSize }),
+	"evict_batch_percent":            floatSetting(func(cfg *Config) *float64 { return &cfg.EvictBatchPercent }),
+	"high_watermark":                 floatSetting(func(cfg *Config) *float64 { return &cfg.HighWatermark }),
+	"low_watermark":                  floatSetting(func(cfg *Config) *float64 { return &cfg.LowWatermark }),
+	"evict_to_low_watermark":         boolSetting(func(cfg *Config) *bool { return &cfg.EvictToLowWatermark }),
+	"grace_period":                   durationSetting(func(cfg *Config) *time.Duration { return &cfg.GracePeriod }),
+	"refresh_beta":                   floatSetting(func(cfg *Config) *float64 { return &cfg.RefreshBeta }),
+	"doorkeeper_keys":                intSetting(func(cfg *Config) *int { return &cfg.DoorkeeperKeys }),
+	"doorkeeper_false_positive_rate": floatSetting(func(cfg *Config) *float64 { return &cfg.DoorkeeperFalsePositiveRate }),
+	"ghost_keys":                     intSetting(func(cfg *Config) *int { return &cfg.GhostKeys }),
+	"frequency_sketch_keys":          intSetting(func(cfg *Config) *int { return &cfg.FrequencySketchKeys }),
+	"purge_on_read":                  boolSetting(func(cfg *Config) *bool { return &cfg.PurgeOnRead }),
+	"track_keys":                     intSetting(func(cfg *Config) *int { return &cfg.TrackKeys }),
+	"track_latency":                  boolSetting(func(cfg *Config) *bool { return &cfg.TrackLatency }),
+	"coarse_clock":                   durationSetting(func(cfg *Config) *time.Duration { return &cfg.CoarseClock }),
+	"callback_workers":               intSetting(func(cfg *Config) *int { return &cfg.CallbackWorkers }),
+	"callback_queue_size":            intSetting(func(cfg *Config) *int { return &cfg.CallbackQueueSize }),
+	"flush_on_shutdown":              boolSetting(func(cfg *Config) *bool { return &cfg.FlushOnShutdown }),
+	"snapshot_path":                  stringSetting(func(cfg *Config) *string { return &cfg.SnapshotPath }),
+	"snapshot_interval":              durationSetting(func(cfg *Config) *time.Duration { return &cfg.SnapshotInterval }),
+	"wal_path":                       stringSetting(func(cfg *Config) *string { return &cfg.WALPath }),
+	"wal_sync":                       intSetting(func(cfg *Config) *int { return &cfg.WALSync }),
+	"wal_sync_interval":              durationSetting(func(cfg *Config) *time.Duration { return &cfg.WALSyncInterval }),
+	"snapshot_keep":                  intSetting(func(cfg *Config) *int { return &cfg.SnapshotKeep }),
+	"tombstone_ttl":                  durationSetting(func(cfg *Config) *time.Duration { return &cfg.TombstoneTTL }),
+	"write_behind":                   boolSetting(func(cfg *Config) *bool { return &cfg.WriteBehind }),
+	"write_behind_interval":          durationSetting(func(cfg *Config) *time.Duration { return &cfg.WriteBehindInterval }),
+	"write_retries":                  intSetting(func(cfg *Config) *int { return &cfg.WriteRetries }),
+	"pinned_never_expire":            boolSetting(func(cfg *Config) *bool { return &cfg.PinnedNeverExpire }),
+}
+
+// ConfigFromEnv loads a Config from environment variables named prefix, an underscore
+// and a setting name in upper case, e.g. CACHE_CAPACITY or CACHE_EVICTION_POLICY=lru
+// for the prefix "CACHE". Unset variables keep their zero values.
+// Callbacks, codecs and other non-scalar fields must be set in code.
+// Returns an error if a value cannot be parsed or the result fails Validate.
+func ConfigFromEnv(prefix string) (Config, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	var cfg Config
+	for _, name := range settingNames() {
+		value, ok := os.LookupEnv(prefix + strings.ToUpper(name))
+		if !ok {
+			continue
+		}
+		if err := settings[name](&cfg, value); err != nil {
+			return Config{}, fmt.Errorf("%s%s: %w", prefix, strings.ToUpper(name), err)
+		}
+	}
+	return cfg, cfg.Validate()
+}
+
+// ConfigFromFile loads a Config from a JSON (.json) or YAML (.yaml, .yml) file
+// with an object of settings in snake case, e.g. {"capacity": 1000, "eviction_policy": "lru"}.
+// Returns an error for unknown settings, values that cannot be parsed
+// or a result that fails Validate.
+func ConfigFromFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	values := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	default:
+		return Config{}, fmt.Errorf("unsupported config format: %q", ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("decode %s: %w", path, err)
+	}
+
+	var cfg Config
+	for name, value := range values {
+		set, ok := settings[name]
+		if !ok {
+			return Config{}, fmt.Errorf("unknown setting: %s", name)
+		}
+		if err := set(&cfg, fmt.Sprint(value)); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return cfg, cfg.Validate()
+}
+
+// settingNames returns the setting names in a stable order.
+func settingNames() []string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func policySetting(cfg *Config, value string) error {
+	policy, err := ParsePolicy(value)
+	if err != nil {
+		return err
+	}
+	cfg.EvictionPolicy = policy
+	return nil
+}
+
+func intSetting(field func(*Config) *int) setting {
+	return func(cfg *Config, value string) error {
+		v, err := strconv.Atoi(value)
+		*field(cfg) = v
+		return err
+	}
+}
+
+func uintSetting(field func(*Config) *uint64) setting {
+	return func(cfg *Config, value string) error {
+		v, err := strconv.ParseUint(value, 10, 64)
+		*field(cfg) = v
+		return err
+	}
+}
+
+func floatSetting(field func(*Config) *float64) setting {
+	return func(cfg *Config, value string) error {
+		v, err := strconv.ParseFloat(value, 64)
+		*field(cfg) = v
+		return err
+	}
+}
+
+func boolSetting(field func(*Config) *bool) setting {
+	return func(cfg *Config, value string) error {
+		v, err := strconv.ParseBool(value)
+		*field(cfg) = v
+		return err
+	}
+}
+
+func durationSetting(field func(*Config) *time.Duration) setting {
+	return func(cfg *Config, value string) error {
+		v, err := time.ParseDuration(value)
+		*field(cfg) = v
+		return err
+	}
+}
+
+func stringSetting(field func(*Config) *string) setting {
+	return func(cfg *Config, value string) error {
+		*field(cfg) = value
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+package cacher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{
+		Capacity:         100,
+		ClearingInterval: -1, // без фоновой очистки
+		EvictionPolicy:   LRUK,
+		HighWatermark:    0.9,
+		LowWatermark:     0.7,
+	}.Validate())
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"negative capacity", Config{Capacity: -1}, "capacity cannot be negative"},
+		{"unknown policy", Config{EvictionPolicy: 42}, "invalid eviction policy"},
+		{"negative interval", Config{MinClearingInterval: -time.Second}, "min clearing interval cannot be negative"},
+		{"min above max", Config{MinClearingInterval: time.Minute, MaxClearingInterval: time.Second}, "greater than max"},
+		{"jitter", Config{TTLJitter: 2}, "TTL jitter"},
+		{"watermarks", Config{HighWatermark: 0.5, LowWatermark: 0.8}, "greater than high watermark"},
+		{"fp rate", Config{DoorkeeperFalsePositiveRate: 1}, "doorkeeper false positive rate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.want)
+			}
+		})
+	}
+
+	// все ошибки возвращаются вместе
+	err := Config{Capacity: -1, EvictionPolicy: -1}.Validate()
+	assert.ErrorContains(t, err, "capacity")
+	assert.ErrorContains(t, err, "eviction policy")
+}
+
+func TestMustNew(t *testing.T) {
+	cache := MustNew(Config{Capacity: 10}, WithDoorkeeper(100, 0.01))
+	defer cache.Close()
+	assert.Equal(t, 10, cache.GetCapacity())
+
+	assert.Panics(t, func() { MustNew(Config{Capacity: -1}) })
+	assert.Panics(t, func() { MustNew(Config{}, WithDoorkeeper(-5, 0.01)) })
+}
+
+func TestParsePolicy(t *testing.T) {
+	for name, want := range map[string]int{"lru": LRU, "MRU": MRU, "Slru": SLRU, "lru-k": LRUK, "lruk": LRUK} {
+		policy, err := ParsePolicy(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, policy, name)
+	}
+
+	_, err := ParsePolicy("fifo")
+	assert.Error(t, err)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("CACHE_CAPACITY", "1000")
+	t.Setenv("CACHE_EVICTION_POLICY", "slru")
+	t.Setenv("CACHE_CLEARING_INTERVAL", "30s")
+	t.Setenv("CACHE_PURGE_ON_READ", "true")
+	t.Setenv("CACHE_TTL_JITTER", "0.1")
+	t.Setenv("OTHER_CAPACITY", "5")
+
+	cfg, err := ConfigFromEnv("CACHE")
+	require.NoError(t, err)
+	assert.Equal(t, 1000, cfg.Capacity)
+	assert.Equal(t, SLRU, cfg.EvictionPolicy)
+	assert.Equal(t, 30*time.Second, cfg.ClearingInterval)
+	assert.True(t, cfg.PurgeOnRead)
+	assert.Equal(t, 0.1, cfg.TTLJitter)
+
+	t.Setenv("CACHE_CAPACITY", "lots")
+	_, err = ConfigFromEnv("CACHE_")
+	assert.ErrorContains(t, err, "CACHE_CAPACITY")
+
+	// значение разбирается, но не проходит проверку
+	t.Setenv("CACHE_CAPACITY", "-1")
+	_, err = ConfigFromEnv("CACHE")
+	assert.ErrorContains(t, err, "capacity cannot be negative")
+}
+
+func TestConfigFromFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+		return path
+	}
+
+	jsonPath := write("cache.json", `{"capacity": 1000000, "eviction_policy": "lfu", "grace_period": "1m", "high_watermark": 0.9}`)
+	cfg, err := ConfigFromFile(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1000000, cfg.Capacity)
+	assert.Equal(t, LFU, cfg.EvictionPolicy)
+	assert.Equal(t, time.Minute, cfg.GracePeriod)
+	assert.Equal(t, 0.9, cfg.HighWatermark)
+
+	yamlPath := write("cache.yaml", "capacity: 50\neviction_policy: LRU-K\nlru_k_history: 3\nsnapshot_path: /tmp/cache.gob\n")
+	cfg, err = ConfigFromFile(yamlPath)
+	require.NoError(t, err)
+	assert.Equal(t, 50, cfg.Capacity)
+	assert.Equal(t, LRUK, cfg.EvictionPolicy)
+	assert.Equal(t, 3, cfg.LRUKHistory)
+	assert.Equal(t, "/tmp/cache.gob", cfg.SnapshotPath)
+
+	_, err = ConfigFromFile(write("unknown.json", `{"capacty": 10}`))
+	assert.ErrorContains(t, err, "unknown setting")
+
+	_, err = ConfigFromFile(write("policy.yml", "eviction_policy: tinylfu\n"))
+	assert.ErrorContains(t, err, "unknown eviction policy")
+
+	_, err = ConfigFromFile(write("cache.toml", "capacity = 10"))
+	assert.Error(t, err)
+
+	_, err = ConfigFromFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestCacher_DefaultTTL(t *testing.T) {
+	cache := New(Config{DefaultTTL: 20 * time.Millisecond})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", DefaultExpiration)
+	cache.Set("k2", "v2", 0) // без истечения
+
+	time.Sleep(30 * time.Millisecond)
+	_, err := cache.Get("k1")
+	assert.Error(t, err)
+	_, err = cache.Get("k2")
+	assert.NoError(t, err)
+}
+
+func TestCacher_ApplyConfig(t *testing.T) {
+	cache := New(Config{Capacity: 10, ClearingInterval: -1})
+	defer cache.Close()
+
+	for i := 0; i < 5; i++ {
+		cache.Set(i, i, 20*time.Millisecond)
+	}
+
+	require.NoError(t, cache.ApplyConfig(Config{
+		Capacity:         3,
+		EvictionPolicy:   LFU,
+		DefaultTTL:       time.Minute,
+		ClearingInterval: 10 * time.Millisecond,
+	}))
+	assert.Equal(t, 3, cache.GetCapacity())
+	assert.Equal(t, "LFU", cache.GetEvictionPolicy())
+
+	// запущенный очиститель удаляет истёкшие элементы
+	assert.Eventually(t, func() bool {
+		return cache.Metrics().Items == 0
+	}, time.Second, 10*time.Millisecond)
+
+	cache.Set("k", "v", DefaultExpiration)
+	ttl, err := cache.GetTTL("k")
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+	// недопустимая конфигурация ничего не меняет
+	assert.Error(t, cache.ApplyConfig(Config{Capacity: -1}))
+	assert.Equal(t, 3, cache.GetCapacity())
+
+	// отрицательный интервал останавливает очиститель
+	require.NoError(t, cache.ApplyConfig(Config{Capacity: 3, ClearingInterval: -1}))
+	cache.Set("short", "v", time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 2, cache.Metrics().Items)
+}
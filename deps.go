@@ -0,0 +1,117 @@
+package cacher
+
+import "time"
+
+// dependencies links keys to the keys derived from them. Links never form a cycle.
+type dependencies struct {
+	dependents map[interface{}]map[interface{}]struct{} // Dependency -> dependent keys
+	links      map[interface{}][]interface{}            // Dependent key -> its dependencies
+}
+
+func newDependencies() dependencies {
+	return dependencies{
+		dependents: make(map[interface{}]map[interface{}]struct{}),
+		links:      make(map[interface{}][]interface{}),
+	}
+}
+
+// SetWithDependencies adds a value derived from other keys, e.g. an aggregate of them.
+// When any of deps is deleted, expires, is evicted or gets a new value, the key is
+// removed as well, and so are the keys depending on it in turn. deps need not be cached.
+// Setting the key again replaces its dependencies, and removes the keys depending on it
+// like any new value. Values with dependencies are not moved to the overflow store.
+func (c *Cacher) SetWithDependencies(key, value interface{}, ttl time.Duration, deps ...interface{}) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	keys := make([]interface{}, len(deps))
+	for i, dep := range deps {
+		keys[i] = c.key(dep)
+	}
+	item := c.newItem(value, ttl, 0)
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	if stored, _ := c.store(key, item); stored {
+		c.link(key, keys)
+	}
+}
+
+// Dependents returns the keys set with key as a direct dependency (order not guaranteed).
+func (c *Cacher) Dependents(key interface{}) []interface{} {
+	key = c.key(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]interface{}, 0, len(c.deps.dependents[key]))
+	for dependent := range c.deps.dependents[key] {
+		keys = append(keys, dependent)
+	}
+	return keys
+}
+
+// link records the dependencies of a key. Must be called with c.mu held,
+// right after the key was set.
+func (c *Cacher) link(key interface{}, deps []interface{}) {
+	var linked []interface{}
+	for _, dep := range deps {
+		// Setting the key removed the keys depending on it,
+		// so only a key depending on itself could form a cycle
+		if dep == key {
+			continue
+		}
+		set, ok := c.deps.dependents[dep]
+		if !ok {
+			set = make(map[interface{}]struct{})
+			c.deps.dependents[dep] = set
+		}
+		set[key] = struct{}{}
+		linked = append(linked, dep)
+	}
+	if len(linked) > 0 {
+		c.deps.links[key] = linked
+	}
+}
+
+// unlink forgets the dependencies of a key.
+func (c *Cacher) unlink(key interface{}) {
+	for _, dep := range c.deps.links[key] {
+		set := c.deps.dependents[dep]
+		delete(set, key)
+		if len(set) == 0 {
+			delete(c.deps.dependents, dep)
+		}
+	}
+	delete(c.deps.links, key)
+}
+
+// invalidateDependents removes the keys depending on key, recursively.
+func (c *Cacher) invalidateDependents(key interface{}) {
+	dependents, ok := c.deps.dependents[key]
+	if !ok {
+		return
+	}
+	delete(c.deps.dependents, key)
+	for dependent := range dependents {
+		if _, ok := c.cache[dependent]; ok {
+			c.removeKey(dependent) // Unlinks it and invalidates its own dependents
+		} else {
+			c.unlink(dependent)
+		}
+	}
+}
+
+// expireDependencies removes the expired dependencies of a key that are still cached,
+// which removes the key too. Reports whether the key was removed.
+func (c *Cacher) expireDependencies(key interface{}) bool {
+	for _, dep := range c.deps.links[key] {
+		if item, ok := c.cache[dep]; ok && c.checkExpiration(item) != nil {
+			c.expire(dep, item)
+		}
+	}
+	_, ok := c.cache[key]
+	return !ok
+}
@@ -0,0 +1,103 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_SetWithDependencies(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("order:1", 10, 0)
+	cache.Set("order:2", 20, 0)
+	cache.SetWithDependencies("total", 30, 0, "order:1", "order:2")
+	cache.SetWithDependencies("report", "total 30", 0, "total")
+	assert.ElementsMatch(t, []interface{}{"total"}, cache.Dependents("order:1"))
+
+	// Удаление зависимости удаляет производные ключи каскадом
+	require.NoError(t, cache.Delete("order:1"))
+	_, err := cache.Get("total")
+	assert.Error(t, err)
+	_, err = cache.Get("report")
+	assert.Error(t, err)
+	_, err = cache.Get("order:2")
+	assert.NoError(t, err)
+	assert.Empty(t, cache.Dependents("order:2"))
+}
+
+func TestCacher_DependencyUpdated(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("price", 10, 0)
+	cache.SetWithDependencies("total", 10, 0, "price")
+
+	// Новое значение зависимости тоже инвалидирует
+	cache.Set("price", 12, 0)
+	_, err := cache.Get("total")
+	assert.Error(t, err)
+
+	cache.SetWithDependencies("total", 12, 0, "price")
+	require.NoError(t, cache.Update("price", 15))
+	_, err = cache.Get("total")
+	assert.Error(t, err)
+
+	// Перезапись без зависимостей снимает связи
+	cache.SetWithDependencies("total", 15, 0, "price")
+	cache.Set("total", 15, 0)
+	cache.Set("price", 20, 0)
+	value, err := cache.Get("total")
+	require.NoError(t, err)
+	assert.Equal(t, 15, value)
+}
+
+func TestCacher_DependencyExpired(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("rate", 1.5, 50*time.Millisecond)
+	cache.SetWithDependencies("converted", 150.0, time.Minute, "rate")
+
+	time.Sleep(80 * time.Millisecond)
+	_, err := cache.Get("converted")
+	assert.Error(t, err)
+	assert.Equal(t, 0, cache.Metrics().Items)
+}
+
+func TestCacher_DependencyEvicted(t *testing.T) {
+	cache := New(Config{Capacity: 2, EvictionPolicy: LRU})
+	defer cache.Close()
+
+	cache.Set("a", 1, 0)
+	cache.SetWithDependencies("b", 2, 0, "a")
+	cache.Get("b")
+	cache.Set("c", 3, 0) // вытесняет "a", а с ним и "b"
+
+	_, err := cache.Get("b")
+	assert.Error(t, err)
+	_, err = cache.Get("c")
+	assert.NoError(t, err)
+}
+
+func TestCacher_DependencyCycle(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.SetWithDependencies("a", 1, 0, "a", "b")
+	assert.Empty(t, cache.Dependents("a")) // ключ не зависит от себя
+	assert.Equal(t, []interface{}{"a"}, cache.Dependents("b"))
+
+	// Запись "b" удаляет "a" до связывания, поэтому цикл не возникает
+	cache.SetWithDependencies("b", 2, 0, "a")
+	_, err := cache.Get("a")
+	assert.Error(t, err)
+	assert.Equal(t, []interface{}{"b"}, cache.Dependents("a"))
+	assert.Empty(t, cache.Dependents("b"))
+
+	cache.Clear()
+	assert.Empty(t, cache.Dependents("a"))
+}
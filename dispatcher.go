@@ -0,0 +1,111 @@
+package cacher
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	defaultCallbackWorkers = 4
+	defaultCallbackQueue   = 1024
+)
+
+// dispatcher runs callbacks outside the cache lock on a bounded pool of workers.
+// Workers are started on the first dispatch. Panics in callbacks are recovered and logged.
+type dispatcher struct {
+	size   int
+	start  sync.Once
+	logger *slog.Logger
+
+	mu      sync.Mutex // Guards closing tasks
+	tasks   chan func()
+	closed  bool
+	workers sync.WaitGroup
+
+	dropped atomic.Uint64 // Callbacks dropped because the queue was full or closed
+	panics  atomic.Uint64 // Callbacks that panicked
+}
+
+// newDispatcher creates a dispatcher with the given number of workers and queue size.
+func newDispatcher(workers, queue int, logger *slog.Logger) *dispatcher {
+	return &dispatcher{
+		size:   workers,
+		tasks:  make(chan func(), queue),
+		logger: logger,
+	}
+}
+
+// dispatch queues a callback without blocking.
+// The callback is dropped if the queue is full or the dispatcher is closed.
+func (d *dispatcher) dispatch(task func()) {
+	d.start.Do(func() {
+		for i := 0; i < d.size; i++ {
+			d.workers.Add(1)
+			go d.run()
+		}
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		d.dropped.Add(1)
+		return
+	}
+	select {
+	case d.tasks <- task:
+	default:
+		d.dropped.Add(1)
+		d.logger.Warn("cache callback dropped, queue is full")
+	}
+}
+
+// close stops accepting callbacks. Queued callbacks still run.
+func (d *dispatcher) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.closed {
+		d.closed = true
+		close(d.tasks)
+	}
+}
+
+// wait waits for the queued callbacks to finish after close.
+// Returns the context error if ctx is done first.
+func (d *dispatcher) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run executes callbacks until the queue is closed and drained.
+func (d *dispatcher) run() {
+	defer d.workers.Done()
+
+	for task := range d.tasks {
+		d.safely(task)
+	}
+}
+
+// safely runs a callback, recovering from a panic.
+func (d *dispatcher) safely(task func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.panics.Add(1)
+			d.logger.Error("cache callback panicked", "panic", r)
+		}
+	}()
+	task()
+}
@@ -0,0 +1,77 @@
+package cacher
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_CallbackPanic(t *testing.T) {
+	var calls atomic.Int32
+	cache := New(Config{
+		Capacity: 1,
+		OnEvicted: func(key, value interface{}) {
+			if calls.Add(1) == 1 {
+				panic("boom")
+			}
+		},
+	})
+
+	cache.Set("k1", "v1", time.Minute)
+	cache.Set("k2", "v2", time.Minute) // колбэк паникует
+	cache.Set("k3", "v3", time.Minute) // воркер продолжает работать
+
+	require.NoError(t, cache.Shutdown(context.Background()))
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, uint64(1), cache.Metrics().CallbackPanics)
+}
+
+func TestCacher_CallbackQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	cache := New(Config{
+		Capacity:          1,
+		CallbackWorkers:   1,
+		CallbackQueueSize: 1,
+		OnEvicted: func(key, value interface{}) {
+			<-release
+		},
+	})
+
+	// Set не блокируется, даже если воркер занят и очередь заполнена
+	for i := 0; i < 10; i++ {
+		cache.Set(i, i, time.Minute)
+	}
+	close(release)
+	require.NoError(t, cache.Shutdown(context.Background()))
+
+	m := cache.Metrics()
+	assert.Equal(t, uint64(9), m.Evictions)
+	assert.GreaterOrEqual(t, m.DroppedCallbacks, uint64(7))
+}
+
+func TestCacher_CallbackUsesCache(t *testing.T) {
+	done := make(chan interface{}, 1)
+	var cache *Cacher
+	cache = New(Config{
+		Capacity: 1,
+		OnEvicted: func(key, value interface{}) {
+			v, _ := cache.Get("k2")
+			done <- v
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", time.Minute)
+	cache.Set("k2", "v2", time.Minute)
+
+	select {
+	case v := <-done:
+		assert.Equal(t, "v2", v)
+	case <-time.After(time.Second):
+		t.Fatal("callback did not run")
+	}
+}
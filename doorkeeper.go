@@ -0,0 +1,83 @@
+package cacher
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+var defaultDoorkeeperFPRate = 0.01
+
+// bloom is a bloom filter that resets itself after a fixed number of additions.
+type bloom struct {
+	bits   []uint64
+	hashes int // Number of hash functions
+	added  int // Keys added since the last reset
+	limit  int // Additions before reset
+}
+
+// newBloom creates a filter sized for n keys with false positive rate p.
+func newBloom(n int, p float64) *bloom {
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := max(int(math.Round(m/float64(n)*math.Ln2)), 1)
+	return &bloom{
+		bits:   make([]uint64, (int(m)+63)/64),
+		hashes: k,
+		limit:  n,
+	}
+}
+
+// admit reports whether a new key may be cached.
+// Keys seen for the first time are remembered and rejected.
+func (c *Cacher) admit(key interface{}) bool {
+	if c.doorkeeper == nil {
+		return true
+	}
+	if c.doorkeeper.addIfAbsent(key) {
+		c.logger.Debug("cache doorkeeper rejected key", "key", key)
+		return false
+	}
+	return true
+}
+
+// addIfAbsent adds a key and reports whether it was not present before.
+// When the filter is full, it is cleared before adding.
+func (b *bloom) addIfAbsent(key interface{}) bool {
+	h1, h2 := hashKey(key)
+	if b.contains(h1, h2) {
+		return false
+	}
+
+	if b.added >= b.limit {
+		clear(b.bits)
+		b.added = 0
+	}
+	m := uint64(len(b.bits) * 64)
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % m
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+	b.added++
+	return true
+}
+
+// contains reports whether all bits of a key are set.
+func (b *bloom) contains(h1, h2 uint64) bool {
+	m := uint64(len(b.bits) * 64)
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % m
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashKey returns two independent hashes of a key for double hashing.
+func hashKey(key interface{}) (uint64, uint64) {
+	data := []byte(fmt.Sprintf("%T:%v", key, key))
+	h1, h2 := fnv.New64a(), fnv.New64()
+	h1.Write(data)
+	h2.Write(data)
+	return h1.Sum64(), h2.Sum64() | 1
+}
@@ -0,0 +1,57 @@
+package cacher
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Doorkeeper(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1}, WithDoorkeeper(1000, 0.01))
+	defer cache.Close()
+
+	// Первый Set нового ключа только запоминается
+	cache.Set("key1", "value1", time.Minute)
+	_, err := cache.Get("key1")
+	assert.Error(t, err)
+
+	cache.Set("key1", "value1", time.Minute)
+	got, err := cache.Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", got)
+
+	// Существующие ключи обновляются сразу
+	cache.Set("key1", "value2", time.Minute)
+	got, err = cache.Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value2", got)
+}
+
+func TestBloom_FalsePositiveRate(t *testing.T) {
+	b := newBloom(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		assert.True(t, b.addIfAbsent(i))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if !b.addIfAbsent(fmt.Sprint("other", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 50)
+}
+
+func TestBloom_Reset(t *testing.T) {
+	b := newBloom(2, 0.01)
+	assert.True(t, b.addIfAbsent("a"))
+	assert.True(t, b.addIfAbsent("b"))
+	assert.False(t, b.addIfAbsent("a"))
+
+	// Фильтр заполнен и сбрасывается
+	assert.True(t, b.addIfAbsent("c"))
+	assert.True(t, b.addIfAbsent("a"))
+}
@@ -0,0 +1,77 @@
+package cacher
+
+import (
+	"fmt"
+	"time"
+)
+
+// EntryInfo describes a cached item and its metadata.
+type EntryInfo struct {
+	Key        interface{}
+	Value      interface{}
+	CreatedAt  time.Time     // When the value was set
+	Modified   time.Time     // When the value was last set or updated
+	LastUsedAt time.Time     // Last access time
+	TTL        time.Duration // Configured TTL, 0 if none
+	Deadline   time.Time     // Absolute expiration time, zero if none
+	Remaining  time.Duration // Time left until expiration, 0 if the item never expires
+	Counter    int           // Access counter
+	Priority   int           // Eviction priority
+	Pinned     bool          // Protected from eviction
+	Negative   bool          // Set with SetNegative
+	Version    uint64        // Changes on every write, see SetIfVersion
+	Cost       int64         // Estimated size of the stored value in bytes, 0 if unknown
+}
+
+// Entry returns the value of a key together with its metadata.
+// Unlike Get, it does not count as an access.
+// Returns an error if the key is not found or the TTL has expired.
+func (c *Cacher) Entry(key interface{}) (EntryInfo, error) {
+	key = c.key(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.cache[key]
+	if !ok {
+		return EntryInfo{}, fmt.Errorf("cache not found for key: %v", key)
+	}
+	if err := c.checkExpiration(item); err != nil {
+		return EntryInfo{}, err
+	}
+	return c.entryInfo(key, item), nil
+}
+
+// entryInfo builds the public description of an item.
+func (c *Cacher) entryInfo(key interface{}, item cache) EntryInfo {
+	info := EntryInfo{
+		Key:        key,
+		Value:      c.load(item),
+		CreatedAt:  item.createdAt,
+		Modified:   item.modified(),
+		LastUsedAt: item.lastUsedAt,
+		TTL:        item.ttl,
+		Deadline:   item.deadline,
+		Counter:    item.counter,
+		Priority:   item.priority,
+		Pinned:     item.pinned,
+		Negative:   item.negative,
+		Version:    item.version,
+		Cost:       storedSize(item.value),
+	}
+	if at := c.expiresAt(item); !at.IsZero() {
+		info.Remaining = max(at.Sub(c.now()), 0)
+	}
+	return info
+}
+
+// storedSize returns the size in bytes of a stored []byte or string value.
+func storedSize(value interface{}) int64 {
+	switch v := value.(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	}
+	return 0
+}
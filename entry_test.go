@@ -0,0 +1,52 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Entry(t *testing.T) {
+	cfg := Config{Capacity: 10}
+	cache := New(cfg)
+	defer cache.Close()
+
+	before := time.Now()
+	cache.SetWithPriority("k1", "value", 5*time.Second, 2)
+	cache.Get("k1")
+	require.NoError(t, cache.Pin("k1"))
+
+	info, err := cache.Entry("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", info.Key)
+	assert.Equal(t, "value", info.Value)
+	assert.Equal(t, 5*time.Second, info.TTL)
+	assert.InDelta(t, float64(5*time.Second), float64(info.Remaining), float64(100*time.Millisecond))
+	assert.Equal(t, 2, info.Counter)
+	assert.Equal(t, 2, info.Priority)
+	assert.True(t, info.Pinned)
+	assert.Equal(t, int64(5), info.Cost)
+	assert.False(t, info.CreatedAt.Before(before))
+	assert.False(t, info.LastUsedAt.Before(info.CreatedAt))
+
+	// Entry не считается обращением
+	info, err = cache.Entry("k1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Counter)
+
+	_, err = cache.Entry("missing")
+	assert.Error(t, err)
+}
+
+func TestCacher_EntryExpired(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	cache.Set("k1", "value", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := cache.Entry("k1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package cacher
+
+import (
+	"container/heap"
+	"time"
+)
+
+// expiryEntry is the position of an expiring item in the expiry index.
+type expiryEntry struct {
+	key   interface{}
+	at    time.Time // Expiration time
+	index int       // Position in the heap
+}
+
+// expiryHeap is a min-heap of entries ordered by expiration time.
+type expiryHeap []*expiryEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	entry := x.(*expiryEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// trackExpiry updates the expiry index after an item's TTL, access time or pinning changed.
+// Items that never expire are removed from the index.
+func (c *Cacher) trackExpiry(key interface{}) {
+	item, ok := c.cache[key]
+	if !ok {
+		c.untrackExpiry(key)
+		return
+	}
+	at := c.expiresAt(item)
+	if at.IsZero() {
+		c.untrackExpiry(key)
+		return
+	}
+	at = at.Add(c.grace)
+
+	if entry, ok := c.expiryIndex[key]; ok {
+		entry.at = at
+		heap.Fix(&c.expiry, entry.index)
+		c.wakeExpiryTimer(entry)
+		return
+	}
+
+	entry := c.newExpiryEntry(key)
+	entry.at = at
+	heap.Push(&c.expiry, entry)
+	c.expiryIndex[key] = entry
+	c.wakeExpiryTimer(entry)
+}
+
+// wakeExpiryTimer reschedules the expiry timer if entry became the next to expire.
+func (c *Cacher) wakeExpiryTimer(entry *expiryEntry) {
+	if c.expiryWake == nil || entry.index != 0 {
+		return
+	}
+	select {
+	case c.expiryWake <- struct{}{}:
+	default:
+	}
+}
+
+// runExpiryTimer removes items as soon as they expire, sleeping until
+// the next expiration time in the expiry index.
+func (c *Cacher) runExpiryTimer() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-c.expiryWake:
+		case <-c.ctx.Done():
+			return
+		}
+
+		c.mu.Lock()
+		c.removeExpired()
+		c.checkWatermarks()
+		next := time.Duration(-1)
+		if len(c.expiry) > 0 {
+			// The coarse clock can lag behind the expiration time; retry shortly.
+			next = max(c.expiry[0].at.Sub(c.now()), time.Millisecond)
+		}
+		c.mu.Unlock()
+
+		timer.Stop()
+		if next > 0 {
+			timer.Reset(next)
+		}
+	}
+}
+
+// expiresAt returns when an item expires: after its TTL since the last access
+// or at its deadline, whichever comes first. Returns zero if the item never expires.
+func (c *Cacher) expiresAt(item cache) time.Time {
+	if item.pinned && c.pinnedNoExpire {
+		return time.Time{}
+	}
+	var at time.Time
+	if item.ttl != 0 {
+		at = item.lastUsedAt.Add(item.ttl)
+	}
+	if !item.deadline.IsZero() && (at.IsZero() || item.deadline.Before(at)) {
+		at = item.deadline
+	}
+	return at
+}
+
+// expire removes an expired item, unless it is still within the grace period.
+func (c *Cacher) expire(key interface{}, item cache) {
+	if c.grace > 0 && c.now().Before(c.expiresAt(item).Add(c.grace)) {
+		return
+	}
+	c.removeKey(key)
+	c.counters.expirations++
+	c.notifyExpired(key, item)
+}
+
+// victimExpiring returns the candidate that expires soonest if it expires within
+// expiringWithin. Only the part of the expiry heap within the window is visited,
+// since entries below a later entry expire later still.
+func (c *Cacher) victimExpiring(candidate func(interface{}) bool) (interface{}, bool) {
+	if c.expiringWithin <= 0 || len(c.expiry) == 0 {
+		return nil, false
+	}
+	limit := c.now().Add(c.expiringWithin + c.grace)
+
+	var victim *expiryEntry
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if i >= len(c.expiry) {
+			continue
+		}
+		entry := c.expiry[i]
+		if entry.at.After(limit) || (victim != nil && !entry.at.Before(victim.at)) {
+			continue
+		}
+		if candidate(entry.key) {
+			victim = entry
+		}
+		stack = append(stack, 2*i+1, 2*i+2)
+	}
+	if victim == nil {
+		return nil, false
+	}
+	return victim.key, true
+}
+
+// untrackExpiry removes a key from the expiry index.
+func (c *Cacher) untrackExpiry(key interface{}) {
+	entry, ok := c.expiryIndex[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&c.expiry, entry.index)
+	delete(c.expiryIndex, key)
+	releaseExpiryEntry(entry)
+}
+
+// purgeBeforeRead removes expired items before a bulk read if configured.
+func (c *Cacher) purgeBeforeRead() {
+	if c.purgeOnRead {
+		c.removeExpired()
+		c.checkWatermarks()
+	}
+}
+
+// removeExpired removes all expired items past the grace period, soonest first,
+// and returns their number.
+func (c *Cacher) removeExpired() int {
+	now := c.now()
+	removed := 0
+	for len(c.expiry) > 0 && c.expiry[0].at.Before(now) {
+		key := c.expiry[0].key
+		item := c.cache[key]
+		c.removeKey(key)
+		c.notifyExpired(key, item)
+		removed++
+	}
+	c.counters.expirations += uint64(removed)
+	return removed
+}
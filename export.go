@@ -0,0 +1,94 @@
+package cacher
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// exportRecord is the exported description of an item. Values are not exported.
+type exportRecord struct {
+	Key        string    `json:"key"`
+	Size       int64     `json:"size"`         // Estimated bytes held by the value
+	TTL        int64     `json:"ttl_ms"`       // Configured TTL in milliseconds, 0 if none
+	Remaining  int64     `json:"remaining_ms"` // Milliseconds until expiration, 0 if the item never expires
+	Counter    int       `json:"counter"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"` // Omitted if the item never expires
+}
+
+// exportRecords describes all unexpired items. Sizes are measured after the lock is released.
+func (c *Cacher) exportRecords() []exportRecord {
+	c.mu.RLock()
+	keys := make([]interface{}, 0, len(c.cache))
+	values := make([]interface{}, 0, len(c.cache))
+	records := make([]exportRecord, 0, len(c.cache))
+	now := c.now()
+	for key, item := range c.cache {
+		if c.checkExpiration(item) != nil {
+			continue
+		}
+		record := exportRecord{
+			TTL:        item.ttl.Milliseconds(),
+			Counter:    item.counter,
+			CreatedAt:  item.createdAt,
+			LastUsedAt: item.lastUsedAt,
+			ExpiresAt:  c.expiresAt(item),
+		}
+		if !record.ExpiresAt.IsZero() {
+			record.Remaining = max(record.ExpiresAt.Sub(now), 0).Milliseconds()
+		}
+		keys = append(keys, key)
+		values = append(values, item.value)
+		records = append(records, record)
+	}
+	c.mu.RUnlock()
+
+	for i := range records {
+		records[i].Key = fmt.Sprint(keys[i])
+		records[i].Size = sizeOf(values[i])
+	}
+	return records
+}
+
+// ExportNDJSON writes one JSON object per unexpired item and line, with its key,
+// estimated size, TTL, access counter and timestamps, for analysis with tools like jq.
+// Values are not exported. Keys are formatted with fmt.Sprint.
+func (c *Cacher) ExportNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, record := range c.exportRecords() {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportCSV writes the same fields as ExportNDJSON as CSV with a header row.
+// Zero expiration times are written as empty fields.
+func (c *Cacher) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"key", "size", "ttl_ms", "remaining_ms", "counter", "created_at", "last_used_at", "expires_at"})
+	for _, record := range c.exportRecords() {
+		var expiresAt string
+		if !record.ExpiresAt.IsZero() {
+			expiresAt = record.ExpiresAt.Format(time.RFC3339Nano)
+		}
+		cw.Write([]string{
+			record.Key,
+			strconv.FormatInt(record.Size, 10),
+			strconv.FormatInt(record.TTL, 10),
+			strconv.FormatInt(record.Remaining, 10),
+			strconv.Itoa(record.Counter),
+			record.CreatedAt.Format(time.RFC3339Nano),
+			record.LastUsedAt.Format(time.RFC3339Nano),
+			expiresAt,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
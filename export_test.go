@@ -0,0 +1,65 @@
+package cacher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_ExportNDJSON(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("k1", "value", time.Minute)
+	cache.Set(2, []byte("v2"), 0)
+	cache.Set("k3", "expired", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, _ = cache.Get("k1")
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.ExportNDJSON(&buf))
+
+	records := map[string]map[string]interface{}{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records[record["key"].(string)] = record
+	}
+	// Истёкший элемент не выгружается
+	require.Len(t, records, 2)
+
+	k1 := records["k1"]
+	assert.Equal(t, float64(time.Minute.Milliseconds()), k1["ttl_ms"])
+	assert.InDelta(t, time.Minute.Milliseconds(), k1["remaining_ms"], 1000)
+	assert.Equal(t, float64(2), k1["counter"])
+	assert.Positive(t, k1["size"])
+	assert.NotContains(t, k1, "value")
+
+	assert.Equal(t, float64(0), records["2"]["remaining_ms"])
+	assert.NotContains(t, records["2"], "expires_at")
+}
+
+func TestCacher_ExportCSV(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("k1", "value", 0)
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.ExportCSV(&buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"key", "size", "ttl_ms", "remaining_ms", "counter", "created_at", "last_used_at", "expires_at"}, rows[0])
+	assert.Equal(t, "k1", rows[1][0])
+	assert.Equal(t, "1", rows[1][4])
+	assert.Empty(t, rows[1][7])
+}
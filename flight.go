@@ -0,0 +1,54 @@
+package cacher
+
+import (
+	"errors"
+	"sync"
+)
+
+// errFlightPanicked is returned to waiters when the shared call panicked.
+var errFlightPanicked = errors.New("shared call panicked")
+
+// call is an in-flight or completed shared function call.
+type call struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+// doKey keeps the calls of Do apart from those of Memoize with the same key.
+type doKey struct {
+	key interface{}
+}
+
+// Do runs fn once per key at a time: concurrent callers with the same key wait
+// for the running call and receive its value and error. The result is not cached,
+// so expensive idempotent work can be deduplicated without storing it; use Memoize
+// to also cache it. If fn panics, the caller running it panics and waiters get an error.
+func (c *Cacher) Do(key interface{}, fn func() (interface{}, error)) (interface{}, error) {
+	return c.do(doKey{key: c.key(key)}, fn)
+}
+
+// do runs fn once per key at a time. Concurrent callers with the same key
+// wait for the running call and receive its result.
+func (c *Cacher) do(key interface{}, fn func() (interface{}, error)) (interface{}, error) {
+	c.flightMu.Lock()
+	if cl, ok := c.flights[key]; ok {
+		c.flightMu.Unlock()
+		cl.wg.Wait()
+		return cl.value, cl.err
+	}
+	cl := &call{err: errFlightPanicked}
+	cl.wg.Add(1)
+	c.flights[key] = cl
+	c.flightMu.Unlock()
+
+	defer func() {
+		c.flightMu.Lock()
+		delete(c.flights, key)
+		c.flightMu.Unlock()
+		cl.wg.Done()
+	}()
+
+	cl.value, cl.err = fn()
+	return cl.value, cl.err
+}
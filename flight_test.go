@@ -0,0 +1,120 @@
+package cacher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Do(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func() (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return "report", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.Do("report", fn)
+			assert.NoError(t, err)
+			results[i] = value
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, value := range results {
+		assert.Equal(t, "report", value)
+	}
+
+	// Результат не сохраняется в кэше
+	_, err := cache.Get("report")
+	assert.Error(t, err)
+	assert.Equal(t, 0, cache.Metrics().Items)
+
+	// Следующий вызов после завершения выполняется заново
+	value, err := cache.Do("report", func() (interface{}, error) { return "fresh", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", value)
+}
+
+func TestCacher_DoKeyFunc(t *testing.T) {
+	cache := New(Config{KeyFunc: func(key interface{}) string { return strings.ToLower(fmt.Sprint(key)) }})
+	defer cache.Close()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func() (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return "report", nil
+	}
+
+	// Ключи, совпадающие после KeyFunc, делят один вызов
+	var wg sync.WaitGroup
+	for _, key := range []string{"Report", "REPORT", "report"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.Do(key, fn)
+			assert.NoError(t, err)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCacher_DoError(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	errFailed := errors.New("failed")
+	_, err := cache.Do("k", func() (interface{}, error) { return nil, errFailed })
+	assert.ErrorIs(t, err, errFailed)
+}
+
+func TestCacher_DoSeparateFromMemoize(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	release := make(chan struct{})
+	get := Memoize(cache, time.Minute, func(key string) (string, error) {
+		<-release
+		return "memoized", nil
+	})
+
+	done := make(chan string)
+	go func() {
+		value, _ := get("k")
+		done <- value
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// Do с тем же ключом не ждёт вызова Memoize
+	value, err := cache.Do("k", func() (interface{}, error) { return "done", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "done", value)
+
+	close(release)
+	assert.Equal(t, "memoized", <-done)
+}
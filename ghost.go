@@ -0,0 +1,60 @@
+package cacher
+
+// ghostList remembers the most recently evicted keys, without their values,
+// to tell how many misses a larger cache would have turned into hits.
+type ghostList struct {
+	size  int
+	keys  *keyList // Evicted keys, most recent at the front
+	index map[interface{}]*element
+	hits  uint64 // Misses on keys in the list
+}
+
+func newGhostList(size int, counters *counters) *ghostList {
+	return &ghostList{
+		size:  size,
+		keys:  newKeyList(counters),
+		index: make(map[interface{}]*element),
+	}
+}
+
+// add remembers an evicted key, forgetting the oldest one if the list is full.
+func (g *ghostList) add(key interface{}) {
+	if e, ok := g.index[key]; ok {
+		g.keys.MoveToFront(e)
+		return
+	}
+	if g.keys.Len() >= g.size {
+		g.remove(g.keys.Back().Value)
+	}
+	g.index[key] = g.keys.PushFront(key)
+}
+
+// miss counts a miss if the key was recently evicted, and forgets the key.
+func (g *ghostList) miss(key interface{}) {
+	if _, ok := g.index[key]; ok {
+		g.hits++
+		g.remove(key)
+	}
+}
+
+// remove forgets a key.
+func (g *ghostList) remove(key interface{}) {
+	if e, ok := g.index[key]; ok {
+		g.keys.Remove(e)
+		delete(g.index, key)
+	}
+}
+
+// WouldHaveHit returns how many Get misses were on keys among the last GhostKeys
+// evicted ones, that is, misses a larger cache would have served. A high number
+// compared to Metrics().Misses suggests increasing the capacity.
+// Returns 0 if GhostKeys is not set.
+func (c *Cacher) WouldHaveHit() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.ghosts == nil {
+		return 0
+	}
+	return c.ghosts.hits
+}
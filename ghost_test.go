@@ -0,0 +1,38 @@
+package cacher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacher_WouldHaveHit(t *testing.T) {
+	cache := New(Config{Capacity: 2, GhostKeys: 2})
+	defer cache.Close()
+
+	for i := 0; i < 5; i++ {
+		cache.Set(i, i, 0) // вытесняются 0, 1, 2; в списке остаются 1 и 2
+	}
+
+	cache.Get(0)         // слишком давно вытеснен
+	cache.Get(2)         // был бы найден
+	cache.Get(2)         // второй промах уже не считается
+	cache.Get("missing") // никогда не было в кэше
+	_ = cache.Delete(1)  // удаление забывает ключ
+	cache.Get(1)
+
+	assert.Equal(t, uint64(1), cache.WouldHaveHit())
+	m := cache.Metrics()
+	assert.Equal(t, uint64(1), m.WouldHaveHit)
+	assert.Equal(t, uint64(5), m.Misses)
+}
+
+func TestCacher_WouldHaveHitDisabled(t *testing.T) {
+	cache := New(Config{Capacity: 1})
+	defer cache.Close()
+
+	cache.Set(1, 1, 0)
+	cache.Set(2, 2, 0)
+	cache.Get(1)
+	assert.Equal(t, uint64(0), cache.WouldHaveHit())
+}
@@ -0,0 +1,116 @@
+package cacher
+
+// KeysMatching returns the unexpired string keys that match a glob pattern
+// (order not guaranteed), like Redis KEYS: * matches any sequence of characters,
+// ? any single character, [abc] and [a-z] a character in the set, [^abc] one not
+// in the set, and \ escapes the next character. Keys of other types are skipped.
+func (c *Cacher) KeysMatching(pattern string) []interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var keys []interface{}
+	for key, item := range c.cache {
+		s, ok := key.(string)
+		if ok && matchGlob(pattern, s) && c.checkExpiration(item) == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// DeleteMatching removes the items with string keys matching a glob pattern,
+// including expired ones, and returns how many were removed. See KeysMatching for the syntax.
+func (c *Cacher) DeleteMatching(pattern string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key := range c.cache {
+		if s, ok := key.(string); ok && matchGlob(pattern, s) {
+			c.removeKey(key)
+			removed++
+		}
+	}
+	c.checkWatermarks()
+	return removed
+}
+
+// matchGlob reports whether s matches a glob pattern.
+// On a mismatch after *, it backtracks to let the star match one more character.
+func matchGlob(pattern, s string) bool {
+	p, i := 0, 0
+	star, next := -1, 0
+	for i < len(s) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				star, next = p, i
+				p++
+				continue
+			case '?':
+				p++
+				i++
+				continue
+			case '[':
+				if end, ok := matchClass(pattern, p, s[i]); ok {
+					p = end
+					i++
+					continue
+				}
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == s[i] {
+					p += 2
+					i++
+					continue
+				}
+			default:
+				if pattern[p] == s[i] {
+					p++
+					i++
+					continue
+				}
+			}
+		}
+		if star < 0 {
+			return false
+		}
+		next++
+		p, i = star+1, next
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// matchClass matches b against the character class starting at pattern[start] ('[').
+// Returns the position after the class and whether b is in it.
+// An unterminated class matches nothing.
+func matchClass(pattern string, start int, b byte) (int, bool) {
+	p := start + 1
+	negate := p < len(pattern) && (pattern[p] == '^' || pattern[p] == '!')
+	if negate {
+		p++
+	}
+	matched := false
+	for first := true; p < len(pattern) && (first || pattern[p] != ']'); first = false {
+		lo := pattern[p]
+		if lo == '\\' && p+1 < len(pattern) {
+			p++
+			lo = pattern[p]
+		}
+		hi := lo
+		if p+2 < len(pattern) && pattern[p+1] == '-' && pattern[p+2] != ']' {
+			hi = pattern[p+2]
+			p += 2
+		}
+		if lo <= b && b <= hi {
+			matched = true
+		}
+		p++
+	}
+	if p >= len(pattern) {
+		return 0, false
+	}
+	return p + 1, matched != negate
+}
@@ -0,0 +1,57 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"*", "user:1/profile", true},
+		{"user:*", "user:42", true},
+		{"user:*", "session:42", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h*llo", "heeeello", true},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"h[a-c]llo", "hdllo", false},
+		{`h\*llo`, "h*llo", true},
+		{`h\*llo`, "hello", false},
+		{"*:*:end", "a:b:c:end", true},
+		{"*x", "abc", false},
+		{"[abc", "a", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchGlob(tt.pattern, tt.s), "%q ~ %q", tt.pattern, tt.s)
+	}
+}
+
+func TestCacher_KeysMatching(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("user:1", "a", 0)
+	cache.Set("user:2", "b", 0)
+	cache.Set("user:3", "c", time.Millisecond)
+	cache.Set("session:1", "d", 0)
+	cache.Set(42, "e", 0) // нестроковые ключи пропускаются
+	time.Sleep(5 * time.Millisecond)
+
+	assert.ElementsMatch(t, []interface{}{"user:1", "user:2"}, cache.KeysMatching("user:*"))
+	assert.Empty(t, cache.KeysMatching("order:*"))
+
+	assert.Equal(t, 3, cache.DeleteMatching("user:?"))
+	assert.ElementsMatch(t, []interface{}{"session:1"}, cache.KeysMatching("*"))
+	_, err := cache.Get(42)
+	assert.NoError(t, err)
+}
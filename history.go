@@ -0,0 +1,62 @@
+package cacher
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoPrevious is returned by GetPrevious when a key has no overwritten value kept.
+var ErrNoPrevious = errors.New("no previous value")
+
+// GetPrevious returns the value a key had before it was last overwritten with Set or
+// Update, e.g. to roll back a bad cache fill by setting it again. Requires
+// Config.HistoryDepth. Reads do not count as accesses.
+// Returns an error if the key is not found or the TTL has expired,
+// and ErrNoPrevious if no previous value is kept.
+func (c *Cacher) GetPrevious(key interface{}) (interface{}, error) {
+	values, err := c.History(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, ErrNoPrevious
+	}
+	return values[0], nil
+}
+
+// History returns the previous values of a key, newest first, up to Config.HistoryDepth.
+// Values that had expired when they were overwritten are not kept.
+// Reads do not count as accesses.
+// Returns an error if the key is not found or the TTL has expired.
+func (c *Cacher) History(key interface{}) ([]interface{}, error) {
+	key = c.key(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.cache[key]
+	if !ok || item.negative {
+		return nil, fmt.Errorf("cache not found for key: %v", key)
+	}
+	if err := c.checkExpiration(item); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(item.previous))
+	for i, prev := range item.previous {
+		values[i] = c.load(prev)
+	}
+	return values, nil
+}
+
+// previousOf returns the previous values to keep when old is overwritten.
+// Must be called with c.mu held.
+func (c *Cacher) previousOf(old cache) []cache {
+	if old.negative || c.checkExpiration(old) != nil {
+		return old.previous
+	}
+	prev := cache{value: old.value, compressed: old.compressed, encoded: old.encoded, cloner: old.cloner}
+	previous := make([]cache, 0, min(len(old.previous)+1, c.historyDepth))
+	previous = append(previous, prev)
+	return append(previous, old.previous[:min(len(old.previous), c.historyDepth-1)]...)
+}
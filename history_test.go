@@ -0,0 +1,80 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_GetPrevious(t *testing.T) {
+	cache := New(Config{HistoryDepth: 2})
+	defer cache.Close()
+
+	cache.Set("config", "v1", time.Minute)
+	_, err := cache.GetPrevious("config")
+	assert.ErrorIs(t, err, ErrNoPrevious)
+
+	cache.Set("config", "v2", time.Minute)
+	require.NoError(t, cache.Update("config", "v3"))
+	cache.Set("config", "v4", time.Minute)
+
+	prev, err := cache.GetPrevious("config")
+	require.NoError(t, err)
+	assert.Equal(t, "v3", prev)
+
+	// Хранится не больше HistoryDepth значений, новые первыми
+	values, err := cache.History("config")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"v3", "v2"}, values)
+
+	// Откат к предыдущему значению
+	cache.Set("config", prev, time.Minute)
+	value, err := cache.Get("config")
+	require.NoError(t, err)
+	assert.Equal(t, "v3", value)
+
+	// История удаляется вместе с ключом
+	cache.Delete("config")
+	_, err = cache.GetPrevious("config")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNoPrevious)
+}
+
+func TestCacher_HistoryExpired(t *testing.T) {
+	cache := New(Config{HistoryDepth: 3})
+	defer cache.Close()
+
+	cache.Set("k", "old", time.Minute)
+	cache.Set("k", "stale", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	// Истёкшее значение не попадает в историю, более старые сохраняются
+	cache.Set("k", "new", time.Minute)
+	values, err := cache.History("k")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"old"}, values)
+}
+
+func TestCacher_HistoryCompressed(t *testing.T) {
+	cache := New(Config{HistoryDepth: 1, CompressionThreshold: 8, Codec: GobCodec{}})
+	defer cache.Close()
+
+	cache.Set("k", "a long enough value", 0)
+	cache.Set("k", "another long value", 0)
+
+	prev, err := cache.GetPrevious("k")
+	require.NoError(t, err)
+	assert.Equal(t, "a long enough value", prev)
+}
+
+func TestCacher_HistoryDisabled(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("k", 1, 0)
+	cache.Set("k", 2, 0)
+	_, err := cache.GetPrevious("k")
+	assert.ErrorIs(t, err, ErrNoPrevious)
+}
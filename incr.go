@@ -0,0 +1,81 @@
+package cacher
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	// ErrNotInteger is returned by Increment when the cached value is not an integer.
+	ErrNotInteger = errors.New("value is not an integer")
+
+	// ErrNotStored is returned by Increment when a new counter was kept out
+	// by a tombstone or the doorkeeper.
+	ErrNotStored = errors.New("value not stored")
+)
+
+// Increment atomically adds delta to the integer value of a key and returns the new value.
+// If the key is not cached or has expired, it is set to delta with the given TTL,
+// so the first increment starts a window and later increments keep its TTL.
+// Returns ErrNotInteger if the cached value is not an integer, and ErrNotStored
+// or a size limit error if a new counter could not be stored.
+// Counters are stored as int64; increments do not count as accesses.
+func (c *Cacher) Increment(key interface{}, delta int64, ttl time.Duration) (int64, error) {
+	n, _, err := c.IncrementMax(key, delta, math.MaxInt64, ttl)
+	return n, err
+}
+
+// IncrementMax is like Increment, but only adds delta if the result does not exceed
+// limit, checking and adding atomically. It returns the value of the counter and
+// whether delta was added. A counter that would start above limit is not created.
+func (c *Cacher) IncrementMax(key interface{}, delta, limit int64, ttl time.Duration) (int64, bool, error) {
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.cache[key]
+	if !ok || item.negative || c.checkExpiration(item) != nil {
+		if delta > limit {
+			return 0, false, nil
+		}
+		stored, err := c.store(key, c.newItem(delta, ttl, 0))
+		if !stored {
+			if err == nil {
+				err = ErrNotStored
+			}
+			return 0, false, err
+		}
+		return delta, true, nil
+	}
+
+	n, ok := toInt64(c.load(item))
+	if !ok {
+		return 0, false, ErrNotInteger
+	}
+	if n+delta > limit {
+		return n, false, nil
+	}
+	n += delta
+	c.replace(key, item, n)
+	return n, true, nil
+}
+
+// toInt64 converts an integer value to int64. Whole float64 values are accepted,
+// as codecs such as JSON decode numbers to float64.
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,119 @@
+package cacher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Increment(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	n, err := cache.Increment("hits", 1, 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = cache.Increment("hits", 5, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+
+	n, err = cache.Increment("hits", -2, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	value, err := cache.Get("hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), value)
+
+	// TTL первого инкремента сохраняется, по истечении счёт начинается заново
+	time.Sleep(150 * time.Millisecond)
+	n, err = cache.Increment("hits", 1, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	cache.Set("name", "alice", 0)
+	_, err = cache.Increment("name", 1, 0)
+	assert.ErrorIs(t, err, ErrNotInteger)
+
+	// Обычные int тоже можно увеличивать
+	cache.Set("int", 10, 0)
+	n, err = cache.Increment("int", 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+}
+
+func TestCacher_IncrementConcurrent(t *testing.T) {
+	cache := New(Config{Codec: GobCodec{}})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				cache.Increment("counter", 1, time.Minute)
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, err := cache.Get("counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), value)
+}
+
+func TestCacher_IncrementMax(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	n, ok, err := cache.IncrementMax("k", 3, 5, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), n)
+
+	// Превышение лимита не меняет счётчик
+	n, ok, err = cache.IncrementMax("k", 3, 5, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, int64(3), n)
+
+	n, ok, err = cache.IncrementMax("k", 2, 5, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), n)
+
+	// Новый счётчик сверх лимита не создаётся
+	_, ok, err = cache.IncrementMax("big", 10, 5, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = cache.Get("big")
+	assert.Error(t, err)
+}
+
+func TestCacher_IncrementNotStored(t *testing.T) {
+	cache := New(Config{TombstoneTTL: time.Minute})
+	defer cache.Close()
+
+	cache.Set("k", int64(1), 0)
+	require.NoError(t, cache.Delete("k"))
+
+	// Надгробие не даёт создать счётчик заново
+	_, err := cache.Increment("k", 1, 0)
+	assert.ErrorIs(t, err, ErrNotStored)
+}
+
+func TestToInt64(t *testing.T) {
+	n, ok := toInt64(float64(42))
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), n)
+
+	_, ok = toInt64(1.5)
+	assert.False(t, ok)
+	_, ok = toInt64("1")
+	assert.False(t, ok)
+}
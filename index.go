@@ -0,0 +1,126 @@
+package cacher
+
+import "fmt"
+
+// IndexFunc returns the terms a value is indexed under, e.g. the customer ID of an order.
+type IndexFunc func(value interface{}) []string
+
+// index maps the terms of a secondary index to the keys of the items with those terms.
+type index struct {
+	fn    IndexFunc
+	terms map[string]map[interface{}]struct{} // Term -> keys
+	keys  map[interface{}][]string            // Key -> terms
+}
+
+func newIndex(fn IndexFunc) *index {
+	return &index{
+		fn:    fn,
+		terms: make(map[string]map[interface{}]struct{}),
+		keys:  make(map[interface{}][]string),
+	}
+}
+
+// AddIndex registers a secondary index and builds it for the cached items.
+// Returns an error if an index with the same name exists.
+func (c *Cacher) AddIndex(name string, fn IndexFunc) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.indexes[name]; ok {
+		return fmt.Errorf("index already exists: %s", name)
+	}
+	idx := newIndex(fn)
+	c.indexes[name] = idx
+	for key, item := range c.cache {
+		if !item.negative {
+			idx.add(key, c.load(item))
+		}
+	}
+	return nil
+}
+
+// GetByIndex returns the unexpired values indexed under term (order not guaranteed).
+// Returns an error if the index does not exist.
+func (c *Cacher) GetByIndex(name, term string) ([]interface{}, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	idx, ok := c.indexes[name]
+	if !ok {
+		return nil, fmt.Errorf("index not found: %s", name)
+	}
+	values := make([]interface{}, 0, len(idx.terms[term]))
+	for key := range idx.terms[term] {
+		if item := c.cache[key]; c.checkExpiration(item) == nil {
+			values = append(values, c.load(item))
+		}
+	}
+	return values, nil
+}
+
+// DeleteByIndex removes the items indexed under term and returns how many were removed.
+// Returns an error if the index does not exist.
+func (c *Cacher) DeleteByIndex(name, term string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	idx, ok := c.indexes[name]
+	if !ok {
+		return 0, fmt.Errorf("index not found: %s", name)
+	}
+	removed := 0
+	for key := range idx.terms[term] {
+		c.removeKey(key)
+		removed++
+	}
+	c.checkWatermarks()
+	return removed, nil
+}
+
+// reindex updates the secondary indexes after an item was set or its value changed.
+func (c *Cacher) reindex(key interface{}, item cache) {
+	if len(c.indexes) == 0 {
+		return
+	}
+	var value interface{}
+	if !item.negative {
+		value = c.load(item)
+	}
+	for _, idx := range c.indexes {
+		idx.remove(key)
+		if !item.negative {
+			idx.add(key, value)
+		}
+	}
+}
+
+// unindex removes a key from the secondary indexes.
+func (c *Cacher) unindex(key interface{}) {
+	for _, idx := range c.indexes {
+		idx.remove(key)
+	}
+}
+
+func (idx *index) add(key, value interface{}) {
+	terms := idx.fn(value)
+	if len(terms) == 0 {
+		return
+	}
+	idx.keys[key] = terms
+	for _, term := range terms {
+		if idx.terms[term] == nil {
+			idx.terms[term] = make(map[interface{}]struct{})
+		}
+		idx.terms[term][key] = struct{}{}
+	}
+}
+
+func (idx *index) remove(key interface{}) {
+	for _, term := range idx.keys[key] {
+		delete(idx.terms[term], key)
+		if len(idx.terms[term]) == 0 {
+			delete(idx.terms, term)
+		}
+	}
+	delete(idx.keys, key)
+}
@@ -0,0 +1,84 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	ID       int
+	Customer string
+	Tags     []string
+}
+
+func TestCacher_Index(t *testing.T) {
+	cache := New(Config{
+		Indexes: map[string]IndexFunc{
+			"customer": func(value interface{}) []string {
+				return []string{value.(order).Customer}
+			},
+		},
+	})
+	defer cache.Close()
+
+	cache.Set(1, order{ID: 1, Customer: "alice", Tags: []string{"new"}}, 0)
+	cache.Set(2, order{ID: 2, Customer: "alice"}, 0)
+	cache.Set(3, order{ID: 3, Customer: "bob", Tags: []string{"new", "paid"}}, 0)
+
+	got, err := cache.GetByIndex("customer", "alice")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []interface{}{order{ID: 1, Customer: "alice", Tags: []string{"new"}}, order{ID: 2, Customer: "alice"}}, got)
+
+	// индекс по нескольким терминам добавляется для существующих элементов
+	require.NoError(t, cache.AddIndex("tag", func(value interface{}) []string {
+		return value.(order).Tags
+	}))
+	assert.Error(t, cache.AddIndex("tag", nil))
+	got, err = cache.GetByIndex("tag", "new")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	// при перезаписи значение переиндексируется
+	cache.Set(2, order{ID: 2, Customer: "bob"}, 0)
+	got, _ = cache.GetByIndex("customer", "alice")
+	assert.Len(t, got, 1)
+
+	removed, err := cache.DeleteByIndex("customer", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	_, err = cache.Get(3)
+	assert.Error(t, err)
+	got, _ = cache.GetByIndex("tag", "paid")
+	assert.Empty(t, got)
+
+	_, err = cache.GetByIndex("missing", "x")
+	assert.Error(t, err)
+	_, err = cache.DeleteByIndex("missing", "x")
+	assert.Error(t, err)
+}
+
+func TestCacher_IndexExpiredAndEvicted(t *testing.T) {
+	cache := New(Config{
+		Capacity: 2,
+		Indexes: map[string]IndexFunc{
+			"value": func(value interface{}) []string { return []string{value.(string)} },
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("k1", "x", 10*time.Millisecond)
+	cache.Set("k2", "y", 0)
+	cache.Set("k3", "y", 0) // k1 вытесняется
+
+	got, _ := cache.GetByIndex("value", "x")
+	assert.Empty(t, got)
+	got, _ = cache.GetByIndex("value", "y")
+	assert.Len(t, got, 2)
+
+	cache.Clear()
+	got, _ = cache.GetByIndex("value", "y")
+	assert.Empty(t, got)
+}
@@ -0,0 +1,128 @@
+package cacher
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// KeyStats describes how a key was used since its stats were last reset.
+type KeyStats struct {
+	Hits      uint64      // Get calls that found a value
+	Misses    uint64      // Get calls for the key while it was missing or expired
+	Evictions uint64      // Times the key was evicted by capacity
+	Accesses  []time.Time // Times of the last hits, oldest first
+}
+
+// maxTrackedKeys is the number of keys whose stats a keyTracker keeps.
+const maxTrackedKeys = 10000
+
+// keyTracker records per-key stats for debugging. Unlike items, the stats
+// of a key survive its eviction. Only the maxTrackedKeys most recently used
+// keys are kept, so misses on ever new keys do not grow it without bound.
+type keyTracker struct {
+	size  int      // Access times kept per key
+	keys  *keyList // Tracked keys, most recently used at the front
+	index map[interface{}]*element
+	stats map[interface{}]*KeyStats
+}
+
+func newKeyTracker(size int, counters *counters) *keyTracker {
+	return &keyTracker{
+		size:  size,
+		keys:  newKeyList(counters),
+		index: make(map[interface{}]*element),
+		stats: make(map[interface{}]*KeyStats),
+	}
+}
+
+// get returns the stats of a key, creating them if needed and forgetting
+// the least recently used key if too many are tracked.
+func (t *keyTracker) get(key interface{}) *KeyStats {
+	if stats, ok := t.stats[key]; ok {
+		t.keys.MoveToFront(t.index[key])
+		return stats
+	}
+	if t.keys.Len() >= maxTrackedKeys {
+		t.remove(t.keys.Back().Value)
+	}
+	stats := new(KeyStats)
+	t.stats[key] = stats
+	t.index[key] = t.keys.PushFront(key)
+	return stats
+}
+
+// remove forgets the stats of a key.
+func (t *keyTracker) remove(key interface{}) {
+	if e, ok := t.index[key]; ok {
+		t.keys.Remove(e)
+		delete(t.index, key)
+		delete(t.stats, key)
+	}
+}
+
+// hit counts a hit and remembers its time, keeping only the last size times.
+func (t *keyTracker) hit(key interface{}, at time.Time) {
+	stats := t.get(key)
+	stats.Hits++
+	if len(stats.Accesses) >= t.size {
+		stats.Accesses = slices.Delete(stats.Accesses, 0, len(stats.Accesses)-t.size+1)
+	}
+	stats.Accesses = append(stats.Accesses, at)
+}
+
+// miss counts a miss.
+func (t *keyTracker) miss(key interface{}) {
+	t.get(key).Misses++
+}
+
+// evicted counts an eviction.
+func (t *keyTracker) evicted(key interface{}) {
+	t.get(key).Evictions++
+}
+
+// KeyStats returns the hits, misses, evictions and last access times recorded
+// for a key, including keys that are no longer cached.
+// Returns an error if TrackKeys is not set or nothing was recorded for the key.
+func (c *Cacher) KeyStats(key interface{}) (KeyStats, error) {
+	key = c.key(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.keyTracker == nil {
+		return KeyStats{}, fmt.Errorf("key stats are not tracked")
+	}
+	stats, ok := c.keyTracker.stats[key]
+	if !ok {
+		return KeyStats{}, fmt.Errorf("no stats for key: %v", key)
+	}
+	result := *stats
+	result.Accesses = slices.Clone(stats.Accesses)
+	return result, nil
+}
+
+// ResetCounter sets the access counter of a cached key to 0 and forgets
+// its KeyStats, so later accesses can be observed from a clean state.
+// Returns an error if the key is neither cached nor tracked.
+func (c *Cacher) ResetCounter(key interface{}) error {
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found := false
+	if c.keyTracker != nil {
+		_, found = c.keyTracker.stats[key]
+		c.keyTracker.remove(key)
+	}
+	if item, ok := c.cache[key]; ok && c.checkExpiration(item) == nil {
+		item.counter = 0
+		c.cache[key] = item
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("cache not found for key: %v", key)
+	}
+	return nil
+}
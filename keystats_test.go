@@ -0,0 +1,80 @@
+package cacher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_KeyStats(t *testing.T) {
+	cfg := Config{Capacity: 1, TrackKeys: 2}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Get("k1") // промах до записи
+	cache.Set("k1", "v1", 0)
+	for i := 0; i < 3; i++ {
+		cache.Get("k1")
+	}
+	cache.Set("k2", "v2", 0) // вытесняет k1
+	cache.Get("k1")
+
+	stats, err := cache.KeyStats("k1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	require.Len(t, stats.Accesses, 2) // хранятся только последние два
+	assert.False(t, stats.Accesses[1].Before(stats.Accesses[0]))
+
+	_, err = cache.KeyStats("unknown")
+	assert.Error(t, err)
+
+	require.NoError(t, cache.ResetCounter("k1"))
+	_, err = cache.KeyStats("k1")
+	assert.Error(t, err)
+	assert.Error(t, cache.ResetCounter("k1"))
+}
+
+func TestCacher_ResetCounter(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 0)
+	cache.Get("k1")
+	cache.Get("k1")
+	counter, err := cache.GetCounter("k1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, counter)
+
+	require.NoError(t, cache.ResetCounter("k1"))
+	counter, err = cache.GetCounter("k1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, counter)
+
+	assert.Error(t, cache.ResetCounter("missing"))
+	_, err = cache.KeyStats("k1") // статистика по ключам выключена
+	assert.Error(t, err)
+}
+
+func TestCacher_KeyStatsBounded(t *testing.T) {
+	cache := New(Config{TrackKeys: 1})
+	defer cache.Close()
+
+	cache.Set("hot", "v", 0)
+	for i := 0; i < maxTrackedKeys+10; i++ {
+		cache.Get(i) // промахи по всё новым ключам
+		if i%100 == 0 {
+			cache.Get("hot")
+		}
+	}
+
+	// Хранится статистика только недавно использованных ключей
+	assert.Len(t, cache.keyTracker.stats, maxTrackedKeys)
+	_, err := cache.KeyStats(0)
+	assert.Error(t, err)
+	stats, err := cache.KeyStats("hot")
+	require.NoError(t, err)
+	assert.NotZero(t, stats.Hits)
+}
@@ -0,0 +1,116 @@
+package cacher
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// histogramBuckets is the number of histogram buckets: four per power of two
+// nanoseconds up to the longest time.Duration, so durations are accurate to within 25%.
+const histogramBuckets = 62 * 4
+
+// histogram counts durations in logarithmic buckets. Safe for concurrent use
+// without locks, so it can record the time spent waiting for c.mu.
+type histogram struct {
+	buckets [histogramBuckets]atomic.Uint64
+	count   atomic.Uint64
+	max     atomic.Int64
+}
+
+// LatencyStats summarizes recorded durations.
+// Percentiles are upper bounds of histogram buckets, accurate to within 25%.
+type LatencyStats struct {
+	Count uint64
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// latency records operation durations and lock waits. Nil if Config.TrackLatency is off.
+type latency struct {
+	get      histogram
+	set      histogram
+	lockWait histogram
+}
+
+// since records the time elapsed since start.
+func (h *histogram) since(start time.Time) {
+	h.observe(time.Since(start))
+}
+
+// observe records a duration.
+func (h *histogram) observe(d time.Duration) {
+	h.buckets[bucketOf(d)].Add(1)
+	h.count.Add(1)
+	for {
+		old := h.max.Load()
+		if int64(d) <= old || h.max.CompareAndSwap(old, int64(d)) {
+			return
+		}
+	}
+}
+
+// stats returns the count, percentiles and maximum of the recorded durations.
+func (h *histogram) stats() LatencyStats {
+	var counts [histogramBuckets]uint64
+	var total uint64
+	for i := range h.buckets {
+		counts[i] = h.buckets[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return LatencyStats{}
+	}
+
+	maxDuration := time.Duration(h.max.Load())
+	quantile := func(q float64) time.Duration {
+		rank := uint64(q * float64(total))
+		var seen uint64
+		for i, n := range counts {
+			if seen += n; seen > rank {
+				return min(bucketLimit(i), maxDuration)
+			}
+		}
+		return maxDuration
+	}
+	return LatencyStats{
+		Count: total,
+		P50:   quantile(0.50),
+		P95:   quantile(0.95),
+		P99:   quantile(0.99),
+		Max:   maxDuration,
+	}
+}
+
+// bucketOf returns the histogram bucket for a duration: durations below 4ns
+// have their own buckets, longer ones are split by their top three bits.
+func bucketOf(d time.Duration) int {
+	n := uint64(max(d, 0))
+	if n < 4 {
+		return int(n)
+	}
+	exp := bits.Len64(n) - 1
+	return (exp-1)*4 + int(n>>(exp-2)&3)
+}
+
+// bucketLimit returns the largest duration counted in a bucket.
+func bucketLimit(bucket int) time.Duration {
+	if bucket < 4 {
+		return time.Duration(bucket)
+	}
+	exp, sub := bucket/4+1, uint64(bucket%4)
+	return time.Duration((4+sub+1)<<(exp-2) - 1)
+}
+
+// lock acquires c.mu, recording the wait if latency tracking is enabled.
+func (c *Cacher) lock() {
+	if c.latency == nil {
+		c.mu.Lock()
+		return
+	}
+	start := time.Now()
+	c.mu.Lock()
+	c.latency.lockWait.since(start)
+}
@@ -0,0 +1,94 @@
+package cacher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Buckets(t *testing.T) {
+	for _, d := range []time.Duration{0, 1, 3, 4, 7, 8, 100, 999, time.Microsecond, time.Second, time.Hour} {
+		bucket := bucketOf(d)
+		assert.LessOrEqual(t, d, bucketLimit(bucket), d)
+		if bucket > 0 {
+			assert.Greater(t, d, bucketLimit(bucket-1), d)
+		}
+		// погрешность не больше 25%
+		assert.LessOrEqual(t, float64(bucketLimit(bucket)), float64(d)*1.25+1, d)
+	}
+	assert.Equal(t, histogramBuckets-1, bucketOf(time.Duration(1<<63-1)))
+}
+
+func TestHistogram_Stats(t *testing.T) {
+	var h histogram
+	assert.Equal(t, LatencyStats{}, h.stats())
+
+	for i := 1; i <= 100; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+	stats := h.stats()
+	assert.Equal(t, uint64(100), stats.Count)
+	assert.InEpsilon(t, float64(50*time.Millisecond), float64(stats.P50), 0.25)
+	assert.InEpsilon(t, float64(95*time.Millisecond), float64(stats.P95), 0.25)
+	assert.InEpsilon(t, float64(99*time.Millisecond), float64(stats.P99), 0.25)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.LessOrEqual(t, stats.P99, stats.Max)
+}
+
+func TestCacher_TrackLatency(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+	cache.Set("k1", "v1", 0)
+	cache.Get("k1")
+	assert.Zero(t, cache.Metrics().GetLatency.Count) // выключено по умолчанию
+
+	cache = New(Config{TrackLatency: true})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cache.Set(j, j, 0)
+				cache.Get(j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	m := cache.Metrics()
+	assert.Equal(t, uint64(400), m.GetLatency.Count)
+	assert.Equal(t, uint64(400), m.SetLatency.Count)
+	assert.Equal(t, uint64(800), m.LockWait.Count)
+	assert.Positive(t, m.GetLatency.Max)
+	assert.LessOrEqual(t, m.GetLatency.P50, m.GetLatency.P99)
+}
+
+func TestCacher_TrackLatencySetters(t *testing.T) {
+	cache := New(Config{TrackLatency: true})
+	defer cache.Close()
+
+	// Все способы записи учитываются в SetLatency и LockWait
+	setters := []func(){
+		func() { cache.Set("k", 1, 0) },
+		func() { cache.SetWithDeadline("k", 1, time.Now().Add(time.Hour)) },
+		func() { cache.SetWithLimits("k", 1, time.Minute, time.Hour) },
+		func() { cache.SetWithCloner("k", 1, 0, func(v interface{}) interface{} { return v }) },
+		func() { cache.SetNegative("missing", time.Minute) },
+		func() { cache.SetWithRecompute("k", 1, time.Minute, time.Millisecond) },
+		func() { cache.SetWithDependencies("derived", 1, 0, "k") },
+		func() { cache.SetAfter("later", 1, 0, 0) },
+		func() { cache.SetIfVersion("new", 1, 0, 0) },
+		func() { cache.Update("k", 2) },
+	}
+	for i, set := range setters {
+		set()
+		m := cache.Metrics()
+		assert.Equal(t, uint64(i+1), m.SetLatency.Count, i)
+		assert.Equal(t, uint64(i+1), m.LockWait.Count, i)
+	}
+}
@@ -0,0 +1,40 @@
+package cacher
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrValueTooLarge is returned by SetE for values larger than Config.MaxValueBytes.
+	ErrValueTooLarge = errors.New("value too large")
+
+	// ErrKeyTooLong is returned by SetE for string keys longer than Config.MaxKeyLength.
+	ErrKeyTooLong = errors.New("key too long")
+)
+
+// checkLimits returns an error if a key or its stored value exceeds the size limits.
+func (c *Cacher) checkLimits(key interface{}, item cache) error {
+	if c.maxKeyLength > 0 {
+		if s, ok := key.(string); ok && len(s) > c.maxKeyLength {
+			return fmt.Errorf("%w: %d bytes, limit %d", ErrKeyTooLong, len(s), c.maxKeyLength)
+		}
+	}
+	if c.maxValueBytes > 0 {
+		if size := sizeOf(item.value); size > int64(c.maxValueBytes) {
+			return fmt.Errorf("%w: %d bytes, limit %d", ErrValueTooLarge, size, c.maxValueBytes)
+		}
+	}
+	return nil
+}
+
+// reject removes the older value of a key whose new value was rejected,
+// so it is not served in place of the value the caller meant to store.
+func (c *Cacher) reject(key interface{}, err error) {
+	c.logger.Debug("cache value rejected", "key", key, "error", err)
+	c.dropSpilled(key)
+	if _, ok := c.cache[key]; ok {
+		c.removeKey(key)
+		c.checkWatermarks()
+	}
+}
@@ -0,0 +1,67 @@
+package cacher
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_SetE(t *testing.T) {
+	cache := New(Config{MaxValueBytes: 1024, MaxKeyLength: 8})
+	defer cache.Close()
+
+	require.NoError(t, setE(cache, "small", "value", time.Minute))
+
+	_, err := cache.SetE("big", strings.Repeat("x", 2048), time.Minute)
+	assert.ErrorIs(t, err, ErrValueTooLarge)
+	_, err = cache.Get("big")
+	assert.Error(t, err)
+
+	_, err = cache.SetE("very-long-key", "value", time.Minute)
+	assert.ErrorIs(t, err, ErrKeyTooLong)
+
+	// Ключи других типов не ограничиваются по длине
+	require.NoError(t, setE(cache, 123456789, "value", time.Minute))
+}
+
+// setE calls SetE and returns only its error.
+func setE(cache *Cacher, key, value interface{}, ttl time.Duration) error {
+	_, err := cache.SetE(key, value, ttl)
+	return err
+}
+
+func TestCacher_SetOversized(t *testing.T) {
+	cache := New(Config{MaxValueBytes: 1024})
+	defer cache.Close()
+
+	cache.Set("k", "old", time.Minute)
+
+	// Слишком большое значение не сохраняется, и старое значение удаляется
+	cache.Set("k", make([]byte, 4096), time.Minute)
+	_, err := cache.Get("k")
+	assert.Error(t, err)
+	assert.Equal(t, 0, cache.Metrics().Items)
+
+	cache.Set("k", "old", time.Minute)
+	err = cache.Update("k", make([]byte, 4096))
+	assert.ErrorIs(t, err, ErrValueTooLarge)
+	value, err := cache.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "old", value) // Update с ошибкой оставляет старое значение
+
+	_, version, err := cache.GetWithVersion("k")
+	require.NoError(t, err)
+	err = cache.SetIfVersion("k", make([]byte, 4096), time.Minute, version)
+	assert.ErrorIs(t, err, ErrValueTooLarge)
+}
+
+func TestCacher_MaxValueBytesCompressed(t *testing.T) {
+	cache := New(Config{MaxValueBytes: 1024, CompressionThreshold: 512})
+	defer cache.Close()
+
+	// Ограничение применяется к сжатому значению
+	require.NoError(t, setE(cache, "k", strings.Repeat("a", 8192), time.Minute))
+}
@@ -0,0 +1,50 @@
+package cacher
+
+import "time"
+
+// Memoize wraps fn so its results are cached in c with the given TTL.
+// Concurrent calls with the same key share a single call to fn.
+// Errors returned by fn are not cached. Values close to expiration may be
+// recomputed early, with a probability depending on how long fn took (see GetWithRefresh).
+//
+// Keys are stored in c as is, so functions memoized on the same cache
+// must not share a key space.
+func Memoize[K comparable, V any](c *Cacher, ttl time.Duration, fn func(K) (V, error)) func(K) (V, error) {
+	return func(key K) (V, error) {
+		if value, ok := getTyped[V](c, key); ok {
+			return value, nil
+		}
+
+		value, err := c.do(key, func() (interface{}, error) {
+			if value, ok := getTyped[V](c, key); ok {
+				return value, nil
+			}
+			start := time.Now()
+			value, err := fn(key)
+			if err != nil {
+				return nil, err
+			}
+			c.SetWithRecompute(key, value, ttl, time.Since(start))
+			return value, nil
+		})
+		if err != nil {
+			var zero V
+			return zero, err
+		}
+
+		typed, _ := value.(V)
+		return typed, nil
+	}
+}
+
+// getTyped returns the cached value for key if it is present, of type V
+// and not due for an early refresh.
+func getTyped[V any](c *Cacher, key interface{}) (V, bool) {
+	value, refresh, err := c.GetWithRefresh(key)
+	if err != nil || refresh {
+		var zero V
+		return zero, false
+	}
+	typed, ok := value.(V)
+	return typed, ok
+}
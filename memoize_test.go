@@ -0,0 +1,76 @@
+package cacher
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoize(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	var calls int32
+	square := Memoize(cache, 5*time.Second, func(n int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return n * n, nil
+	})
+
+	got, err := square(3)
+	require.NoError(t, err)
+	assert.Equal(t, 9, got)
+
+	got, err = square(3)
+	require.NoError(t, err)
+	assert.Equal(t, 9, got)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestMemoize_Concurrent(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	var calls int32
+	slow := Memoize(cache, 5*time.Second, func(key string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		return "value:" + key, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := slow("k1")
+			assert.NoError(t, err)
+			assert.Equal(t, "value:k1", got)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestMemoize_ErrorNotCached(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	var calls int32
+	failing := Memoize(cache, 5*time.Second, func(key string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "", errors.New("boom")
+	})
+
+	_, err := failing("k1")
+	assert.Error(t, err)
+	_, err = failing("k1")
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Empty(t, cache.GetAll())
+}
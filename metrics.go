@@ -0,0 +1,132 @@
+package cacher
+
+import (
+	"expvar"
+	"fmt"
+	"time"
+)
+
+// counters holds operation counters. Guarded by Cacher.mu.
+type counters struct {
+	hits        uint64 // Successful Get calls
+	misses      uint64 // Get calls for missing or expired keys
+	evictions   uint64 // Items evicted by capacity
+	expirations uint64 // Items removed because their TTL expired
+	purged      uint64 // Expired items removed to make room on Set
+	allocated   uint64 // List elements and expiry entries allocated
+	reused      uint64 // List elements and expiry entries taken from the pools
+}
+
+// Metrics is a snapshot of cache counters.
+type Metrics struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+	Items       int
+	Capacity    int // 0 if unlimited
+
+	// Allocated and Reused count the internal list elements and expiry index
+	// entries that were newly allocated or reused from a pool.
+	Allocated uint64
+	Reused    uint64
+
+	// DroppedCallbacks counts callbacks dropped because their queue was full,
+	// and CallbackPanics callbacks that panicked.
+	DroppedCallbacks uint64
+	CallbackPanics   uint64
+
+	// WouldHaveHit counts misses on recently evicted keys. See Cacher.WouldHaveHit.
+	WouldHaveHit uint64
+
+	// GetLatency and SetLatency are the durations of Get calls and of Set, Update
+	// and the other setters, and LockWait the time they waited for the lock.
+	// Empty unless Config.TrackLatency is set.
+	GetLatency LatencyStats
+	SetLatency LatencyStats
+	LockWait   LatencyStats
+
+	// Snapshots and SnapshotErrors count the snapshots saved to Config.SnapshotPath
+	// and failed attempts. LastSnapshotDuration and LastSnapshotBytes describe
+	// the last saved snapshot.
+	Snapshots            uint64
+	SnapshotErrors       uint64
+	LastSnapshotDuration time.Duration
+	LastSnapshotBytes    int64
+}
+
+// HitRatio returns the share of Get calls that found a value.
+func (m Metrics) HitRatio() float64 {
+	total := m.Hits + m.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(m.Hits) / float64(total)
+}
+
+// Metrics returns a snapshot of the cache counters.
+func (c *Cacher) Metrics() Metrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	m := Metrics{
+		Hits:        c.counters.hits,
+		Misses:      c.counters.misses,
+		Evictions:   c.counters.evictions,
+		Expirations: c.counters.expirations,
+		Items:       len(c.cache),
+		Capacity:    c.capacity,
+		Allocated:   c.counters.allocated,
+		Reused:      c.counters.reused,
+
+		DroppedCallbacks: c.dispatcher.dropped.Load(),
+		CallbackPanics:   c.dispatcher.panics.Load(),
+
+		Snapshots:            c.snapshots.count,
+		SnapshotErrors:       c.snapshots.errors,
+		LastSnapshotDuration: c.snapshots.duration,
+		LastSnapshotBytes:    c.snapshots.bytes,
+	}
+	if c.ghosts != nil {
+		m.WouldHaveHit = c.ghosts.hits
+	}
+	if c.latency != nil {
+		m.GetLatency = c.latency.get.stats()
+		m.SetLatency = c.latency.set.stats()
+		m.LockWait = c.latency.lockWait.stats()
+	}
+	return m
+}
+
+// PublishExpvar publishes the cache metrics with expvar as
+// cacher.<name>.hits, .misses, .evictions, .expirations, .items, .capacity, .hit_ratio,
+// .allocated, .reused and .would_have_hit.
+// Returns an error if a variable with the same name is already published.
+func (c *Cacher) PublishExpvar(name string) error {
+	prefix := "cacher." + name + "."
+	vars := map[string]func(Metrics) interface{}{
+		"hits":        func(m Metrics) interface{} { return m.Hits },
+		"misses":      func(m Metrics) interface{} { return m.Misses },
+		"evictions":   func(m Metrics) interface{} { return m.Evictions },
+		"expirations": func(m Metrics) interface{} { return m.Expirations },
+		"items":       func(m Metrics) interface{} { return m.Items },
+		"capacity":    func(m Metrics) interface{} { return m.Capacity },
+		"hit_ratio":   func(m Metrics) interface{} { return m.HitRatio() },
+		"allocated":   func(m Metrics) interface{} { return m.Allocated },
+		"reused":      func(m Metrics) interface{} { return m.Reused },
+
+		"would_have_hit": func(m Metrics) interface{} { return m.WouldHaveHit },
+	}
+
+	for suffix := range vars {
+		if expvar.Get(prefix+suffix) != nil {
+			return fmt.Errorf("expvar already published: %s", prefix+suffix)
+		}
+	}
+	for suffix, get := range vars {
+		expvar.Publish(prefix+suffix, expvar.Func(func() interface{} {
+			return get(c.Metrics())
+		}))
+	}
+	return nil
+}
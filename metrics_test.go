@@ -0,0 +1,51 @@
+package cacher
+
+import (
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Metrics(t *testing.T) {
+	cfg := Config{Capacity: 2}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 10*time.Millisecond)
+	cache.Get("k1")
+	cache.Get("missing")
+	time.Sleep(20 * time.Millisecond)
+	cache.Get("k2")                      // истёк
+	cache.Set("k3", "v3", 5*time.Second) // без вытеснения
+	cache.Set("k4", "v4", 5*time.Second) // вытесняется k1
+
+	m := cache.Metrics()
+	assert.Equal(t, uint64(1), m.Hits)
+	assert.Equal(t, uint64(2), m.Misses)
+	assert.Equal(t, uint64(1), m.Evictions)
+	assert.Equal(t, uint64(1), m.Expirations)
+	assert.Equal(t, 2, m.Items)
+	assert.Equal(t, 2, m.Capacity)
+	assert.InDelta(t, 1.0/3, m.HitRatio(), 0.001)
+
+	assert.Contains(t, cache.Stats(), "Hits: 1")
+}
+
+func TestCacher_PublishExpvar(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	require.NoError(t, cache.PublishExpvar("test"))
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Get("k1")
+
+	assert.Equal(t, "1", expvar.Get("cacher.test.hits").String())
+	assert.Equal(t, "1", expvar.Get("cacher.test.items").String())
+	assert.Equal(t, "10", expvar.Get("cacher.test.capacity").String())
+
+	assert.Error(t, cache.PublishExpvar("test"))
+}
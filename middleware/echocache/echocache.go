@@ -0,0 +1,68 @@
+// Package echocache is Echo middleware caching handler responses in a *cacher.Cacher:
+//
+//	e.GET("/products/:id", getProduct, echocache.Middleware(cache, middleware.Options{TTL: time.Minute}))
+//
+// Responses are keyed by the route pattern, path parameters and query string.
+// See package middleware for what is cached.
+package echocache
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware returns middleware serving cached responses and caching the responses
+// of the handlers it wraps. Responses of handlers returning an error are not cached.
+func Middleware(cache *cacher.Cacher, opts middleware.Options) echo.MiddlewareFunc {
+	h := middleware.New(cache, opts)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			if h.Skip(c.Request()) {
+				h.SetStatus(res.Header(), middleware.StatusBypass)
+				return next(c)
+			}
+
+			key := middleware.NewKey(c.Path(), c.ParamNames(), c.ParamValues(), c.QueryParams())
+			if h.Serve(res, c.Request(), key) {
+				return nil
+			}
+
+			h.SetStatus(res.Header(), middleware.StatusMiss)
+			rec := &recorder{ResponseWriter: res.Writer}
+			res.Writer = rec
+			err := next(c)
+			res.Writer = rec.ResponseWriter
+			if err != nil {
+				return err
+			}
+
+			h.Set(key, middleware.Response{
+				Status: res.Status,
+				Header: res.Header(),
+				Body:   rec.body.Bytes(),
+			})
+			return nil
+		}
+	}
+}
+
+// recorder copies the response body while writing it to the client.
+type recorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// Unwrap lets http.ResponseController reach the client connection.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,71 @@
+package echocache
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+
+	calls := 0
+	e := echo.New()
+	e.Use(Middleware(cache, middleware.Options{TTL: time.Minute, StatusHeader: "X-Cache"}))
+	e.GET("/users/:id", func(c echo.Context) error {
+		calls++
+		return c.String(http.StatusOK, "user "+c.Param("id"))
+	})
+	e.GET("/broken", func(c echo.Context) error {
+		calls++
+		return errors.New("broken")
+	})
+	e.POST("/users/:id", func(c echo.Context) error {
+		calls++
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := do(http.MethodGet, "/users/1")
+	assert.Equal(t, "user 1", w.Body.String())
+	assert.Equal(t, middleware.StatusMiss, w.Header().Get("X-Cache"))
+
+	w = do(http.MethodGet, "/users/1")
+	assert.Equal(t, "user 1", w.Body.String())
+	assert.Equal(t, middleware.StatusHit, w.Header().Get("X-Cache"))
+	assert.Equal(t, 1, calls)
+
+	// Условный запрос с ETag из кэша
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, "user 2", do(http.MethodGet, "/users/2").Body.String())
+	assert.Equal(t, 2, calls)
+
+	// POST не кэшируется
+	w = do(http.MethodPost, "/users/1")
+	assert.Equal(t, middleware.StatusBypass, w.Header().Get("X-Cache"))
+	assert.Equal(t, 3, calls)
+
+	// Ответ обработчика с ошибкой не кэшируется
+	assert.Equal(t, http.StatusInternalServerError, do(http.MethodGet, "/broken").Code)
+	do(http.MethodGet, "/broken")
+	assert.Equal(t, 5, calls)
+}
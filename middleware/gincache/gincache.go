@@ -0,0 +1,72 @@
+// Package gincache is Gin middleware caching handler responses in a *cacher.Cacher:
+//
+//	r.GET("/products/:id", gincache.Middleware(cache, middleware.Options{TTL: time.Minute}), getProduct)
+//
+// Responses are keyed by the route pattern, path parameters and query string.
+// See package middleware for what is cached.
+package gincache
+
+import (
+	"bytes"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware returns a handler serving cached responses and caching the responses
+// of the handlers after it.
+func Middleware(cache *cacher.Cacher, opts middleware.Options) gin.HandlerFunc {
+	h := middleware.New(cache, opts)
+	return func(c *gin.Context) {
+		if h.Skip(c.Request) {
+			h.SetStatus(c.Writer.Header(), middleware.StatusBypass)
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		names := make([]string, len(c.Params))
+		values := make([]string, len(c.Params))
+		for i, param := range c.Params {
+			names[i], values[i] = param.Key, param.Value
+		}
+		key := middleware.NewKey(route, names, values, c.Request.URL.Query())
+
+		if h.Serve(c.Writer, c.Request, key) {
+			c.Abort()
+			return
+		}
+
+		h.SetStatus(c.Writer.Header(), middleware.StatusMiss)
+		rec := &recorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+		c.Next()
+		c.Writer = rec.ResponseWriter
+
+		h.Set(key, middleware.Response{
+			Status: rec.Status(),
+			Header: rec.Header(),
+			Body:   rec.body.Bytes(),
+		})
+	}
+}
+
+// recorder copies the response body while writing it to the client.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,74 @@
+package gincache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+
+	calls := 0
+	r := gin.New()
+	r.Use(Middleware(cache, middleware.Options{
+		TTL:    time.Minute,
+		Bypass: func(r *http.Request) bool { return r.URL.Query().Get("fresh") != "" },
+	}))
+	r.GET("/users/:id", func(c *gin.Context) {
+		calls++
+		c.Header("X-Calls", "set")
+		c.String(http.StatusOK, "user %s", c.Param("id"))
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		calls++
+		c.String(http.StatusNotFound, "no")
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/users/1?a=1")
+	assert.Equal(t, "user 1", w.Body.String())
+	assert.Equal(t, middleware.StatusMiss, w.Header().Get(middleware.DefaultStatusHeader))
+
+	w = get("/users/1?a=1")
+	assert.Equal(t, "user 1", w.Body.String())
+	assert.Equal(t, "set", w.Header().Get("X-Calls"))
+	assert.Equal(t, middleware.StatusHit, w.Header().Get(middleware.DefaultStatusHeader))
+	assert.Equal(t, 1, calls)
+
+	// Условный запрос с ETag из кэша
+	req := httptest.NewRequest(http.MethodGet, "/users/1?a=1", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// Другой параметр маршрута или запроса — другой ключ
+	assert.Equal(t, "user 2", get("/users/2?a=1").Body.String())
+	get("/users/1?a=2")
+	assert.Equal(t, 3, calls)
+
+	w = get("/users/1?a=1&fresh=1")
+	assert.Equal(t, middleware.StatusBypass, w.Header().Get(middleware.DefaultStatusHeader))
+	assert.Equal(t, 4, calls)
+
+	// Ответы с ошибкой не кэшируются
+	get("/missing")
+	assert.Equal(t, http.StatusNotFound, get("/missing").Code)
+	assert.Equal(t, 6, calls)
+}
@@ -0,0 +1,197 @@
+// Package middleware caches HTTP handler responses in a *cacher.Cacher. The Gin and Echo
+// middleware in its subpackages gincache and echocache are built on it.
+//
+// Only GET requests are cached, keyed by route, path parameters and query string.
+// Responses are stored if their status is cacheable (by default 200) and they
+// do not set cookies. A status header reports whether a response was a HIT,
+// a MISS or a BYPASS of the cache.
+//
+// Cached responses get ETag and Last-Modified headers from the cache, unless
+// the handler set an ETag, and requests with a matching If-None-Match header
+// are answered with 304 Not Modified.
+//
+// It is a separate module, so the cacher module does not depend on web frameworks.
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+// DefaultStatusHeader is the response header reporting the cache status if Options.StatusHeader is empty.
+const DefaultStatusHeader = "X-Cache-Status"
+
+// Cache statuses reported in the status header
+const (
+	StatusHit    = "HIT"
+	StatusMiss   = "MISS"
+	StatusBypass = "BYPASS"
+)
+
+// Options configures the response cache.
+type Options struct {
+	// TTL is how long a response stays cached after it is stored, however often
+	// it is served. 0 means no expiration.
+	TTL time.Duration
+
+	// Bypass reports whether a request must neither be served from nor stored in the cache,
+	// e.g. for authenticated users.
+	Bypass func(*http.Request) bool
+
+	// Cacheable reports whether a response with the status may be stored.
+	// If nil, only 200 responses are stored.
+	Cacheable func(status int) bool
+
+	// StatusHeader is the header reporting HIT, MISS or BYPASS.
+	// If empty, defaults to DefaultStatusHeader; "-" disables it.
+	StatusHeader string
+}
+
+// Key identifies a cached response. Its type keeps responses apart
+// from other keys in a shared cache.
+type Key struct {
+	Route  string
+	Params string
+	Query  string
+}
+
+// NewKey builds a key from a route pattern, its parameter names and values in
+// route order, and the query. Query parameters are sorted, so their order does not matter.
+func NewKey(route string, names, values []string, query url.Values) Key {
+	var params strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			params.WriteByte('&')
+		}
+		params.WriteString(url.QueryEscape(name))
+		params.WriteByte('=')
+		if i < len(values) {
+			params.WriteString(url.QueryEscape(values[i]))
+		}
+	}
+	return Key{Route: route, Params: params.String(), Query: query.Encode()}
+}
+
+// Response is a cached handler response.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Handler stores and replays responses. The framework middleware call it around their handlers.
+type Handler struct {
+	cache     *cacher.Cacher
+	ttl       time.Duration
+	bypass    func(*http.Request) bool
+	cacheable func(int) bool
+	header    string
+}
+
+// New creates a Handler storing responses in cache.
+func New(cache *cacher.Cacher, opts Options) *Handler {
+	if opts.Cacheable == nil {
+		opts.Cacheable = func(status int) bool { return status == http.StatusOK }
+	}
+	if opts.StatusHeader == "" {
+		opts.StatusHeader = DefaultStatusHeader
+	}
+	return &Handler{
+		cache:     cache,
+		ttl:       opts.TTL,
+		bypass:    opts.Bypass,
+		cacheable: opts.Cacheable,
+		header:    opts.StatusHeader,
+	}
+}
+
+// Skip reports whether a request bypasses the cache: it is not a GET request
+// or the Bypass option says so.
+func (h *Handler) Skip(r *http.Request) bool {
+	return r.Method != http.MethodGet || (h.bypass != nil && h.bypass(r))
+}
+
+// Get returns the cached response for key.
+func (h *Handler) Get(key Key) (Response, bool) {
+	value, err := h.cache.Get(key)
+	if err != nil {
+		return Response{}, false
+	}
+	resp, ok := value.(Response)
+	return resp, ok
+}
+
+// Serve writes the cached response for key, or 304 Not Modified if the request's
+// If-None-Match header matches its ETag, and sets the status header to HIT.
+// The ETag is the one the handler set, if any. Reports whether a response was written.
+func (h *Handler) Serve(w http.ResponseWriter, r *http.Request, key Key) bool {
+	value, v, err := h.cache.GetConditional(key, "")
+	if err != nil {
+		return false
+	}
+	resp, ok := value.(Response)
+	if !ok {
+		return false
+	}
+
+	h.SetStatus(w.Header(), StatusHit)
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		etag = v.ETag
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && matchETag(match, etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	if resp.Header.Get("ETag") == "" {
+		w.Header().Set("ETag", v.ETag)
+		w.Header().Set("Last-Modified", v.Modified.UTC().Format(http.TimeFormat))
+	}
+	Write(w, resp)
+	return true
+}
+
+// Set stores a response if its status is cacheable and it sets no cookies.
+// The status header is not stored.
+func (h *Handler) Set(key Key, resp Response) {
+	if !h.cacheable(resp.Status) || resp.Header.Get("Set-Cookie") != "" {
+		return
+	}
+	resp.Header = resp.Header.Clone()
+	resp.Header.Del(h.header)
+	h.cache.SetWithLimits(key, resp, 0, h.ttl) // Serving a response must not extend its lifetime
+}
+
+// SetStatus sets the status header, unless it is disabled.
+func (h *Handler) SetStatus(header http.Header, status string) {
+	if h.header != "-" {
+		header.Set(h.header, status)
+	}
+}
+
+// matchETag reports whether an If-None-Match list matches an ETag.
+// Weak comparison is used, as for GET requests.
+func matchETag(list, etag string) bool {
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Write replays a cached response.
+func Write(w http.ResponseWriter, resp Response) {
+	header := w.Header()
+	for name, values := range resp.Header {
+		header[name] = values
+	}
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKey(t *testing.T) {
+	a := NewKey("/users/:id", []string{"id"}, []string{"1"}, url.Values{"b": {"2"}, "a": {"1"}})
+	b := NewKey("/users/:id", []string{"id"}, []string{"1"}, url.Values{"a": {"1"}, "b": {"2"}})
+	assert.Equal(t, a, b) // порядок параметров запроса не важен
+
+	assert.NotEqual(t, a, NewKey("/users/:id", []string{"id"}, []string{"2"}, url.Values{"a": {"1"}, "b": {"2"}}))
+	// Разделители в значениях экранируются
+	assert.NotEqual(t,
+		NewKey("/:a/:b", []string{"a", "b"}, []string{"x&b=y", ""}, nil),
+		NewKey("/:a/:b", []string{"a", "b"}, []string{"x", "y"}, nil))
+}
+
+func TestHandler(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	h := New(cache, Options{Bypass: func(r *http.Request) bool { return r.Header.Get("Authorization") != "" }})
+	key := NewKey("/", nil, nil, nil)
+
+	header := http.Header{"Content-Type": {"text/plain"}}
+	h.SetStatus(header, StatusMiss)
+	h.Set(key, Response{Status: http.StatusOK, Header: header, Body: []byte("hello")})
+
+	resp, ok := h.Get(key)
+	assert.True(t, ok)
+	assert.Empty(t, resp.Header.Get(DefaultStatusHeader)) // статус кэша не сохраняется
+
+	w := httptest.NewRecorder()
+	Write(w, resp)
+	assert.Equal(t, "hello", w.Body.String())
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+
+	// Ошибки и ответы с куками не кэшируются
+	other := NewKey("/other", nil, nil, nil)
+	h.Set(other, Response{Status: http.StatusInternalServerError, Header: http.Header{}})
+	h.Set(other, Response{Status: http.StatusOK, Header: http.Header{"Set-Cookie": {"a=b"}}})
+	_, ok = h.Get(other)
+	assert.False(t, ok)
+
+	get := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.False(t, h.Skip(get))
+	assert.True(t, h.Skip(httptest.NewRequest(http.MethodPost, "/", nil)))
+	get.Header.Set("Authorization", "Bearer x")
+	assert.True(t, h.Skip(get))
+}
+
+func TestHandler_Serve(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	h := New(cache, Options{})
+	key := NewKey("/", nil, nil, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.False(t, h.Serve(httptest.NewRecorder(), r, key))
+
+	h.Set(key, Response{Status: http.StatusOK, Header: http.Header{}, Body: []byte("hello")})
+	w := httptest.NewRecorder()
+	assert.True(t, h.Serve(w, r, key))
+	assert.Equal(t, "hello", w.Body.String())
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+
+	// Совпадающий If-None-Match даёт 304 без тела
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	assert.True(t, h.Serve(w, r, key))
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, StatusHit, w.Header().Get(DefaultStatusHeader))
+
+	// ETag обработчика не заменяется
+	h.Set(key, Response{Status: http.StatusOK, Header: http.Header{"Etag": {`"v1"`}}, Body: []byte("hello")})
+	w = httptest.NewRecorder()
+	assert.True(t, h.Serve(w, httptest.NewRequest(http.MethodGet, "/", nil), key))
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+
+	// If-None-Match сравнивается с ETag обработчика
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", `W/"v1"`)
+	w = httptest.NewRecorder()
+	assert.True(t, h.Serve(w, r, key))
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+}
+
+func TestHandler_TTLIsAbsolute(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+	h := New(cache, Options{TTL: 150 * time.Millisecond})
+	key := NewKey("/", nil, nil, nil)
+	h.Set(key, Response{Status: http.StatusOK, Header: http.Header{}, Body: []byte("hello")})
+
+	// Частые попадания не продлевают срок жизни ответа
+	for i := 0; i < 2; i++ {
+		time.Sleep(50 * time.Millisecond)
+		_, ok := h.Get(key)
+		assert.True(t, ok)
+	}
+	time.Sleep(100 * time.Millisecond)
+	_, ok := h.Get(key)
+	assert.False(t, ok)
+}
@@ -0,0 +1,26 @@
+package cacher
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNegativeCached is returned by Get for keys stored with SetNegative.
+var ErrNegativeCached = errors.New("key is cached as missing")
+
+// SetNegative stores a marker that the key is known to be missing, so repeated
+// lookups of nonexistent records do not reach the origin. Get returns ErrNegativeCached
+// for the key until the TTL expires or a value is set.
+func (c *Cacher) SetNegative(key interface{}, ttl time.Duration) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(nil, ttl, 0)
+	item.negative = true
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.store(key, item)
+}
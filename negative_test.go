@@ -0,0 +1,37 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_SetNegative(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.SetNegative("user:42", 20*time.Millisecond)
+
+	_, err := cache.Get("user:42")
+	assert.ErrorIs(t, err, ErrNegativeCached)
+	assert.Empty(t, cache.GetAll())
+
+	info, err := cache.Entry("user:42")
+	require.NoError(t, err)
+	assert.True(t, info.Negative)
+
+	// Обычная запись заменяет отрицательную
+	cache.Set("user:42", "alice", 0)
+	got, err := cache.Get("user:42")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got)
+
+	// После истечения TTL ключ просто отсутствует
+	cache.SetNegative("user:43", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, err = cache.Get("user:43")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNegativeCached)
+}
@@ -0,0 +1,198 @@
+// Package otelcacher instruments a *cacher.Cacher with OpenTelemetry.
+//
+// Every operation records a span, Get calls record their latency and hit/miss outcome,
+// and the cache counters (evictions, expirations, items) are exported as observable metrics.
+package otelcacher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/danRulev/cacher/otelcacher"
+
+// Attribute keys
+const (
+	NameKey = attribute.Key("cache.name")
+	HitKey  = attribute.Key("cache.hit")
+	KeyKey  = attribute.Key("cache.key")
+)
+
+// config holds wrapper options.
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	name           string
+	recordKeys     bool
+}
+
+// Option configures the wrapper.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. Defaults to the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider sets the meter provider. Defaults to the global one.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+// WithName sets the cache.name attribute added to all spans and metrics.
+func WithName(name string) Option {
+	return func(c *config) { c.name = name }
+}
+
+// WithKeys records the cache key as a span attribute.
+// Disabled by default since keys may contain sensitive data.
+func WithKeys() Option {
+	return func(c *config) { c.recordKeys = true }
+}
+
+// Cacher wraps a cache and records telemetry for each operation.
+type Cacher struct {
+	cache      *cacher.Cacher
+	tracer     trace.Tracer
+	attrs      []attribute.KeyValue
+	recordKeys bool
+
+	getDuration  metric.Float64Histogram
+	requests     metric.Int64Counter
+	registration metric.Registration
+}
+
+// New wraps the cache. Close must be called to stop exporting the observable metrics.
+func New(cache *cacher.Cacher, opts ...Option) (*Cacher, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	c := &Cacher{
+		cache:      cache,
+		tracer:     cfg.tracerProvider.Tracer(instrumentationName),
+		recordKeys: cfg.recordKeys,
+	}
+	if cfg.name != "" {
+		c.attrs = []attribute.KeyValue{NameKey.String(cfg.name)}
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	var err error
+	c.getDuration, err = meter.Float64Histogram("cacher.get.duration",
+		metric.WithDescription("Duration of Get calls"), metric.WithUnit("ms"))
+	if err != nil {
+		return nil, err
+	}
+	c.requests, err = meter.Int64Counter("cacher.requests",
+		metric.WithDescription("Get calls by outcome"))
+	if err != nil {
+		return nil, err
+	}
+
+	evictions, err := meter.Int64ObservableCounter("cacher.evictions",
+		metric.WithDescription("Items evicted by capacity"))
+	if err != nil {
+		return nil, err
+	}
+	expirations, err := meter.Int64ObservableCounter("cacher.expirations",
+		metric.WithDescription("Items removed because their TTL expired"))
+	if err != nil {
+		return nil, err
+	}
+	items, err := meter.Int64ObservableGauge("cacher.items",
+		metric.WithDescription("Items in the cache"))
+	if err != nil {
+		return nil, err
+	}
+
+	c.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		m := cache.Metrics()
+		set := metric.WithAttributes(c.attrs...)
+		o.ObserveInt64(evictions, int64(m.Evictions), set)
+		o.ObserveInt64(expirations, int64(m.Expirations), set)
+		o.ObserveInt64(items, int64(m.Items), set)
+		return nil
+	}, evictions, expirations, items)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Unwrap returns the underlying cache.
+func (c *Cacher) Unwrap() *cacher.Cacher {
+	return c.cache
+}
+
+// Get retrieves a value and records its latency and hit/miss outcome.
+func (c *Cacher) Get(ctx context.Context, key interface{}) (interface{}, error) {
+	ctx, span := c.start(ctx, "cacher.Get", key)
+	defer span.End()
+
+	start := time.Now()
+	value, err := c.cache.Get(key)
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	hit := err == nil
+	span.SetAttributes(HitKey.Bool(hit))
+	attrs := metric.WithAttributes(append(c.attrs, HitKey.Bool(hit))...)
+	c.getDuration.Record(ctx, elapsed, attrs)
+	c.requests.Add(ctx, 1, attrs)
+
+	return value, err
+}
+
+// Set stores a value.
+func (c *Cacher) Set(ctx context.Context, key, value interface{}, ttl time.Duration) {
+	_, span := c.start(ctx, "cacher.Set", key)
+	defer span.End()
+
+	c.cache.Set(key, value, ttl)
+}
+
+// Delete removes a key.
+func (c *Cacher) Delete(ctx context.Context, key interface{}) error {
+	_, span := c.start(ctx, "cacher.Delete", key)
+	defer span.End()
+
+	err := c.cache.Delete(key)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// Clear removes all items.
+func (c *Cacher) Clear(ctx context.Context) {
+	_, span := c.start(ctx, "cacher.Clear", nil)
+	defer span.End()
+
+	c.cache.Clear()
+}
+
+// Close stops exporting the observable metrics. The underlying cache is not closed.
+func (c *Cacher) Close() error {
+	return c.registration.Unregister()
+}
+
+// start begins a span for an operation.
+func (c *Cacher) start(ctx context.Context, name string, key interface{}) (context.Context, trace.Span) {
+	attrs := c.attrs
+	if c.recordKeys && key != nil {
+		attrs = append(attrs[:len(attrs):len(attrs)], KeyKey.String(fmt.Sprint(key)))
+	}
+	return c.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,69 @@
+package otelcacher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCacher_Telemetry(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	cache := cacher.New(cacher.Config{Capacity: 1})
+	defer cache.Close()
+
+	c, err := New(cache,
+		WithName("test"),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.Set(ctx, "k1", "v1", time.Minute)
+	_, err = c.Get(ctx, "k1")
+	require.NoError(t, err)
+	_, err = c.Get(ctx, "missing")
+	assert.Error(t, err)
+	c.Set(ctx, "k2", "v2", time.Minute) // вытесняет k1
+
+	ended := spans.Ended()
+	require.Len(t, ended, 4)
+	assert.Equal(t, "cacher.Get", ended[1].Name())
+	assert.Contains(t, ended[1].Attributes(), HitKey.Bool(true))
+	assert.Contains(t, ended[2].Attributes(), HitKey.Bool(false))
+	assert.Contains(t, ended[0].Attributes(), NameKey.String("test"))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	found := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = m.Data
+		}
+	}
+
+	requests := found["cacher.requests"].(metricdata.Sum[int64])
+	for _, dp := range requests.DataPoints {
+		hit, _ := dp.Attributes.Value(HitKey)
+		assert.Equal(t, int64(1), dp.Value, "hit=%v", hit.AsBool())
+	}
+	evictions := found["cacher.evictions"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(1), evictions.DataPoints[0].Value)
+	name, _ := evictions.DataPoints[0].Attributes.Value(NameKey)
+	assert.Equal(t, attribute.StringValue("test"), name)
+	assert.Contains(t, found, "cacher.get.duration")
+	assert.Contains(t, found, "cacher.items")
+}
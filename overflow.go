@@ -0,0 +1,351 @@
+package cacher
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Backend is a secondary store for cached items.
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	// Get returns the value and TTL stored for key.
+	// ok is false if the key is not present or has expired.
+	Get(key interface{}) (value interface{}, ttl time.Duration, ok bool, err error)
+
+	// Set stores a value with a TTL. A TTL of 0 means no expiration.
+	Set(key, value interface{}, ttl time.Duration) error
+
+	// Delete removes a key. Deleting a missing key is not an error.
+	Delete(key interface{}) error
+}
+
+// KeyLister is implemented by Backends that keep items across restarts.
+// New loads their keys, so Get restores items the overflow store held before a restart.
+type KeyLister interface {
+	Keys() ([]interface{}, error)
+}
+
+// spilledItem is the value written to the overflow store. It keeps the expiration
+// and priority of the item, so restoring it rebuilds the item as it was.
+type spilledItem struct {
+	Value      interface{}
+	TTL        time.Duration // Sliding TTL
+	Deadline   time.Time     // Absolute expiration time, zero if none
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	Priority   int
+}
+
+func init() {
+	gob.Register(spilledItem{})
+}
+
+// spilledEntry is a key held by the overflow store.
+type spilledEntry struct {
+	pending *spilledItem  // Not written to the overflow store yet
+	ttl     time.Duration // Lifetime to write it with
+	cloner  Cloner        // Per-item cloner, kept in memory
+}
+
+// spillOp is a queued overflow store write. A nil entry deletes the key.
+type spillOp struct {
+	key   interface{}
+	entry *spilledEntry
+}
+
+// loadSpilled remembers the keys held by a persistent overflow store.
+func (c *Cacher) loadSpilled() {
+	lister, ok := c.overflow.(KeyLister)
+	if !ok {
+		return
+	}
+	keys, err := lister.Keys()
+	if err != nil {
+		c.logger.Warn("cache overflow key listing failed", "error", err)
+		return
+	}
+	for _, key := range keys {
+		c.spilled[key] = &spilledEntry{}
+	}
+}
+
+// spill queues an evicted item for the overflow store. It is written by runSpills,
+// so the overflow store is never called with c.mu held; until then it is restored
+// from memory. Expired, negative and dependent items are discarded.
+func (c *Cacher) spill(key interface{}, item cache) {
+	if c.overflow == nil || item.negative || c.checkExpiration(item) != nil {
+		return
+	}
+	if _, ok := c.deps.links[key]; ok {
+		return // Its dependencies could not be tracked in the overflow store
+	}
+	var ttl time.Duration
+	if expiresAt := c.expiresAt(item); !expiresAt.IsZero() {
+		ttl = max(expiresAt.Sub(c.now()), 1)
+	}
+	entry := &spilledEntry{
+		pending: &spilledItem{
+			Value:      c.load(item),
+			TTL:        item.ttl,
+			Deadline:   item.deadline,
+			CreatedAt:  item.createdAt,
+			LastUsedAt: item.lastUsedAt,
+			Priority:   item.priority,
+		},
+		ttl:    ttl,
+		cloner: item.cloner,
+	}
+	c.spilled[key] = entry
+	c.queueSpill(spillOp{key: key, entry: entry})
+}
+
+// queueSpill queues an overflow store write and wakes runSpills.
+func (c *Cacher) queueSpill(op spillOp) {
+	c.spillQueue = append(c.spillQueue, op)
+	select {
+	case c.spillWake <- struct{}{}:
+	default:
+	}
+}
+
+// runSpills applies queued overflow store writes in order until the cache is closed.
+func (c *Cacher) runSpills() {
+	defer close(c.spillDone)
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.writeSpills()
+			return
+		case <-c.spillWake:
+			c.writeSpills()
+		}
+	}
+}
+
+// writeSpills applies the queued overflow store writes without holding c.mu.
+// A write whose key was restored or dropped meanwhile is skipped; the delete
+// queued by dropSpilled follows it.
+func (c *Cacher) writeSpills() {
+	c.mu.Lock()
+	ops := c.spillQueue
+	c.spillQueue = nil
+	c.mu.Unlock()
+
+	for _, op := range ops {
+		if op.entry == nil {
+			if err := c.overflow.Delete(op.key); err != nil {
+				c.logger.Warn("cache overflow delete failed", "key", op.key, "error", err)
+			}
+			continue
+		}
+
+		c.mu.RLock()
+		item := op.entry.pending
+		current := c.spilled[op.key] == op.entry
+		c.mu.RUnlock()
+		if !current {
+			continue
+		}
+
+		err := c.overflow.Set(op.key, *item, op.entry.ttl)
+		c.mu.Lock()
+		if c.spilled[op.key] == op.entry {
+			if err != nil {
+				c.logger.Warn("cache overflow write failed", "key", op.key, "error", err)
+				delete(c.spilled, op.key)
+			} else {
+				op.entry.pending = nil
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// unspill moves a key from the overflow store back into the cache, so the following
+// lookup finds it. The overflow store is read without holding c.mu.
+func (c *Cacher) unspill(key interface{}) {
+	if c.overflow == nil {
+		return
+	}
+	c.mu.RLock()
+	entry, ok := c.spilled[key]
+	var item *spilledItem
+	if ok {
+		item = entry.pending
+	}
+	c.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	found := true
+	if item == nil {
+		value, ttl, ok, err := c.overflow.Get(key)
+		if err != nil {
+			c.logger.Warn("cache overflow read failed", "key", key, "error", err)
+			return
+		}
+		found = ok
+		if ok {
+			item = c.spilledItem(value, ttl)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.spilled[key] != entry {
+		return // Restored, dropped or spilled again meanwhile
+	}
+	c.dropSpilled(key)
+	if found {
+		c.restore(key, entry, item)
+	}
+}
+
+// restorePending moves a key whose overflow store write is still queued back into
+// the cache. Keys already written are left to unspill, which does the I/O.
+// Must be called with c.mu held.
+func (c *Cacher) restorePending(key interface{}) {
+	entry, ok := c.spilled[key]
+	if !ok || entry.pending == nil {
+		return
+	}
+	c.dropSpilled(key)
+	c.restore(key, entry, entry.pending)
+}
+
+// restore adds an item read from the overflow store back to the cache,
+// with the expiration and priority it had when it was evicted.
+// Must be called with c.mu held.
+func (c *Cacher) restore(key interface{}, entry *spilledEntry, s *spilledItem) {
+	item := c.pack(cache{
+		ttl:        s.TTL,
+		deadline:   s.Deadline,
+		counter:    1,
+		createdAt:  s.CreatedAt,
+		lastUsedAt: s.LastUsedAt,
+		priority:   s.Priority,
+		cloner:     entry.cloner,
+	}, s.Value)
+	if c.checkExpiration(item) != nil {
+		return
+	}
+	c.set(key, item)
+}
+
+// spilledItem returns the item stored in the overflow store. Values written
+// without their metadata are restored with the remaining TTL as a sliding TTL.
+func (c *Cacher) spilledItem(value interface{}, ttl time.Duration) *spilledItem {
+	if s, ok := value.(spilledItem); ok {
+		return &s
+	}
+	now := c.now()
+	return &spilledItem{Value: value, TTL: ttl, CreatedAt: now, LastUsedAt: now}
+}
+
+// dropSpilled removes a key from the overflow store.
+// Must be called with c.mu held.
+func (c *Cacher) dropSpilled(key interface{}) {
+	if _, ok := c.spilled[key]; !ok {
+		return
+	}
+	delete(c.spilled, key)
+	c.queueSpill(spillOp{key: key})
+}
+
+// FileBackend is a Backend that stores each item as a gob-encoded file in a directory.
+// Keys and values of custom types must be registered with gob.Register.
+type FileBackend struct {
+	dir string
+}
+
+// fileEntry is the on-disk representation of an item.
+type fileEntry struct {
+	Value     interface{}
+	ExpiresAt time.Time // Zero if the item never expires
+}
+
+// NewFileBackend creates a FileBackend rooted at dir, creating it if needed.
+func NewFileBackend(dir string) (*FileBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create overflow dir: %w", err)
+	}
+	return &FileBackend{dir: dir}, nil
+}
+
+// Get reads an item from disk. Expired items are removed and reported as missing.
+func (b *FileBackend) Get(key interface{}) (interface{}, time.Duration, bool, error) {
+	data, err := os.ReadFile(b.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	var entry fileEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return nil, 0, false, fmt.Errorf("decode %v: %w", key, err)
+	}
+
+	var ttl time.Duration
+	if !entry.ExpiresAt.IsZero() {
+		ttl = time.Until(entry.ExpiresAt)
+		if ttl <= 0 {
+			_ = b.Delete(key)
+			return nil, 0, false, nil
+		}
+	}
+	return entry.Value, ttl, true, nil
+}
+
+// Set writes an item to disk, replacing any previous value.
+func (b *FileBackend) Set(key, value interface{}, ttl time.Duration) error {
+	entry := fileEntry{Value: value}
+	if ttl != 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&entry); err != nil {
+		return fmt.Errorf("encode %v: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(b.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), b.path(key))
+}
+
+// Delete removes an item from disk.
+func (b *FileBackend) Delete(key interface{}) error {
+	err := os.Remove(b.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file name for a key.
+func (b *FileBackend) path(key interface{}) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%T:%v", key, key)))
+	return filepath.Join(b.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,147 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_OverflowRestore(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	require.NoError(t, err)
+
+	cfg := Config{Capacity: 2, EvictionPolicy: LRU, Overflow: backend}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+	cache.Set("k3", "v3", 5*time.Second) // k1 уходит в overflow
+
+	// Запись в overflow идёт в фоне, без блокировки кеша
+	assert.Eventually(t, func() bool {
+		_, _, ok, _ := backend.Get("k1")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	got, err := cache.Get("k1") // k1 возвращается, k2 уходит в overflow
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+
+	assert.Eventually(t, func() bool {
+		_, _, ok, _ := backend.Get("k1")
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+
+	got, err = cache.Get("k2")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got)
+}
+
+func TestCacher_OverflowKeepsDeadline(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	require.NoError(t, err)
+
+	cache := New(Config{Capacity: 1, Overflow: backend})
+	defer cache.Close()
+
+	cache.SetWithDeadline("a", "v1", time.Now().Add(300*time.Millisecond))
+	cache.Set("b", "v2", time.Minute) // a уходит в overflow
+	assert.Eventually(t, func() bool {
+		_, _, ok, _ := backend.Get("a")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	// Восстановленный ключ сохраняет абсолютный срок жизни: частые чтения его не продлевают
+	for i := 0; i < 2; i++ {
+		_, err := cache.Get("a")
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(150 * time.Millisecond)
+	_, err = cache.Get("a")
+	assert.Error(t, err)
+}
+
+func TestCacher_OverflowKeepsPriority(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	require.NoError(t, err)
+
+	cache := New(Config{Capacity: 1, Overflow: backend})
+	defer cache.Close()
+
+	cache.SetWithPriority("a", "v1", time.Minute, 5)
+	cache.Set("b", "v2", time.Minute) // a уходит в overflow
+	assert.Eventually(t, func() bool {
+		_, _, ok, _ := backend.Get("a")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	_, err = cache.Get("a")
+	require.NoError(t, err)
+	entry, err := cache.Entry("a")
+	require.NoError(t, err)
+	assert.Equal(t, 5, entry.Priority)
+	assert.Equal(t, time.Minute, entry.TTL)
+}
+
+func TestCacher_OverflowRestoreThenSet(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	require.NoError(t, err)
+
+	cache := New(Config{Capacity: 1, Overflow: backend})
+	defer cache.Close()
+
+	cache.Set("a", "v1", 5*time.Second)
+	cache.Set("b", "v2", 5*time.Second) // a уходит в overflow
+
+	got, err := cache.Get("a") // a возвращается из overflow
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+
+	// Повторная запись восстановленного ключа не должна паниковать
+	require.NotPanics(t, func() { cache.Set("a", "v3", 5*time.Second) })
+	got, err = cache.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "v3", got)
+	assert.Len(t, cache.Items(), 1)
+}
+
+func TestCacher_OverflowDelete(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	require.NoError(t, err)
+
+	cfg := Config{Capacity: 1, Overflow: backend}
+	cache := New(cfg)
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+
+	require.NoError(t, cache.Delete("k1"))
+	_, err = cache.Get("k1")
+	assert.Error(t, err)
+
+	cache.Set("k3", "v3", 5*time.Second)
+	cache.Clear()
+	_, err = cache.Get("k2")
+	assert.Error(t, err)
+}
+
+func TestFileBackend_Expired(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, backend.Set("k1", 42, 10*time.Millisecond))
+	value, _, ok, err := backend.Get("k1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+
+	time.Sleep(20 * time.Millisecond)
+	_, _, ok, err = backend.Get("k1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,155 @@
+package cacher
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// Pools of list elements and expiry index entries, so caches with a high churn
+// of items reuse them instead of allocating new ones for every Set.
+var (
+	elementPool     sync.Pool
+	expiryEntryPool sync.Pool
+)
+
+// element is an entry of a keyList.
+type element struct {
+	Value      interface{}
+	next, prev *element
+	list       *keyList
+	index      int // Position in list.elems
+}
+
+// Next returns the next list element or nil.
+func (e *element) Next() *element {
+	if p := e.next; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// Prev returns the previous list element or nil.
+func (e *element) Prev() *element {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// keyList is a doubly linked list of keys like container/list,
+// except that removed elements are returned to elementPool for reuse.
+type keyList struct {
+	root     element    // Sentinel, root.next is the front and root.prev the back
+	elems    []*element // Elements in no particular order, for Random
+	len      int
+	counters *counters // Receives allocation stats
+}
+
+// newKeyList creates an empty list that counts allocations in counters.
+func newKeyList(counters *counters) *keyList {
+	l := &keyList{counters: counters}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+// Len returns the number of elements.
+func (l *keyList) Len() int { return l.len }
+
+// Front returns the first element or nil.
+func (l *keyList) Front() *element {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the last element or nil.
+func (l *keyList) Back() *element {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// PushFront inserts a value at the front and returns its element.
+func (l *keyList) PushFront(v interface{}) *element {
+	e, ok := elementPool.Get().(*element)
+	if ok {
+		l.counters.reused++
+	} else {
+		e = &element{}
+		l.counters.allocated++
+	}
+	e.Value = v
+	e.list = l
+	e.index = len(l.elems)
+	l.elems = append(l.elems, e)
+	l.insertAfter(e, &l.root)
+	l.len++
+	return e
+}
+
+// Random returns an element chosen uniformly at random, or nil if the list is empty.
+func (l *keyList) Random() *element {
+	if l.len == 0 {
+		return nil
+	}
+	return l.elems[rand.IntN(len(l.elems))]
+}
+
+// MoveToFront moves an element to the front.
+func (l *keyList) MoveToFront(e *element) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+	l.unlink(e)
+	l.insertAfter(e, &l.root)
+}
+
+// Remove removes an element and returns it to the pool.
+// The element must not be used afterwards.
+func (l *keyList) Remove(e *element) {
+	if e.list != l {
+		return
+	}
+	l.unlink(e)
+	l.len--
+	last := l.elems[len(l.elems)-1]
+	l.elems[e.index], last.index = last, e.index
+	l.elems[len(l.elems)-1] = nil
+	l.elems = l.elems[:len(l.elems)-1]
+	*e = element{}
+	elementPool.Put(e)
+}
+
+func (l *keyList) insertAfter(e, at *element) {
+	e.prev = at
+	e.next = at.next
+	at.next.prev = e
+	at.next = e
+}
+
+func (l *keyList) unlink(e *element) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
+
+// newExpiryEntry returns an expiry index entry, reusing a pooled one if possible.
+func (c *Cacher) newExpiryEntry(key interface{}) *expiryEntry {
+	entry, ok := expiryEntryPool.Get().(*expiryEntry)
+	if ok {
+		c.counters.reused++
+	} else {
+		entry = &expiryEntry{}
+		c.counters.allocated++
+	}
+	entry.key = key
+	return entry
+}
+
+// releaseExpiryEntry returns an entry removed from the expiry index to the pool.
+func releaseExpiryEntry(entry *expiryEntry) {
+	*entry = expiryEntry{}
+	expiryEntryPool.Put(entry)
+}
@@ -0,0 +1,79 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyList(t *testing.T) {
+	var counters counters
+	l := newKeyList(&counters)
+	assert.Nil(t, l.Front())
+	assert.Nil(t, l.Back())
+
+	a := l.PushFront("a")
+	b := l.PushFront("b")
+	c := l.PushFront("c")
+	assert.Equal(t, 3, l.Len())
+	assert.Equal(t, []interface{}{"c", "b", "a"}, listValues(l))
+
+	l.MoveToFront(a)
+	assert.Equal(t, []interface{}{"a", "c", "b"}, listValues(l))
+	assert.Nil(t, a.Prev())
+	assert.Nil(t, b.Next())
+
+	l.Remove(c)
+	assert.Equal(t, []interface{}{"a", "b"}, listValues(l))
+	assert.Equal(t, b, l.Back())
+	assert.Equal(t, 2, l.Len())
+}
+
+func TestKeyList_Random(t *testing.T) {
+	var counters counters
+	l := newKeyList(&counters)
+	assert.Nil(t, l.Random())
+
+	elems := make([]*element, 10)
+	for i := range elems {
+		elems[i] = l.PushFront(i)
+	}
+	l.Remove(elems[0])
+	l.Remove(elems[5])
+	l.Remove(elems[9])
+
+	// Удалённые элементы не выбираются, остальные выбираются примерно поровну
+	seen := make(map[interface{}]int)
+	for i := 0; i < 7000; i++ {
+		seen[l.Random().Value]++
+	}
+	assert.Len(t, seen, 7)
+	for _, i := range []int{0, 5, 9} {
+		assert.NotContains(t, seen, i)
+	}
+	for value, n := range seen {
+		assert.InDelta(t, 1000, n, 200, value)
+	}
+}
+
+func TestCacher_PoolReuse(t *testing.T) {
+	cache := New(Config{Capacity: 10, ClearingInterval: -1})
+	defer cache.Close()
+
+	// Вытесненные элементы возвращаются в пул и переиспользуются
+	for i := 0; i < 1000; i++ {
+		cache.Set(i, i, time.Minute)
+	}
+	m := cache.Metrics()
+	assert.Equal(t, uint64(2000), m.Allocated+m.Reused)
+	assert.Greater(t, m.Reused, uint64(0))
+}
+
+func listValues(l *keyList) []interface{} {
+	var values []interface{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value)
+	}
+	return values
+}
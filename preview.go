@@ -0,0 +1,102 @@
+package cacher
+
+// NextVictims returns up to n keys the current eviction policy would evict next,
+// in order, without removing them. Pinned items are never returned, and items
+// with a lower priority come first. With RANDOM and WRANDOM the keys are only an example,
+// since the actual choice is random, and so are sampled LFU choices and the order
+// of LFU items with equal counts.
+func (c *Cacher) NextVictims(n int) []interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	priorities := make(map[int]int, len(c.priorities))
+	for priority, count := range c.priorities {
+		priorities[priority] = count
+	}
+	chosen := make(map[interface{}]struct{}, n)
+
+	var victims []interface{}
+	for len(victims) < n && len(priorities) > 0 {
+		lowest := 0
+		first := true
+		for priority := range priorities {
+			if first || priority < lowest {
+				lowest, first = priority, false
+			}
+		}
+		candidate := func(key interface{}) bool {
+			if _, ok := chosen[key]; ok {
+				return false
+			}
+			item, ok := c.cache[key]
+			return ok && !item.pinned && item.priority == lowest
+		}
+
+		key, ok := c.victimExpiring(candidate)
+		switch {
+		case ok:
+		case c.evictionPolicy == LRU:
+			key, ok = c.victimLRU(candidate)
+		case c.evictionPolicy == MRU:
+			key, ok = c.victimMRU(candidate)
+		case c.evictionPolicy == LFU:
+			key, ok = c.victimLFU(candidate)
+		case c.evictionPolicy == RANDOM:
+			key, ok = c.victimRANDOM(candidate)
+		case c.evictionPolicy == CLOCK:
+			key, ok = c.previewCLOCK(candidate)
+		case c.evictionPolicy == SLRU:
+			key, ok = c.victimSLRU(candidate)
+		case c.evictionPolicy == LRUK:
+			key, ok = c.victimLRUK(candidate)
+		case c.evictionPolicy == WRANDOM:
+			key, ok = c.victimWRANDOM(candidate)
+		case c.evictionPolicy == GDSF:
+			key, ok = c.victimGDSF(candidate)
+		case c.evictionPolicy == HYPERBOLIC:
+			key, ok = c.victimHyperbolic(candidate)
+		case c.evictionPolicy == LIFO:
+			key, ok = c.victimLIFO(candidate)
+		}
+		if !ok {
+			break
+		}
+
+		victims = append(victims, key)
+		chosen[key] = struct{}{}
+		if priorities[lowest]--; priorities[lowest] <= 0 {
+			delete(priorities, lowest)
+		}
+	}
+	return victims
+}
+
+// previewCLOCK returns the candidate victimCLOCK would choose, without moving
+// the hand or clearing reference bits: the first unreferenced candidate from
+// the hand, or else the first referenced one, whose bit the sweep would clear.
+func (c *Cacher) previewCLOCK(candidate func(interface{}) bool) (interface{}, bool) {
+	var referenced interface{}
+	found := false
+
+	e := c.hand
+	if e == nil {
+		e = c.keys.Back()
+	}
+	for i := 0; i < c.keys.Len(); i++ {
+		if e == nil {
+			e = c.keys.Back()
+		}
+		key := e.Value
+		e = e.Prev()
+		if !candidate(key) {
+			continue
+		}
+		if !c.cache[key].referenced {
+			return key, true
+		}
+		if !found {
+			referenced, found = key, true
+		}
+	}
+	return referenced, found
+}
@@ -0,0 +1,54 @@
+package cacher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_NextVictims(t *testing.T) {
+	for _, policy := range []int{LRU, MRU, LFU, CLOCK, SLRU, LRUK} {
+		t.Run(policyName(policy), func(t *testing.T) {
+			cache := New(Config{Capacity: 5, EvictionPolicy: policy})
+			defer cache.Close()
+
+			for i := 0; i < 5; i++ {
+				cache.Set(i, i, 0)
+			}
+			for i := 0; i < 4; i++ {
+				for j := 0; j < i; j++ {
+					cache.Get(3 - i) // разные счётчики, чтобы у LFU не было равных
+				}
+			}
+			require.NoError(t, cache.Pin(4))
+
+			victims := cache.NextVictims(3)
+			require.Len(t, victims, 3)
+			assert.NotContains(t, victims, 4)
+
+			// предсказание совпадает с фактическим вытеснением
+			cache.mu.Lock()
+			defer cache.mu.Unlock()
+			for _, want := range victims {
+				key, ok := cache.victim()
+				require.True(t, ok)
+				assert.Equal(t, want, key)
+				cache.evictKey(key)
+			}
+		})
+	}
+}
+
+func TestCacher_NextVictimsPriority(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	cache.SetWithPriority("low", 1, 0, 0)
+	cache.SetWithPriority("high", 2, 0, 5)
+	cache.SetWithPriority("low2", 3, 0, 0)
+
+	assert.Equal(t, []interface{}{"low", "low2", "high"}, cache.NextVictims(10))
+	assert.Equal(t, 3, cache.Metrics().Items)
+	assert.Empty(t, cache.NextVictims(0))
+}
@@ -0,0 +1,62 @@
+package cacher
+
+import "sort"
+
+// Ranking criteria for TopKeys and ColdKeys
+const (
+	ByCounter = iota // Access counter
+	ByRecency        // Last access time
+)
+
+// TopKeys returns up to n keys with the highest rank: the most accessed
+// (ByCounter) or the most recently used (ByRecency). Expired items are skipped.
+func (c *Cacher) TopKeys(n int, by int) []interface{} {
+	return c.rankKeys(n, by, true)
+}
+
+// ColdKeys returns up to n keys with the lowest rank: the least accessed
+// (ByCounter) or the least recently used (ByRecency). Expired items are skipped.
+func (c *Cacher) ColdKeys(n int, by int) []interface{} {
+	return c.rankKeys(n, by, false)
+}
+
+// rankKeys sorts live keys by the criterion and returns the first n.
+func (c *Cacher) rankKeys(n int, by int, hottest bool) []interface{} {
+	if n <= 0 {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	type ranked struct {
+		key  interface{}
+		item cache
+	}
+	items := make([]ranked, 0, len(c.cache))
+	for key, item := range c.cache {
+		if c.checkExpiration(item) == nil {
+			items = append(items, ranked{key, item})
+		}
+	}
+
+	// less reports whether a ranks below b
+	less := func(a, b cache) bool {
+		if by == ByCounter && a.counter != b.counter {
+			return a.counter < b.counter
+		}
+		return a.lastUsedAt.Before(b.lastUsedAt)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if hottest {
+			return less(items[j].item, items[i].item)
+		}
+		return less(items[i].item, items[j].item)
+	})
+
+	keys := make([]interface{}, 0, min(n, len(items)))
+	for _, r := range items[:min(n, len(items))] {
+		keys = append(keys, r.key)
+	}
+	return keys
+}
@@ -0,0 +1,41 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacher_TopKeys(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+	cache.Set("k3", "v3", 5*time.Second)
+	for i := 0; i < 3; i++ {
+		cache.Get("k2")
+	}
+	cache.Get("k1")
+	time.Sleep(time.Millisecond)
+	cache.Get("k3")
+
+	assert.Equal(t, []interface{}{"k2", "k3"}, cache.TopKeys(2, ByCounter))
+	assert.Equal(t, []interface{}{"k3", "k1", "k2"}, cache.TopKeys(10, ByRecency))
+	assert.Nil(t, cache.TopKeys(0, ByCounter))
+}
+
+func TestCacher_ColdKeys(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 5*time.Second)
+	cache.Set("k2", "v2", 5*time.Second)
+	cache.Set("expired", "v", time.Millisecond)
+	cache.Get("k1")
+	time.Sleep(2 * time.Millisecond)
+
+	assert.Equal(t, []interface{}{"k2"}, cache.ColdKeys(1, ByCounter))
+	assert.Equal(t, []interface{}{"k2", "k1"}, cache.ColdKeys(5, ByRecency))
+}
@@ -0,0 +1,157 @@
+// Package ratelimit throttles clients with window counters kept in a *cacher.Cacher,
+// so services already embedding a cacher need no other dependency:
+//
+//	limiter := ratelimit.NewSlidingWindow(cache, ratelimit.Options{Name: "api", Limit: 100, Window: time.Minute})
+//	if res := limiter.AllowN(clientIP, 1); !res.Allowed {
+//		w.Header().Set("Retry-After", strconv.Itoa(int(res.RetryAfter.Seconds())+1))
+//		...
+//	}
+//
+// Counters are checked and updated atomically with Cacher.IncrementMax and expire
+// with their windows. Identifiers can be any comparable values, e.g. client IPs or user IDs.
+// Requests are rejected while a counter cannot be stored, so use a cache without
+// a doorkeeper and do not Delete counters of a cache with tombstones.
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+// Options configures a limiter.
+type Options struct {
+	// Name keeps the counters of limiters sharing a cacher apart.
+	Name string
+
+	// Limit is the number of requests allowed per window.
+	Limit int
+
+	// Window is the length of a window.
+	Window time.Duration
+}
+
+// Result is the outcome of a request to a limiter.
+type Result struct {
+	Allowed    bool
+	Remaining  int           // Requests left in the current window
+	RetryAfter time.Duration // If not allowed, how long to wait before retrying
+}
+
+// Limiter decides whether requests of an identifier are allowed.
+type Limiter interface {
+	// AllowN reports whether n requests are allowed now and counts them if they are.
+	AllowN(id interface{}, n int) Result
+}
+
+// windowKey is the cache key of the counter of an identifier in a window.
+type windowKey struct {
+	name  string
+	id    interface{}
+	start int64 // Start of the window in Unix nanoseconds
+}
+
+// FixedWindow counts requests in consecutive windows aligned to the clock.
+// It is cheap, but allows up to twice the limit around a window boundary.
+type FixedWindow struct {
+	cache *cacher.Cacher
+	opts  Options
+	now   func() time.Time
+}
+
+// NewFixedWindow creates a fixed window limiter keeping its counters in cache.
+func NewFixedWindow(cache *cacher.Cacher, opts Options) *FixedWindow {
+	return &FixedWindow{cache: cache, opts: opts, now: time.Now}
+}
+
+// Allow reports whether a request is allowed now and counts it if it is.
+func (l *FixedWindow) Allow(id interface{}) bool {
+	return l.AllowN(id, 1).Allowed
+}
+
+// AllowN reports whether n requests are allowed now and counts them if they are.
+func (l *FixedWindow) AllowN(id interface{}, n int) Result {
+	now := l.now()
+	start := now.Truncate(l.opts.Window)
+	left := start.Add(l.opts.Window).Sub(now)
+	key := windowKey{name: l.opts.Name, id: id, start: start.UnixNano()}
+
+	// Rejected requests are not counted
+	count, ok, err := l.cache.IncrementMax(key, int64(n), int64(l.opts.Limit), left)
+	if err != nil || !ok {
+		return Result{Remaining: remaining(l.opts.Limit, float64(count)), RetryAfter: left}
+	}
+	return Result{Allowed: true, Remaining: remaining(l.opts.Limit, float64(count))}
+}
+
+// SlidingWindow estimates the requests of the last window from the counters of the
+// current and previous fixed windows, weighting the previous one by how much of it
+// the sliding window still covers. It smooths bursts at window boundaries.
+type SlidingWindow struct {
+	cache *cacher.Cacher
+	opts  Options
+	now   func() time.Time
+}
+
+// NewSlidingWindow creates a sliding window limiter keeping its counters in cache.
+func NewSlidingWindow(cache *cacher.Cacher, opts Options) *SlidingWindow {
+	return &SlidingWindow{cache: cache, opts: opts, now: time.Now}
+}
+
+// Allow reports whether a request is allowed now and counts it if it is.
+func (l *SlidingWindow) Allow(id interface{}) bool {
+	return l.AllowN(id, 1).Allowed
+}
+
+// AllowN reports whether n requests are allowed now and counts them if they are.
+// RetryAfter is an estimate.
+func (l *SlidingWindow) AllowN(id interface{}, n int) Result {
+	window := l.opts.Window
+	now := l.now()
+	start := now.Truncate(window)
+	elapsed := now.Sub(start)
+	key := windowKey{name: l.opts.Name, id: id, start: start.UnixNano()}
+
+	// The counter outlives its window to serve as the previous one in the next
+	ttl := 2*window - elapsed
+	prev := l.count(windowKey{name: l.opts.Name, id: id, start: start.Add(-window).UnixNano()})
+
+	// The current counter may reach what the previous window's share leaves of the limit
+	weight := 1 - float64(elapsed)/float64(window)
+	limit := int64(math.Floor(float64(l.opts.Limit) - float64(prev)*weight))
+	count, ok, err := l.cache.IncrementMax(key, int64(n), limit, ttl)
+	estimate := float64(prev)*weight + float64(count)
+	if err == nil && ok {
+		return Result{Allowed: true, Remaining: remaining(l.opts.Limit, estimate)}
+	}
+
+	// Wait until the previous window's share drops enough, or for the next window
+	retry := window - elapsed
+	if prev > 0 {
+		excess := estimate + float64(n) - float64(l.opts.Limit)
+		retry = min(retry, time.Duration(excess/float64(prev)*float64(window)))
+	}
+	return Result{Remaining: remaining(l.opts.Limit, estimate), RetryAfter: retry}
+}
+
+// count returns the value of a counter, or 0 if it is missing.
+// Reading it with Entry does not count as an access.
+func (l *SlidingWindow) count(key windowKey) int64 {
+	entry, err := l.cache.Entry(key)
+	if err != nil {
+		return 0
+	}
+	switch n := entry.Value.(type) {
+	case int64:
+		return n
+	case float64: // Decoded by a JSON codec
+		return int64(n)
+	}
+	return 0
+}
+
+// remaining returns the requests left under a limit after used, rounding used up.
+func remaining(limit int, used float64) int {
+	return max(limit-int(math.Ceil(used)), 0)
+}
@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	t time.Time
+}
+
+// newFakeClock returns a clock starting at a window boundary.
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Unix(1_700_000_000, 0).Truncate(time.Hour)}
+}
+
+func (c *fakeClock) now() time.Time      { return c.t }
+func (c *fakeClock) add(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestCache(t *testing.T) *cacher.Cacher {
+	cache := cacher.New(cacher.Config{})
+	t.Cleanup(cache.Close)
+	return cache
+}
+
+var (
+	_ Limiter = (*FixedWindow)(nil)
+	_ Limiter = (*SlidingWindow)(nil)
+)
+
+func TestFixedWindow(t *testing.T) {
+	clock := newFakeClock()
+	l := NewFixedWindow(newTestCache(t), Options{Limit: 3, Window: time.Minute})
+	l.now = clock.now
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.Allow("alice"))
+	}
+	clock.add(20 * time.Second)
+	res := l.AllowN("alice", 1)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 40*time.Second, res.RetryAfter)
+
+	// Счётчики разных идентификаторов независимы
+	res = l.AllowN("bob", 2)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	// Отклонённый запрос не расходует лимит
+	assert.False(t, l.AllowN("bob", 2).Allowed)
+	assert.True(t, l.Allow("bob"))
+
+	// Новое окно — новый счётчик
+	clock.add(40 * time.Second)
+	assert.True(t, l.Allow("alice"))
+}
+
+func TestFixedWindow_Concurrent(t *testing.T) {
+	l := NewFixedWindow(newTestCache(t), Options{Limit: 10, Window: time.Hour})
+	assert.True(t, l.AllowN("alice", 9).Allowed)
+
+	// Отклоняемые запросы не должны занимать лимит даже на время
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if l.AllowN("alice", 5).Allowed {
+					allowed.Add(1)
+				}
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, l.Allow("alice")) // Последний свободный запрос
+	close(stop)
+	wg.Wait()
+	assert.Zero(t, allowed.Load())
+}
+
+func TestFixedWindow_NotStored(t *testing.T) {
+	cache := cacher.New(cacher.Config{DoorkeeperKeys: 100})
+	t.Cleanup(cache.Close)
+	clock := newFakeClock()
+	l := NewFixedWindow(cache, Options{Limit: 3, Window: time.Minute})
+	l.now = clock.now
+
+	// Счётчик, не пропущенный doorkeeper, не разрешает запрос
+	res := l.AllowN("alice", 1)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Minute, res.RetryAfter)
+	assert.True(t, l.Allow("alice"))
+}
+
+func TestFixedWindow_Names(t *testing.T) {
+	cache := newTestCache(t)
+	login := NewFixedWindow(cache, Options{Name: "login", Limit: 1, Window: time.Minute})
+	api := NewFixedWindow(cache, Options{Name: "api", Limit: 1, Window: time.Minute})
+
+	assert.True(t, login.Allow("alice"))
+	assert.False(t, login.Allow("alice"))
+	assert.True(t, api.Allow("alice"))
+}
+
+func TestSlidingWindow(t *testing.T) {
+	clock := newFakeClock()
+	l := NewSlidingWindow(newTestCache(t), Options{Limit: 10, Window: time.Minute})
+	l.now = clock.now
+
+	// Всплеск в конце окна
+	clock.add(50 * time.Second)
+	assert.True(t, l.AllowN("alice", 10).Allowed)
+	assert.False(t, l.Allow("alice"))
+
+	// В начале следующего окна предыдущее ещё весит 3/4: 10*0.75 = 7.5
+	clock.add(25 * time.Second)
+	res := l.AllowN("alice", 2)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining) // 9.5 округляется вверх
+
+	res = l.AllowN("alice", 1)
+	assert.False(t, res.Allowed)
+	// Нужно, чтобы доля предыдущего окна упала на 0.5, то есть 3 секунды
+	assert.Equal(t, 3*time.Second, res.RetryAfter)
+
+	clock.add(res.RetryAfter)
+	assert.True(t, l.Allow("alice"))
+
+	// Через два окна предыдущего счётчика уже нет
+	clock.add(2 * time.Minute)
+	assert.True(t, l.AllowN("alice", 10).Allowed)
+}
+
+func TestSlidingWindow_RetryNextWindow(t *testing.T) {
+	clock := newFakeClock()
+	l := NewSlidingWindow(newTestCache(t), Options{Limit: 2, Window: time.Minute})
+	l.now = clock.now
+
+	clock.add(10 * time.Second)
+	assert.True(t, l.AllowN("alice", 2).Allowed)
+	res := l.AllowN("alice", 1)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 50*time.Second, res.RetryAfter)
+}
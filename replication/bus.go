@@ -0,0 +1,75 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Invalidation is a message telling replicas to drop keys.
+// Adapters encode it as JSON, so services in other languages can publish it too.
+type Invalidation struct {
+	Origin string   `json:"origin"`         // ID of the node that published it
+	Keys   []string `json:"keys,omitempty"` // Keys to drop
+	All    bool     `json:"all,omitempty"`  // Drop all keys
+}
+
+// InvalidationBus is a publish/subscribe channel for invalidations, such as
+// NATS subjects (package cachernats) or Redis pub/sub (package cacherredis).
+// Implementations must be safe for concurrent use.
+type InvalidationBus interface {
+	// Publish sends an invalidation to all subscribers.
+	Publish(ctx context.Context, inv Invalidation) error
+
+	// Subscribe registers the handler for received invalidations,
+	// including those published by this node.
+	Subscribe(handler func(Invalidation)) error
+
+	// Close unsubscribes and releases the bus resources.
+	Close() error
+}
+
+// errValueOverBus is returned when a Set event is published over an invalidation bus.
+var errValueOverBus = errors.New("replication: an invalidation bus cannot replicate values")
+
+// busTransport adapts an InvalidationBus to a Transport.
+type busTransport struct {
+	bus InvalidationBus
+}
+
+// BusTransport returns a Transport sending Delete and Clear events over an invalidation bus,
+// for a Replicator without ReplicateSets. Keys are sent as strings, formatted with fmt.Sprint,
+// and arrive as those strings, so non-string keys do not round-trip: a Delete of the
+// key 42 removes the key "42" on other nodes, not 42. Use string keys in replicated caches.
+func BusTransport(bus InvalidationBus) Transport {
+	return busTransport{bus: bus}
+}
+
+func (t busTransport) Publish(e Event) error {
+	inv := Invalidation{Origin: e.Origin}
+	switch e.Op {
+	case OpDelete:
+		inv.Keys = []string{fmt.Sprint(e.Key)}
+	case OpClear:
+		inv.All = true
+	default:
+		return errValueOverBus
+	}
+	return t.bus.Publish(context.Background(), inv)
+}
+
+func (t busTransport) Subscribe(handler func(Event)) error {
+	return t.bus.Subscribe(func(inv Invalidation) {
+		if inv.All {
+			handler(Event{Origin: inv.Origin, Op: OpClear})
+			return
+		}
+		for _, key := range inv.Keys {
+			handler(Event{Origin: inv.Origin, Op: OpDelete, Key: key})
+		}
+	})
+}
+
+func (t busTransport) Close() error {
+	return t.bus.Close()
+}
@@ -0,0 +1,72 @@
+package replication
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBus delivers invalidations to all subscribers in the process.
+type memoryBus struct {
+	mu       sync.Mutex
+	handlers []func(Invalidation)
+}
+
+func (b *memoryBus) Publish(_ context.Context, inv Invalidation) error {
+	b.mu.Lock()
+	handlers := append([]func(Invalidation){}, b.handlers...)
+	b.mu.Unlock()
+	for _, handler := range handlers {
+		handler(inv)
+	}
+	return nil
+}
+
+func (b *memoryBus) Subscribe(handler func(Invalidation)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
+
+func (b *memoryBus) Close() error {
+	return nil
+}
+
+func TestBusTransport(t *testing.T) {
+	bus := &memoryBus{}
+	a, err := New(cacher.New(cacher.Config{}), BusTransport(bus), Options{})
+	require.NoError(t, err)
+	b, err := New(cacher.New(cacher.Config{}), BusTransport(bus), Options{})
+	require.NoError(t, err)
+	defer a.Cache().Close()
+	defer b.Cache().Close()
+
+	b.Cache().Set("k1", "old", 0)
+	require.NoError(t, a.Set("k1", "new", time.Minute))
+
+	// Запись на одной реплике удаляет ключ на другой, но не у себя
+	_, err = b.Cache().Get("k1")
+	assert.Error(t, err)
+	got, err := a.Cache().Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "new", got)
+
+	b.Cache().Set("k2", "v2", 0)
+	require.NoError(t, a.Clear())
+	_, err = b.Cache().Get("k2")
+	assert.Error(t, err)
+}
+
+func TestBusTransport_NoValues(t *testing.T) {
+	r, err := New(cacher.New(cacher.Config{}), BusTransport(&memoryBus{}), Options{ReplicateSets: true})
+	require.NoError(t, err)
+	defer r.Cache().Close()
+
+	assert.ErrorIs(t, r.Set("k1", "v1", 0), errValueOverBus)
+}
@@ -0,0 +1,190 @@
+// Package replication keeps several cacher instances coherent by propagating
+// Set, Delete and Clear events between them over a Transport.
+//
+// By default a Set on one node only invalidates the key on its peers, so they
+// reload the value on their next miss. With Options.ReplicateSets the value
+// itself is sent and stored on every peer.
+//
+// Invalidations can also travel over a message broker: BusTransport adapts an
+// InvalidationBus, such as NATS or Redis pub/sub, to a Transport.
+package replication
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+// Op is the kind of a replicated operation.
+type Op int
+
+// Replicated operations
+const (
+	OpSet    Op = iota // Stores a value on peers
+	OpDelete           // Removes a key on peers
+	OpClear            // Removes all keys on peers
+)
+
+// Event is a single cache operation sent between nodes.
+type Event struct {
+	Origin string        // ID of the node that produced the event
+	Op     Op            // Operation kind
+	Key    interface{}   // Key for OpSet and OpDelete
+	Value  interface{}   // Value for OpSet
+	TTL    time.Duration // TTL for OpSet
+
+	// Encoded reports that Value holds bytes produced by the sender's Codec.
+	Encoded bool
+}
+
+// Transport delivers events between nodes.
+// Implementations must be safe for concurrent use.
+type Transport interface {
+	// Publish sends an event to all other nodes.
+	Publish(e Event) error
+
+	// Subscribe registers the handler for events received from other nodes.
+	Subscribe(handler func(Event)) error
+
+	// Close releases the transport resources.
+	Close() error
+}
+
+// Options configures a Replicator.
+type Options struct {
+	// ID identifies this node. Events with the same origin are ignored.
+	// If empty, a random ID is generated.
+	ID string
+
+	// ReplicateSets sends values with Set events instead of invalidating the key on peers.
+	ReplicateSets bool
+
+	// Codec encodes replicated values, e.g. for peers in other languages.
+	// Defaults to the cache codec. If neither is set, values are sent as is.
+	Codec cacher.Codec
+}
+
+// Replicator wraps a Cacher and propagates write operations to peers.
+// Writes that should reach other nodes must go through the Replicator.
+type Replicator struct {
+	cache     *cacher.Cacher
+	transport Transport
+	id        string
+	sets      bool
+	codec     cacher.Codec
+}
+
+// New creates a Replicator and subscribes it to the transport.
+func New(cache *cacher.Cacher, transport Transport, opts Options) (*Replicator, error) {
+	if opts.ID == "" {
+		opts.ID = randomID()
+	}
+	if opts.Codec == nil {
+		opts.Codec = cache.Codec()
+	}
+
+	r := &Replicator{
+		cache:     cache,
+		transport: transport,
+		id:        opts.ID,
+		sets:      opts.ReplicateSets,
+		codec:     opts.Codec,
+	}
+	if err := transport.Subscribe(r.apply); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Cache returns the local cache.
+func (r *Replicator) Cache() *cacher.Cacher {
+	return r.cache
+}
+
+// Set stores the value locally and propagates the write to peers,
+// either as a replicated value or as an invalidation of the key.
+// If the cache has a Config.Store, only this node writes the value to it;
+// peers store replicated values in their caches only.
+func (r *Replicator) Set(key, value interface{}, ttl time.Duration) error {
+	r.cache.Set(key, value, ttl)
+
+	if !r.sets {
+		return r.transport.Publish(Event{Origin: r.id, Op: OpDelete, Key: key})
+	}
+	e := Event{Origin: r.id, Op: OpSet, Key: key, Value: value, TTL: ttl}
+	if r.codec != nil {
+		data, err := r.codec.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("encode %v: %w", key, err)
+		}
+		e.Value, e.Encoded = data, true
+	}
+	return r.transport.Publish(e)
+}
+
+// Delete removes the key locally and on peers.
+// The key is deleted on peers even if it was not present locally.
+func (r *Replicator) Delete(key interface{}) error {
+	localErr := r.cache.Delete(key)
+	if err := r.transport.Publish(Event{Origin: r.id, Op: OpDelete, Key: key}); err != nil {
+		return err
+	}
+	return localErr
+}
+
+// Clear removes all items locally and on peers.
+func (r *Replicator) Clear() error {
+	r.cache.Clear()
+	return r.transport.Publish(Event{Origin: r.id, Op: OpClear})
+}
+
+// Close closes the transport. The local cache is not closed.
+func (r *Replicator) Close() error {
+	return r.transport.Close()
+}
+
+// apply executes an event received from a peer.
+func (r *Replicator) apply(e Event) {
+	if e.Origin == r.id {
+		return
+	}
+
+	switch e.Op {
+	case OpSet:
+		value, err := r.decode(e)
+		if err != nil {
+			// The value cannot be applied, so drop the stale local copy instead.
+			_ = r.cache.Delete(e.Key)
+			return
+		}
+		// Not Set: the origin already wrote the value to its Config.Store,
+		// so replicas must not write it again
+		r.cache.SetWithPriority(e.Key, value, e.TTL, 0)
+	case OpDelete:
+		_ = r.cache.Delete(e.Key)
+	case OpClear:
+		r.cache.Clear()
+	}
+}
+
+// decode returns the value of a Set event.
+func (r *Replicator) decode(e Event) (interface{}, error) {
+	if !e.Encoded {
+		return e.Value, nil
+	}
+	data, ok := e.Value.([]byte)
+	if !ok || r.codec == nil {
+		return nil, fmt.Errorf("cannot decode value for key: %v", e.Key)
+	}
+	return r.codec.Unmarshal(data)
+}
+
+// randomID generates a node ID.
+func randomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,116 @@
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/danRulev/cacher/cachermsgpack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPair(t *testing.T, opts Options) (*Replicator, *Replicator) {
+	ta, err := ListenTCP("127.0.0.1:0")
+	require.NoError(t, err)
+	tb, err := ListenTCP("127.0.0.1:0", ta.Addr().String())
+	require.NoError(t, err)
+	ta.AddPeer(tb.Addr().String())
+
+	a, err := New(cacher.New(cacher.Config{}), ta, opts)
+	require.NoError(t, err)
+	b, err := New(cacher.New(cacher.Config{}), tb, opts)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		a.Close()
+		b.Close()
+		a.Cache().Close()
+		b.Cache().Close()
+	})
+	return a, b
+}
+
+func TestReplicator_Delete(t *testing.T) {
+	a, b := newPair(t, Options{})
+
+	b.Cache().Set("k1", "v1", 0)
+	assert.Error(t, a.Delete("k1")) // локально ключа нет, но у пира он удаляется
+
+	assert.Eventually(t, func() bool {
+		_, err := b.Cache().Get("k1")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReplicator_SetInvalidates(t *testing.T) {
+	a, b := newPair(t, Options{})
+
+	b.Cache().Set("k1", "old", 0)
+	require.NoError(t, a.Set("k1", "new", 0))
+
+	assert.Eventually(t, func() bool {
+		_, err := b.Cache().Get("k1")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	got, err := a.Cache().Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "new", got)
+}
+
+func TestReplicator_ReplicateSets(t *testing.T) {
+	a, b := newPair(t, Options{ReplicateSets: true})
+
+	require.NoError(t, a.Set("k1", "v1", time.Minute))
+
+	assert.Eventually(t, func() bool {
+		got, err := b.Cache().Get("k1")
+		return err == nil && got == "v1"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReplicator_ApplyDoesNotWriteStore(t *testing.T) {
+	store, err := cacher.NewFileBackend(t.TempDir())
+	require.NoError(t, err)
+	cache := cacher.New(cacher.Config{Store: store})
+	defer cache.Close()
+	r := &Replicator{cache: cache, id: "b"}
+
+	// Значение от пира попадает в кэш, но не записывается в хранилище ещё раз
+	r.apply(Event{Origin: "a", Op: OpSet, Key: "k1", Value: "v1", TTL: time.Minute})
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+	_, _, ok, err := store.Get("k1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestReplicator_Clear(t *testing.T) {
+	a, b := newPair(t, Options{})
+
+	a.Cache().Set("k1", "v1", 0)
+	b.Cache().Set("k2", "v2", 0)
+	require.NoError(t, b.Clear())
+
+	assert.Eventually(t, func() bool {
+		return len(a.Cache().GetAll()) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestReplicator_Codec(t *testing.T) {
+	// point не зарегистрирован в gob, поэтому без кодека событие не отправится
+	a, b := newPair(t, Options{ReplicateSets: true, Codec: cachermsgpack.Typed(point{})})
+
+	require.NoError(t, a.Set("k1", point{X: 1, Y: 2}, time.Minute))
+
+	assert.Eventually(t, func() bool {
+		got, err := b.Cache().Get("k1")
+		return err == nil && got == point{X: 1, Y: 2}
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,206 @@
+package replication
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// dialTimeout limits how long Publish waits to connect to a peer.
+const dialTimeout = time.Second
+
+// TCPTransport is a Transport that sends gob-encoded events directly to a fixed set of peers.
+// Keys and values of custom types must be registered with gob.Register.
+type TCPTransport struct {
+	listener net.Listener
+
+	peersMu sync.Mutex           // Held while publishing, separate from mu so receiving never waits on peers
+	peers   map[string]*peerConn // Outgoing connections by address
+
+	mu      sync.Mutex
+	inbound map[net.Conn]struct{}
+	handler func(Event)
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// peerConn is an outgoing connection to a peer.
+// A nil conn means it has to be dialed on the next Publish.
+type peerConn struct {
+	conn net.Conn
+	enc  *gob.Encoder
+}
+
+// ListenTCP starts a transport listening on addr that publishes to the given peer addresses.
+func ListenTCP(addr string, peers ...string) (*TCPTransport, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &TCPTransport{
+		listener: l,
+		peers:    make(map[string]*peerConn),
+		inbound:  make(map[net.Conn]struct{}),
+	}
+	for _, peer := range peers {
+		t.peers[peer] = &peerConn{}
+	}
+
+	t.wg.Add(1)
+	go t.accept()
+	return t, nil
+}
+
+// Addr returns the listening address.
+func (t *TCPTransport) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+// AddPeer adds a peer address to publish events to.
+func (t *TCPTransport) AddPeer(addr string) {
+	t.peersMu.Lock()
+	defer t.peersMu.Unlock()
+
+	if _, ok := t.peers[addr]; !ok {
+		t.peers[addr] = &peerConn{}
+	}
+}
+
+// Publish sends the event to every peer.
+// Peers that cannot be reached are skipped and reported in the returned error.
+func (t *TCPTransport) Publish(e Event) error {
+	t.peersMu.Lock()
+	defer t.peersMu.Unlock()
+
+	t.mu.Lock()
+	closed := t.closed
+	t.mu.Unlock()
+	if closed {
+		return errors.New("replication: transport closed")
+	}
+
+	var errs []error
+	for addr, peer := range t.peers {
+		if err := peer.send(addr, e); err != nil {
+			errs = append(errs, fmt.Errorf("peer %s: %w", addr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe sets the handler for incoming events.
+// Events received before Subscribe is called are dropped.
+func (t *TCPTransport) Subscribe(handler func(Event)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.handler = handler
+	return nil
+}
+
+// Close stops the listener and closes all connections.
+func (t *TCPTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	err := t.listener.Close()
+	for conn := range t.inbound {
+		conn.Close()
+	}
+	t.mu.Unlock()
+
+	t.peersMu.Lock()
+	for _, peer := range t.peers {
+		peer.close()
+	}
+	t.peersMu.Unlock()
+
+	t.wg.Wait()
+	return err
+}
+
+// accept serves incoming peer connections until the listener is closed.
+func (t *TCPTransport) accept() {
+	defer t.wg.Done()
+
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		t.mu.Lock()
+		if t.closed {
+			t.mu.Unlock()
+			conn.Close()
+			return
+		}
+		t.inbound[conn] = struct{}{}
+		t.wg.Add(1)
+		t.mu.Unlock()
+
+		go t.receive(conn)
+	}
+}
+
+// receive decodes events from a peer and passes them to the handler.
+func (t *TCPTransport) receive(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		t.mu.Lock()
+		delete(t.inbound, conn)
+		t.mu.Unlock()
+		t.wg.Done()
+	}()
+
+	dec := gob.NewDecoder(conn)
+	for {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			return
+		}
+
+		t.mu.Lock()
+		handler := t.handler
+		t.mu.Unlock()
+
+		if handler != nil {
+			handler(e)
+		}
+	}
+}
+
+// send writes an event to the peer, dialing it first if needed.
+// The connection is dropped on error and redialed on the next call.
+func (p *peerConn) send(addr string, e Event) error {
+	if p.conn == nil {
+		conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+		if err != nil {
+			return err
+		}
+		p.conn = conn
+		p.enc = gob.NewEncoder(conn)
+	}
+
+	if err := p.enc.Encode(&e); err != nil {
+		p.close()
+		return err
+	}
+	return nil
+}
+
+// close closes the connection if it is open.
+func (p *peerConn) close() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+		p.enc = nil
+	}
+}
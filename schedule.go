@@ -0,0 +1,117 @@
+package cacher
+
+import (
+	"container/heap"
+	"time"
+)
+
+// scheduledItem is a value set with SetAfter that is not visible yet,
+// or a removal scheduled with DeleteAfter.
+type scheduledItem struct {
+	item   cache
+	remove bool
+	entry  *expiryEntry // Position in the schedule, ordered by due time
+}
+
+// SetAfter adds a value to the cache that becomes visible only after delay.
+// Until then Get and the other reads do not see it, and an existing value
+// for the key stays visible. The TTL starts when the value becomes visible.
+// Setting the key again with SetAfter or DeleteAfter replaces the scheduled value;
+// Delete and Clear cancel it. A delay of 0 or less is the same as Set.
+func (c *Cacher) SetAfter(key, value interface{}, delay, ttl time.Duration) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, ttl, 0)
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.unschedule(key)
+	if delay <= 0 {
+		c.store(key, item)
+		return
+	}
+
+	c.scheduleAt(key, scheduledItem{item: item}, c.now().Add(delay))
+}
+
+// scheduleAt adds a scheduled set or removal for a key that has none.
+func (c *Cacher) scheduleAt(key interface{}, s scheduledItem, at time.Time) {
+	entry := c.newExpiryEntry(key)
+	entry.at = at
+	heap.Push(&c.schedule, entry)
+	s.entry = entry
+	c.scheduled[key] = s
+
+	c.scheduleOnce.Do(func() {
+		go c.runSchedule()
+	})
+	if entry.index == 0 {
+		select {
+		case c.scheduleWake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// releaseScheduled stores the scheduled values and performs the scheduled removals
+// whose time has come.
+func (c *Cacher) releaseScheduled() {
+	now := c.now()
+	for len(c.schedule) > 0 && !c.schedule[0].at.After(now) {
+		key, at := c.schedule[0].key, c.schedule[0].at
+		s := c.scheduled[key]
+		c.unschedule(key)
+
+		if s.remove {
+			c.remove(key)
+			continue
+		}
+		s.item.createdAt, s.item.lastUsedAt = at, at
+		c.store(key, s.item)
+	}
+}
+
+// unschedule cancels the scheduled value for a key, if any.
+// Reports whether there was one.
+func (c *Cacher) unschedule(key interface{}) bool {
+	s, ok := c.scheduled[key]
+	if !ok {
+		return false
+	}
+	heap.Remove(&c.schedule, s.entry.index)
+	delete(c.scheduled, key)
+	releaseExpiryEntry(s.entry)
+	return true
+}
+
+// runSchedule makes scheduled values visible on time,
+// sleeping until the next one is due.
+func (c *Cacher) runSchedule() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-c.scheduleWake:
+		case <-c.ctx.Done():
+			return
+		}
+
+		c.mu.Lock()
+		c.releaseScheduled()
+		next := time.Duration(-1)
+		if len(c.schedule) > 0 {
+			next = max(c.schedule[0].at.Sub(c.now()), time.Millisecond)
+		}
+		c.mu.Unlock()
+
+		timer.Stop()
+		if next > 0 {
+			timer.Reset(next)
+		}
+	}
+}
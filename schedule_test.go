@@ -0,0 +1,85 @@
+package cacher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_SetAfter(t *testing.T) {
+	cache := New(Config{Capacity: 10})
+	defer cache.Close()
+
+	cache.Set("price", 100, time.Minute)
+	cache.SetAfter("price", 120, 30*time.Millisecond, time.Minute)
+	cache.SetAfter("news", "embargoed", 30*time.Millisecond, 0)
+
+	// до истечения задержки виден старый элемент
+	got, err := cache.Get("price")
+	require.NoError(t, err)
+	assert.Equal(t, 100, got)
+	_, err = cache.Get("news")
+	assert.Error(t, err)
+	assert.NotContains(t, cache.GetAll(), "embargoed")
+
+	time.Sleep(40 * time.Millisecond)
+	got, err = cache.Get("price")
+	require.NoError(t, err)
+	assert.Equal(t, 120, got)
+	got, err = cache.Get("news")
+	require.NoError(t, err)
+	assert.Equal(t, "embargoed", got)
+}
+
+func TestCacher_SetAfterTTL(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	// TTL отсчитывается с момента появления
+	cache.SetAfter("k", "v", 30*time.Millisecond, 30*time.Millisecond)
+	time.Sleep(45 * time.Millisecond)
+	_, err := cache.Get("k")
+	assert.NoError(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = cache.Get("k")
+	assert.Error(t, err)
+}
+
+func TestCacher_SetAfterWait(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.SetAfter("k", "v", 20*time.Millisecond, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got, err := cache.Wait(ctx, "k") // будится таймером без чтений
+	require.NoError(t, err)
+	assert.Equal(t, "v", got)
+}
+
+func TestCacher_SetAfterCancel(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.SetAfter("k1", "v1", 20*time.Millisecond, 0)
+	cache.SetAfter("k2", "v2", 20*time.Millisecond, 0)
+	assert.NoError(t, cache.Delete("k1"))
+	cache.Clear()
+
+	time.Sleep(30 * time.Millisecond)
+	_, err := cache.Get("k1")
+	assert.Error(t, err)
+	_, err = cache.Get("k2")
+	assert.Error(t, err)
+
+	// нулевая задержка работает как Set
+	cache.SetAfter("k3", "v3", 0, 0)
+	got, err := cache.Get("k3")
+	require.NoError(t, err)
+	assert.Equal(t, "v3", got)
+}
@@ -0,0 +1,116 @@
+package cacher
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Sizer is implemented by values that report their size in bytes.
+// MemoryUsage uses it instead of estimating the size with reflection.
+type Sizer interface {
+	Size() int
+}
+
+// entryOverhead is the estimated memory taken by an item besides its key and value:
+// the item itself, its list element and its map slot.
+const entryOverhead = int64(unsafe.Sizeof(cache{}) + unsafe.Sizeof(element{}) + 2*unsafe.Sizeof(interface{}(nil)))
+
+// MemoryUsage returns the estimated number of bytes held by the items in the cache:
+// a fixed overhead per item plus the sizes of its key and value. Values implementing
+// Sizer report their own size, other values are measured with reflection,
+// and compressed or encoded values by their stored bytes.
+// It walks all items, so call it periodically rather than on every request.
+func (c *Cacher) MemoryUsage() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var total int64
+	for key, item := range c.cache {
+		total += entryOverhead + sizeOf(key) + sizeOf(item.value) + int64(len(item.history))*int64(unsafe.Sizeof(item.lastUsedAt))
+		for _, prev := range item.previous {
+			total += int64(unsafe.Sizeof(prev)) + sizeOf(prev.value)
+		}
+	}
+	return total
+}
+
+// sizeOf estimates the bytes taken by a value, including the memory it references.
+func sizeOf(value interface{}) int64 {
+	if value == nil {
+		return 0
+	}
+	if s, ok := value.(Sizer); ok {
+		return int64(s.Size())
+	}
+	v := reflect.ValueOf(value)
+	return int64(v.Type().Size()) + referencedSize(v, make(map[uintptr]struct{}))
+}
+
+// referencedSize returns the bytes referenced by v beyond its own size.
+// Pointers already seen are counted once, so shared and cyclic data is not counted twice.
+func referencedSize(v reflect.Value, seen map[uintptr]struct{}) int64 {
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+
+	case reflect.Pointer:
+		if v.IsNil() || visited(v.Pointer(), seen) {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + referencedSize(elem, seen)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + referencedSize(elem, seen)
+
+	case reflect.Slice:
+		if v.IsNil() || visited(v.Pointer(), seen) {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen)
+		}
+		return size
+
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen)
+		}
+		return size
+
+	case reflect.Map:
+		if v.IsNil() || visited(v.Pointer(), seen) {
+			return 0
+		}
+		t := v.Type()
+		size := int64(v.Len()) * int64(t.Key().Size()+t.Elem().Size())
+		iter := v.MapRange()
+		for iter.Next() {
+			size += referencedSize(iter.Key(), seen) + referencedSize(iter.Value(), seen)
+		}
+		return size
+
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += referencedSize(v.Field(i), seen)
+		}
+		return size
+	}
+	return 0
+}
+
+// visited reports whether a pointer was already counted, marking it as seen.
+func visited(ptr uintptr, seen map[uintptr]struct{}) bool {
+	if _, ok := seen[ptr]; ok {
+		return true
+	}
+	seen[ptr] = struct{}{}
+	return false
+}
@@ -0,0 +1,49 @@
+package cacher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedSize struct{}
+
+func (fixedSize) Size() int { return 1000 }
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestSizeOf(t *testing.T) {
+	assert.Equal(t, int64(0), sizeOf(nil))
+	assert.Equal(t, int64(1000), sizeOf(fixedSize{}))
+	assert.Equal(t, int64(16+5), sizeOf("hello"))
+	assert.Equal(t, int64(24+10), sizeOf(make([]byte, 3, 10)))
+	assert.Equal(t, int64(8), sizeOf(42))
+
+	// циклические ссылки считаются один раз
+	n := &node{Name: "a"}
+	n.Next = n
+	assert.Equal(t, int64(8+24+1), sizeOf(n))
+
+	assert.Greater(t, sizeOf(map[string][]int{"k": {1, 2, 3}}), int64(24))
+}
+
+func TestCacher_MemoryUsage(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	assert.Equal(t, int64(0), cache.MemoryUsage())
+
+	cache.Set("k1", make([]byte, 1<<20), 0)
+	usage := cache.MemoryUsage()
+	assert.Greater(t, usage, int64(1<<20))
+	assert.Less(t, usage, int64(1<<20+1024))
+
+	cache.Set("k2", fixedSize{}, 0)
+	assert.Equal(t, usage+entryOverhead+sizeOf("k2")+1000, cache.MemoryUsage())
+
+	cache.Clear()
+	assert.Equal(t, int64(0), cache.MemoryUsage())
+}
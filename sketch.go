@@ -0,0 +1,77 @@
+package cacher
+
+import "math/bits"
+
+// sketchDepth is the number of rows of the count-min sketch.
+const sketchDepth = 4
+
+// sketch is a count-min sketch estimating how often keys were accessed.
+// Counters are halved after a sample of accesses, so old popularity fades out.
+type sketch struct {
+	rows    [sketchDepth][]uint32
+	mask    uint64
+	added   int // Increments since the last halving
+	resetAt int // Increments before halving
+}
+
+// newSketch creates a sketch sized for about n distinct keys.
+func newSketch(n int) *sketch {
+	width := uint64(1) << bits.Len64(uint64(max(n, 1)-1))
+	s := &sketch{mask: width - 1, resetAt: 10 * n}
+	for i := range s.rows {
+		s.rows[i] = make([]uint32, width)
+	}
+	return s
+}
+
+// increment records an access to a key.
+func (s *sketch) increment(key interface{}) {
+	h1, h2 := hashKey(key)
+	for i := range s.rows {
+		j := (h1 + uint64(i)*h2) & s.mask
+		if s.rows[i][j] < ^uint32(0) {
+			s.rows[i][j]++
+		}
+	}
+
+	s.added++
+	if s.added >= s.resetAt {
+		s.halve()
+	}
+}
+
+// estimate returns the approximate number of accesses to a key.
+// It may overestimate, but never underestimates (apart from halving).
+func (s *sketch) estimate(key interface{}) uint32 {
+	h1, h2 := hashKey(key)
+	count := ^uint32(0)
+	for i := range s.rows {
+		count = min(count, s.rows[i][(h1+uint64(i)*h2)&s.mask])
+	}
+	return count
+}
+
+// halve divides all counters by two.
+func (s *sketch) halve() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] /= 2
+		}
+	}
+	s.added /= 2
+}
+
+// EstimateFrequency returns the approximate number of times a key was read,
+// whether or not it is currently cached. Counts fade out over time.
+// Returns 0 unless Config.FrequencySketchKeys is set.
+func (c *Cacher) EstimateFrequency(key interface{}) uint32 {
+	key = c.key(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.sketch == nil {
+		return 0
+	}
+	return c.sketch.estimate(key)
+}
@@ -0,0 +1,38 @@
+package cacher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacher_EstimateFrequency(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1, FrequencySketchKeys: 100})
+	defer cache.Close()
+
+	cache.Set("hot", "value", 0)
+	for i := 0; i < 5; i++ {
+		_, _ = cache.Get("hot")
+		_, _ = cache.Get("missing") // учитываются и промахи
+	}
+	assert.Equal(t, uint32(5), cache.EstimateFrequency("hot"))
+	assert.Equal(t, uint32(5), cache.EstimateFrequency("missing"))
+	assert.Equal(t, uint32(0), cache.EstimateFrequency("never"))
+
+	disabled := New(Config{ClearingInterval: -1})
+	defer disabled.Close()
+	_, _ = disabled.Get("key")
+	assert.Equal(t, uint32(0), disabled.EstimateFrequency("key"))
+}
+
+func TestSketch_Halving(t *testing.T) {
+	s := newSketch(4)
+	for i := 0; i < 39; i++ {
+		s.increment("key")
+	}
+	assert.Equal(t, uint32(39), s.estimate("key"))
+
+	// 40 обращений = 10 * n, счётчики делятся пополам
+	s.increment("key")
+	assert.Equal(t, uint32(20), s.estimate("key"))
+}
@@ -0,0 +1,98 @@
+package cacher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// snapshotStats describes the snapshots taken by the cache. Guarded by Cacher.mu.
+type snapshotStats struct {
+	count    uint64
+	errors   uint64
+	duration time.Duration // Of the last successful snapshot
+	bytes    int64         // Of the last successful snapshot
+}
+
+// WithAutoSnapshot saves the items to path every interval, keeping the last
+// keepN snapshots as path, path.1, ..., path.<keepN-1>, newest first.
+// Each snapshot is written to a temporary file and renamed into place, so path
+// always holds a complete snapshot that WarmFromFile can load.
+// Shutdown takes a final snapshot. See Config.SnapshotInterval.
+func WithAutoSnapshot(interval time.Duration, path string, keepN int) Option {
+	return func(cfg *Config) {
+		cfg.SnapshotInterval = interval
+		cfg.SnapshotPath = path
+		cfg.SnapshotKeep = keepN
+	}
+}
+
+// startSnapshots saves a snapshot every interval until the cache is closed.
+func (c *Cacher) startSnapshots(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.snapshot(); err != nil {
+				c.logger.Warn("cache snapshot failed", "path", c.snapshotPath, "error", err)
+			}
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// snapshot saves the items to the snapshot path and records its duration and size.
+func (c *Cacher) snapshot() error {
+	start := time.Now()
+	size, err := c.saveToFile(c.snapshotPath, c.snapshotKeep)
+	duration := time.Since(start)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.snapshots.errors++
+		return err
+	}
+	c.snapshots.count++
+	c.snapshots.duration = duration
+	c.snapshots.bytes = size
+	c.logger.Debug("cache snapshot saved", "path", c.snapshotPath, "bytes", size, "duration", duration)
+	return nil
+}
+
+// rotateSnapshots shifts the existing snapshots at path to path.1, path.2 and so on,
+// dropping those beyond keep generations, so a new snapshot can be renamed to path.
+// The current snapshot is hard linked to path.1 where possible, so path stays in place
+// until it is replaced. Does nothing if keep is 1 or less.
+func rotateSnapshots(path string, keep int) error {
+	if keep <= 1 {
+		return nil
+	}
+	generation := func(i int) string {
+		if i == 0 {
+			return path
+		}
+		return fmt.Sprintf("%s.%d", path, i)
+	}
+
+	for i := keep - 1; i >= 1; i-- {
+		err := os.Remove(generation(i))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if i > 1 {
+			err = os.Rename(generation(i-1), generation(i))
+		} else if err = os.Link(path, generation(1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			err = os.Rename(path, generation(1))
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
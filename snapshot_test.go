@@ -0,0 +1,74 @@
+package cacher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_AutoSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+	cache := New(Config{}, WithAutoSnapshot(10*time.Millisecond, path, 3))
+	cache.Set("k1", "v1", 0)
+
+	// ждём несколько снимков, чтобы сработала ротация
+	require.Eventually(t, func() bool {
+		return cache.Metrics().Snapshots >= 4
+	}, 2*time.Second, 5*time.Millisecond)
+
+	m := cache.Metrics()
+	assert.Zero(t, m.SnapshotErrors)
+	assert.Positive(t, m.LastSnapshotBytes)
+	assert.Positive(t, m.LastSnapshotDuration)
+
+	cache.Set("k2", "v2", 0)
+	require.NoError(t, cache.Shutdown(t.Context())) // финальный снимок
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		_, err := os.Stat(name)
+		assert.NoError(t, err, name)
+	}
+	_, err := os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	restored := New(Config{})
+	defer restored.Close()
+	require.NoError(t, restored.WarmFromFile(path))
+	got, err := restored.Get("k2")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got)
+}
+
+func TestCacher_SnapshotErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "cache.snap")
+	cache := New(Config{SnapshotPath: path, SnapshotInterval: 10 * time.Millisecond})
+	defer cache.Close()
+
+	require.Eventually(t, func() bool {
+		return cache.Metrics().SnapshotErrors > 0
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Zero(t, cache.Metrics().Snapshots)
+}
+
+func TestRotateSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+	require.NoError(t, rotateSnapshots(path, 2)) // снимков ещё нет
+
+	for i, content := range []string{"a", "b", "c"} {
+		require.NoError(t, rotateSnapshots(path, 2))
+		require.NoError(t, os.WriteFile(path+".tmp", []byte(content), 0o644))
+		require.NoError(t, os.Rename(path+".tmp", path))
+		if i == 0 {
+			continue
+		}
+		prev, err := os.ReadFile(path + ".1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}[i-1], string(prev))
+	}
+	_, err := os.Stat(path + ".2")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,189 @@
+// Package sqlcache caches database/sql query results in a *cacher.Cacher, so read-heavy
+// services can skip repeated queries:
+//
+//	q, err := sqlcache.New(cache, db, sqlcache.Options{TTL: time.Minute})
+//	rows, err := q.Query(ctx, []string{"users"}, "SELECT id, name FROM users WHERE team = ?", team)
+//	...
+//	_, err = q.Exec(ctx, []string{"users"}, "UPDATE users SET name = ? WHERE id = ?", name, id)
+//
+// Results are keyed by the query with whitespace normalized and by the arguments,
+// and tagged with the tables they read. Exec and Invalidate drop the results
+// tagged with the tables a write changes.
+package sqlcache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danRulev/cacher"
+)
+
+// DefaultIndex is the name of the secondary index of table tags if Options.Index is empty.
+const DefaultIndex = "sqlcache.tables"
+
+// Querier runs queries. *sql.DB, *sql.Tx and *sql.Conn implement it.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Options configures a Cache.
+type Options struct {
+	// TTL is how long a result stays cached after the query ran, however often
+	// it is read. 0 means no expiration.
+	TTL time.Duration
+
+	// Index is the name of the secondary index of table tags added to the cacher.
+	// If empty, defaults to DefaultIndex. Caches sharing a cacher need different names.
+	Index string
+}
+
+// Rows is a cached result set. It is shared by all callers and must not be modified.
+type Rows struct {
+	Columns []string
+	Values  [][]interface{} // One slice per row, in column order
+	Tables  []string        // Tables the result was tagged with
+}
+
+// queryKey identifies a cached result. Its type keeps results apart
+// from other keys in a shared cacher.
+type queryKey struct {
+	query string
+	args  string
+}
+
+// Cache runs queries through a cacher.
+type Cache struct {
+	cache *cacher.Cacher
+	db    Querier
+	ttl   time.Duration
+	index string
+}
+
+// New creates a Cache running queries on db and registers its table index on cache.
+// Returns an error if an index with the same name exists.
+func New(cache *cacher.Cacher, db Querier, opts Options) (*Cache, error) {
+	if opts.Index == "" {
+		opts.Index = DefaultIndex
+	}
+	err := cache.AddIndex(opts.Index, func(value interface{}) []string {
+		if rows, ok := value.(*Rows); ok {
+			return rows.Tables
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{cache: cache, db: db, ttl: opts.TTL, index: opts.Index}, nil
+}
+
+// Query returns the cached result of a query, or runs it, scans all rows
+// and caches them tagged with tables. Errors are not cached.
+func (c *Cache) Query(ctx context.Context, tables []string, query string, args ...interface{}) (*Rows, error) {
+	key := newQueryKey(query, args)
+	if value, err := c.cache.Get(key); err == nil {
+		if rows, ok := value.(*Rows); ok {
+			return rows, nil
+		}
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := scan(rows)
+	if err != nil {
+		return nil, err
+	}
+	result.Tables = tables
+	c.cache.SetWithLimits(key, result, 0, c.ttl) // Reads must not keep a stale result alive
+	return result, nil
+}
+
+// Exec runs a statement and, if it succeeds, drops the results tagged with tables.
+// A query running at the same time may still cache an old result until its TTL expires.
+func (c *Cache) Exec(ctx context.Context, tables []string, query string, args ...interface{}) (sql.Result, error) {
+	result, err := c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	c.Invalidate(tables...)
+	return result, nil
+}
+
+// Invalidate drops the results tagged with any of the tables and returns how many were dropped.
+func (c *Cache) Invalidate(tables ...string) int {
+	total := 0
+	for _, table := range tables {
+		n, _ := c.cache.DeleteByIndex(c.index, table)
+		total += n
+	}
+	return total
+}
+
+// scan reads and closes a result set.
+func scan(rows *sql.Rows) (*Rows, error) {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &Rows{Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		// Scanning into *interface{} copies []byte values, so they outlive the rows
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result.Values = append(result.Values, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// newQueryKey builds the key of a query and its arguments.
+func newQueryKey(query string, args []interface{}) queryKey {
+	var b strings.Builder
+	for _, arg := range args {
+		fmt.Fprintf(&b, "%T:%v\x00", arg, arg)
+	}
+	return queryKey{query: normalize(query), args: b.String()}
+}
+
+// normalize collapses whitespace outside quoted strings and identifiers
+// and removes a trailing semicolon, so formatting does not change the key.
+func normalize(query string) string {
+	var b strings.Builder
+	var quote rune
+	space := false
+	for _, r := range strings.TrimSpace(query) {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(strings.TrimSuffix(b.String(), ";"))
+}
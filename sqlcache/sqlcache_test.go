@@ -0,0 +1,127 @@
+package sqlcache
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/danRulev/cacher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// countingDB counts the queries reaching the database.
+type countingDB struct {
+	*sql.DB
+	queries int
+}
+
+func (db *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db.queries++
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+func newTestCache(t *testing.T, ttl time.Duration) (*Cache, *countingDB) {
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	_, err = sqlDB.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, team TEXT);
+		INSERT INTO users VALUES (1, 'alice', 'red'), (2, 'bob', 'red'), (3, 'carol', 'blue')`)
+	require.NoError(t, err)
+
+	cache := cacher.New(cacher.Config{})
+	t.Cleanup(func() {
+		cache.Close()
+		sqlDB.Close()
+	})
+
+	db := &countingDB{DB: sqlDB}
+	q, err := New(cache, db, Options{TTL: ttl})
+	require.NoError(t, err)
+	return q, db
+}
+
+func TestCache_Query(t *testing.T) {
+	q, db := newTestCache(t, time.Minute)
+	ctx := context.Background()
+	users := []string{"users"}
+
+	rows, err := q.Query(ctx, users, "SELECT id, name FROM users WHERE team = ? ORDER BY id", "red")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, rows.Columns)
+	assert.Equal(t, [][]interface{}{{int64(1), "alice"}, {int64(2), "bob"}}, rows.Values)
+
+	// Отличия только в пробелах дают тот же ключ
+	_, err = q.Query(ctx, users, "SELECT id, name\n\tFROM users  WHERE team = ? ORDER BY id;", "red")
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.queries)
+
+	// Другие аргументы — другой ключ
+	rows, err = q.Query(ctx, users, "SELECT id, name FROM users WHERE team = ? ORDER BY id", "blue")
+	require.NoError(t, err)
+	assert.Len(t, rows.Values, 1)
+	assert.Equal(t, 2, db.queries)
+
+	// Ошибки не кэшируются
+	_, err = q.Query(ctx, users, "SELECT missing FROM users")
+	assert.Error(t, err)
+	_, err = q.Query(ctx, users, "SELECT missing FROM users")
+	assert.Error(t, err)
+	assert.Equal(t, 4, db.queries)
+}
+
+func TestCache_TTLIsAbsolute(t *testing.T) {
+	q, db := newTestCache(t, 150*time.Millisecond)
+	ctx := context.Background()
+
+	// Частые попадания не продлевают срок жизни результата
+	for i := 0; i < 4; i++ {
+		_, err := q.Query(ctx, []string{"users"}, "SELECT name FROM users")
+		require.NoError(t, err)
+		time.Sleep(60 * time.Millisecond)
+	}
+	assert.Equal(t, 2, db.queries)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	q, db := newTestCache(t, time.Minute)
+	ctx := context.Background()
+
+	_, err := q.Query(ctx, []string{"users"}, "SELECT name FROM users WHERE id = ?", 1)
+	require.NoError(t, err)
+	_, err = q.Query(ctx, []string{"teams"}, "SELECT 1")
+	require.NoError(t, err)
+
+	_, err = q.Exec(ctx, []string{"users"}, "UPDATE users SET name = ? WHERE id = ?", "alicia", 1)
+	require.NoError(t, err)
+
+	rows, err := q.Query(ctx, []string{"users"}, "SELECT name FROM users WHERE id = ?", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alicia", rows.Values[0][0])
+	assert.Equal(t, 3, db.queries)
+
+	// Результаты других таблиц остаются в кэше
+	_, err = q.Query(ctx, []string{"teams"}, "SELECT 1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, db.queries)
+	assert.Equal(t, 1, q.Invalidate("teams", "orders"))
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "SELECT a FROM t WHERE b = 'x  y'", normalize("  SELECT  a\nFROM t WHERE b = 'x  y' ; "))
+	assert.Equal(t, `SELECT "a  b"`, normalize("SELECT   \"a  b\""))
+}
+
+func TestNew_DuplicateIndex(t *testing.T) {
+	cache := cacher.New(cacher.Config{})
+	defer cache.Close()
+
+	_, err := New(cache, nil, Options{})
+	require.NoError(t, err)
+	_, err = New(cache, nil, Options{})
+	assert.Error(t, err)
+	_, err = New(cache, nil, Options{Index: "other"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,74 @@
+package cacher
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotTokenBucket is returned by TakeToken when the cached value is not a TokenBucket.
+var ErrNotTokenBucket = errors.New("value is not a token bucket")
+
+// Token buckets can be stored with GobCodec.
+func init() {
+	gob.Register(TokenBucket{})
+}
+
+// TokenBucket is a value holding tokens that refill at a constant rate up to a capacity.
+// Store it with Set and take tokens with TakeToken:
+//
+//	cache.Set(clientID, cacher.NewTokenBucket(10, 2), time.Hour)
+//	ok, err := cache.TakeToken(clientID, 1)
+type TokenBucket struct {
+	Capacity float64   // Maximum number of tokens
+	Rate     float64   // Tokens added per second
+	Tokens   float64   // Tokens available at Updated
+	Updated  time.Time // Last refill, zero until the first take
+}
+
+// NewTokenBucket returns a full bucket.
+func NewTokenBucket(capacity, rate float64) TokenBucket {
+	return TokenBucket{Capacity: capacity, Rate: rate, Tokens: capacity}
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *TokenBucket) refill(now time.Time) {
+	if !b.Updated.IsZero() && now.After(b.Updated) {
+		b.Tokens = min(b.Capacity, b.Tokens+now.Sub(b.Updated).Seconds()*b.Rate)
+	}
+	b.Updated = now
+}
+
+// TakeToken refills the token bucket of a key for the time elapsed since the last take
+// and takes n tokens from it, if it has that many. Reports whether the tokens were taken.
+// Takes count as accesses, so buckets in use do not expire.
+// Returns an error if the key is not found or the TTL has expired,
+// and ErrNotTokenBucket if the value is not a TokenBucket.
+func (c *Cacher) TakeToken(key interface{}, n int) (bool, error) {
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.cache[key]
+	if !ok || item.negative {
+		return false, fmt.Errorf("cache not found for key: %v", key)
+	}
+	if err := c.checkExpiration(item); err != nil {
+		c.expire(key, item)
+		return false, err
+	}
+	bucket, ok := c.load(item).(TokenBucket)
+	if !ok {
+		return false, ErrNotTokenBucket
+	}
+
+	bucket.refill(c.now())
+	taken := bucket.Tokens >= float64(n)
+	if taken {
+		bucket.Tokens -= float64(n)
+	}
+	c.update(key, c.replace(key, item, bucket))
+	return taken, nil
+}
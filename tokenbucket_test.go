@@ -0,0 +1,72 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_TakeToken(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("client", NewTokenBucket(3, 20), time.Minute)
+
+	for i := 0; i < 3; i++ {
+		ok, err := cache.TakeToken("client", 1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := cache.TakeToken("client", 1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// За 100мс при 20 токенах в секунду набирается 2 токена
+	time.Sleep(100 * time.Millisecond)
+	ok, err = cache.TakeToken("client", 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Больше ёмкости взять нельзя, даже после долгого ожидания
+	time.Sleep(300 * time.Millisecond)
+	ok, err = cache.TakeToken("client", 4)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	value, err := cache.Get("client")
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, value.(TokenBucket).Tokens)
+}
+
+func TestCacher_TakeTokenErrors(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	_, err := cache.TakeToken("missing", 1)
+	assert.Error(t, err)
+
+	cache.Set("name", "alice", 0)
+	_, err = cache.TakeToken("name", 1)
+	assert.ErrorIs(t, err, ErrNotTokenBucket)
+}
+
+func TestCacher_TakeTokenKeepsAlive(t *testing.T) {
+	cache := New(Config{Codec: GobCodec{}})
+	defer cache.Close()
+
+	cache.Set("client", NewTokenBucket(100, 0), 100*time.Millisecond)
+
+	// Каждое взятие продлевает TTL
+	for i := 0; i < 4; i++ {
+		time.Sleep(50 * time.Millisecond)
+		ok, err := cache.TakeToken("client", 1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	value, err := cache.Get("client")
+	require.NoError(t, err)
+	assert.Equal(t, 96.0, value.(TokenBucket).Tokens)
+}
@@ -0,0 +1,79 @@
+package cacher
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeleteAfter removes a key after delay, replacing any value scheduled with SetAfter.
+// Reads see the current value until then. A delay of 0 or less is the same as Delete.
+// Returns an error if the key is not found.
+func (c *Cacher) DeleteAfter(key interface{}, delay time.Duration) error {
+	if delay <= 0 {
+		return c.Delete(key)
+	}
+	key = c.key(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, scheduled := c.scheduled[key]
+	if _, ok := c.cache[key]; !ok && !scheduled {
+		if _, ok := c.spilled[key]; !ok {
+			return fmt.Errorf("cache not found for key: %v", key)
+		}
+	}
+
+	c.unschedule(key)
+	c.scheduleAt(key, scheduledItem{remove: true}, c.now().Add(delay))
+	return nil
+}
+
+// remove deletes a key from the cache and the overflow store and leaves a tombstone.
+// Reports whether the key was present.
+func (c *Cacher) remove(key interface{}) bool {
+	c.bury(key)
+	if c.ghosts != nil {
+		c.ghosts.remove(key)
+	}
+	if _, ok := c.spilled[key]; ok {
+		c.dropSpilled(key)
+		return true
+	}
+	if _, ok := c.cache[key]; !ok {
+		return false
+	}
+	c.removeKey(key)
+	c.checkWatermarks()
+	return true
+}
+
+// bury leaves a tombstone for a deleted key if TombstoneTTL is set.
+func (c *Cacher) bury(key interface{}) {
+	if c.tombstoneTTL > 0 {
+		c.tombstones[key] = c.now().Add(c.tombstoneTTL)
+	}
+}
+
+// buried reports whether a key has a tombstone, removing it if it has expired.
+func (c *Cacher) buried(key interface{}) bool {
+	until, ok := c.tombstones[key]
+	if !ok {
+		return false
+	}
+	if c.now().Before(until) {
+		return true
+	}
+	delete(c.tombstones, key)
+	return false
+}
+
+// removeTombstones removes expired tombstones.
+func (c *Cacher) removeTombstones() {
+	now := c.now()
+	for key, until := range c.tombstones {
+		if !now.Before(until) {
+			delete(c.tombstones, key)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_DeleteAfter(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("k", "v", 0)
+	require.NoError(t, cache.DeleteAfter("k", 20*time.Millisecond))
+
+	_, err := cache.Get("k") // ещё доступен
+	assert.NoError(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = cache.Get("k")
+	assert.Error(t, err)
+
+	assert.Error(t, cache.DeleteAfter("missing", time.Millisecond))
+}
+
+func TestCacher_DeleteAfterReplacesSetAfter(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.SetAfter("k", "v", 10*time.Millisecond, 0)
+	require.NoError(t, cache.DeleteAfter("k", 20*time.Millisecond))
+
+	time.Sleep(30 * time.Millisecond)
+	_, err := cache.Get("k")
+	assert.Error(t, err)
+}
+
+func TestCacher_Tombstone(t *testing.T) {
+	cache := New(Config{TombstoneTTL: 30 * time.Millisecond})
+	defer cache.Close()
+
+	cache.Set("k", "v1", 0)
+	require.NoError(t, cache.Delete("k"))
+
+	// запоздавший писатель со старым значением игнорируется
+	cache.Set("k", "stale", 0)
+	_, err := cache.Get("k")
+	assert.Error(t, err)
+
+	// надгробие ставится и для отсутствующего ключа
+	assert.Error(t, cache.Delete("absent"))
+	cache.Set("absent", "stale", 0)
+	_, err = cache.Get("absent")
+	assert.Error(t, err)
+
+	time.Sleep(40 * time.Millisecond)
+	cache.Set("k", "v2", 0)
+	got, err := cache.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got)
+}
+
+func TestCacher_TombstoneDisabled(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("k", "v1", 0)
+	require.NoError(t, cache.Delete("k"))
+	cache.Set("k", "v2", 0)
+
+	got, err := cache.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got)
+}
@@ -0,0 +1,97 @@
+package cacher
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Txn is a set of operations executed atomically by Tx.
+// Writes are buffered and applied only if the transaction succeeds.
+type Txn struct {
+	c      *Cacher
+	writes map[interface{}]txWrite
+	order  []interface{} // Written keys in order of first write
+}
+
+// txWrite is a buffered write of a transaction.
+type txWrite struct {
+	item    cache
+	deleted bool
+}
+
+// Tx runs fn with the cache locked, so its operations are atomic to other goroutines.
+// Writes made through tx are applied when fn returns nil and discarded when it returns
+// an error or panics. fn must not call methods of the cache itself, only those of tx.
+// Keys already written to the overflow store are not restored inside fn.
+func (c *Cacher) Tx(fn func(tx *Txn) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx := &Txn{c: c, writes: make(map[interface{}]txWrite)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	tx.commit()
+	return nil
+}
+
+// Get returns the value of a key, including writes made earlier in the transaction.
+func (tx *Txn) Get(key interface{}) (interface{}, error) {
+	return tx.get(tx.c.key(key))
+}
+
+// get returns the value of a key mapped by the key function.
+func (tx *Txn) get(key interface{}) (interface{}, error) {
+	if w, ok := tx.writes[key]; ok {
+		if w.deleted {
+			return nil, fmt.Errorf("cache not found for key: %v", key)
+		}
+		if w.item.negative {
+			return nil, ErrNegativeCached
+		}
+		return tx.c.load(w.item), nil
+	}
+	return tx.c.get(key)
+}
+
+// Set adds a value to the cache with a TTL when the transaction commits.
+func (tx *Txn) Set(key, value interface{}, ttl time.Duration) {
+	tx.write(tx.c.key(key), txWrite{item: tx.c.newItem(value, ttl, 0)})
+}
+
+// Delete removes a key when the transaction commits.
+// Returns an error if the key is not found.
+func (tx *Txn) Delete(key interface{}) error {
+	key = tx.c.key(key)
+	if _, err := tx.get(key); err != nil && !errors.Is(err, ErrNegativeCached) {
+		return err
+	}
+	tx.write(key, txWrite{deleted: true})
+	return nil
+}
+
+// write buffers a write.
+func (tx *Txn) write(key interface{}, w txWrite) {
+	if _, ok := tx.writes[key]; !ok {
+		tx.order = append(tx.order, key)
+	}
+	tx.writes[key] = w
+}
+
+// commit applies the buffered writes.
+func (tx *Txn) commit() {
+	c := tx.c
+	for _, key := range tx.order {
+		w := tx.writes[key]
+		if !w.deleted {
+			c.store(key, w.item)
+			continue
+		}
+		c.dropSpilled(key)
+		if _, ok := c.cache[key]; ok {
+			c.removeKey(key)
+		}
+	}
+	c.checkWatermarks()
+}
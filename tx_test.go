@@ -0,0 +1,78 @@
+package cacher
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Tx(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("index", []string{"a"}, 0)
+	cache.Set("data:a", "A", 0)
+
+	err := cache.Tx(func(tx *Txn) error {
+		index, err := tx.Get("index")
+		if err != nil {
+			return err
+		}
+		tx.Set("data:b", "B", time.Minute)
+		tx.Set("index", append(index.([]string), "b"), 0)
+		require.NoError(t, tx.Delete("data:a"))
+
+		// Внутри транзакции видны её собственные записи
+		got, err := tx.Get("data:b")
+		require.NoError(t, err)
+		assert.Equal(t, "B", got)
+		_, err = tx.Get("data:a")
+		assert.Error(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+
+	index, err := cache.Get("index")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, index)
+	_, err = cache.Get("data:a")
+	assert.Error(t, err)
+	got, err := cache.Get("data:b")
+	require.NoError(t, err)
+	assert.Equal(t, "B", got)
+}
+
+func TestCacher_TxRollback(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("key1", "value1", 0)
+	errAbort := errors.New("abort")
+	err := cache.Tx(func(tx *Txn) error {
+		tx.Set("key1", "changed", 0)
+		tx.Set("key2", "value2", 0)
+		assert.Error(t, tx.Delete("missing"))
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	got, err := cache.Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", got)
+	_, err = cache.Get("key2")
+	assert.Error(t, err)
+
+	// Паника тоже откатывает транзакцию и освобождает блокировку
+	assert.Panics(t, func() {
+		_ = cache.Tx(func(tx *Txn) error {
+			tx.Set("key1", "changed", 0)
+			panic("boom")
+		})
+	})
+	got, err = cache.Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", got)
+}
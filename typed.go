@@ -0,0 +1,87 @@
+package cacher
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TypeError is returned by the typed getters when a value does not have the requested type.
+type TypeError struct {
+	Key  interface{}
+	Want string // Requested type
+	Got  string // Type of the cached value
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("cache value for key %v is %s, not %s", e.Key, e.Got, e.Want)
+}
+
+// GetString is like Get, but returns a string value.
+// Returns a *TypeError if the value is not a string.
+func (c *Cacher) GetString(key interface{}) (string, error) {
+	value, err := c.Get(key)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", &TypeError{Key: key, Want: "string", Got: fmt.Sprintf("%T", value)}
+	}
+	return s, nil
+}
+
+// GetInt64 is like Get, but returns an integer value as int64. Values of type int,
+// int32 and whole float64 values, as decoded by JSON codecs, are converted.
+// Returns a *TypeError if the value is not an integer.
+func (c *Cacher) GetInt64(key interface{}) (int64, error) {
+	value, err := c.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := toInt64(value)
+	if !ok {
+		return 0, &TypeError{Key: key, Want: "int64", Got: fmt.Sprintf("%T", value)}
+	}
+	return n, nil
+}
+
+// GetBytes is like Get, but returns a []byte value.
+// Returns a *TypeError if the value is not a []byte.
+func (c *Cacher) GetBytes(key interface{}) ([]byte, error) {
+	value, err := c.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil, &TypeError{Key: key, Want: "[]byte", Got: fmt.Sprintf("%T", value)}
+	}
+	return b, nil
+}
+
+// GetJSON is like Get, but unmarshals the value into dst with encoding/json.
+// []byte and string values are unmarshaled as JSON documents; other values are
+// first marshaled to JSON, so e.g. a map decoded by a codec can fill a struct.
+// Returns the unmarshaling error if the value does not fit dst.
+func (c *Cacher) GetJSON(key interface{}, dst interface{}) error {
+	value, err := c.Get(key)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		if data, err = json.Marshal(value); err != nil {
+			return fmt.Errorf("cache value for key %v: %w", key, err)
+		}
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("cache value for key %v: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cacher
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_TypedGetters(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	cache.Set("name", "alice", 0)
+	cache.Set("age", 30, 0)
+	cache.Set("raw", []byte{1, 2}, 0)
+
+	s, err := cache.GetString("name")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", s)
+
+	n, err := cache.GetInt64("age")
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), n)
+
+	b, err := cache.GetBytes("raw")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, b)
+
+	// Несовпадение типа — *TypeError
+	_, err = cache.GetString("age")
+	var typeErr *TypeError
+	require.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "age", typeErr.Key)
+	assert.Equal(t, "int", typeErr.Got)
+	assert.Equal(t, "string", typeErr.Want)
+
+	_, err = cache.GetInt64("name")
+	assert.ErrorAs(t, err, &typeErr)
+	_, err = cache.GetBytes("name")
+	assert.ErrorAs(t, err, &typeErr)
+
+	// Отсутствующий ключ — обычная ошибка
+	_, err = cache.GetString("missing")
+	assert.Error(t, err)
+	assert.NotErrorAs(t, err, &typeErr)
+}
+
+func TestCacher_GetJSON(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	cache.Set("doc", []byte(`{"name":"alice","age":30}`), 0)
+	cache.Set("map", map[string]interface{}{"name": "bob", "age": 25.0}, 0)
+
+	var u user
+	require.NoError(t, cache.GetJSON("doc", &u))
+	assert.Equal(t, user{Name: "alice", Age: 30}, u)
+
+	// Значения других типов сначала кодируются в JSON
+	require.NoError(t, cache.GetJSON("map", &u))
+	assert.Equal(t, user{Name: "bob", Age: 25}, u)
+
+	cache.Set("bad", "not json", 0)
+	err := cache.GetJSON("bad", &u)
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+}
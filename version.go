@@ -0,0 +1,61 @@
+package cacher
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrVersionMismatch is returned by SetIfVersion when the key was changed since it was read.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// GetWithVersion is like Get, but also returns the version of the value.
+// Every write of a key gives it a new, higher version.
+func (c *Cacher) GetWithVersion(key interface{}) (value interface{}, version uint64, err error) {
+	key = c.key(key)
+	c.unspill(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, err = c.get(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, c.cache[key].version, nil
+}
+
+// SetIfVersion sets a value only if the key still has the given version,
+// so a read-modify-write does not overwrite a concurrent change.
+// Version 0 means the key must not be cached. Returns ErrVersionMismatch otherwise,
+// and ErrValueTooLarge or ErrKeyTooLong if the item exceeds the size limits.
+func (c *Cacher) SetIfVersion(key, value interface{}, ttl time.Duration, version uint64) error {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, ttl, 0)
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkLimits(key, item); err != nil {
+		return err
+	}
+	var current uint64
+	if old, ok := c.cache[key]; ok && c.checkExpiration(old) == nil {
+		current = old.version
+	}
+	if current != version {
+		return ErrVersionMismatch
+	}
+
+	c.dropSpilled(key)
+	c.set(key, item)
+	return nil
+}
+
+// nextVersion returns a new item version.
+func (c *Cacher) nextVersion() uint64 {
+	c.version++
+	return c.version
+}
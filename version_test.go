@@ -0,0 +1,64 @@
+package cacher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_SetIfVersion(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	// Версия 0 — только если ключа нет
+	require.NoError(t, cache.SetIfVersion("counter", 1, 0, 0))
+	assert.ErrorIs(t, cache.SetIfVersion("counter", 1, 0, 0), ErrVersionMismatch)
+
+	value, version, err := cache.GetWithVersion("counter")
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	cache.Set("counter", 5, 0)
+	assert.ErrorIs(t, cache.SetIfVersion("counter", 2, 0, version), ErrVersionMismatch)
+
+	_, newVersion, err := cache.GetWithVersion("counter")
+	require.NoError(t, err)
+	assert.Greater(t, newVersion, version)
+	require.NoError(t, cache.SetIfVersion("counter", 6, time.Minute, newVersion))
+
+	info, err := cache.Entry("counter")
+	require.NoError(t, err)
+	assert.Equal(t, 6, info.Value)
+	assert.Greater(t, info.Version, newVersion)
+}
+
+func TestCacher_SetIfVersionConcurrent(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+	cache.Set("counter", 0, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				for {
+					value, version, err := cache.GetWithVersion("counter")
+					require.NoError(t, err)
+					if cache.SetIfVersion("counter", value.(int)+1, 0, version) == nil {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := cache.Get("counter")
+	require.NoError(t, err)
+	assert.Equal(t, 100, got)
+}
@@ -0,0 +1,67 @@
+package cacher
+
+import (
+	"context"
+	"errors"
+)
+
+// errClosed is returned by Wait when the cache is closed.
+var errClosed = errors.New("cache closed")
+
+// waiter is a set of goroutines waiting for a key to be set.
+type waiter struct {
+	ch chan struct{} // Closed when the key is set
+	n  int           // Number of waiting goroutines
+}
+
+// Wait returns the value of a key, blocking until it is set by another goroutine
+// if it is not cached yet. Returns the context error if ctx is done first,
+// and ErrNegativeCached if the key is set with SetNegative.
+func (c *Cacher) Wait(ctx context.Context, key interface{}) (interface{}, error) {
+	key = c.key(key)
+	for {
+		c.unspill(key)
+		c.mu.Lock()
+		value, err := c.get(key)
+		if err == nil || errors.Is(err, ErrNegativeCached) {
+			c.mu.Unlock()
+			return value, err
+		}
+		w, ok := c.waiters[key]
+		if !ok {
+			w = &waiter{ch: make(chan struct{})}
+			c.waiters[key] = w
+		}
+		w.n++
+		c.mu.Unlock()
+
+		select {
+		case <-w.ch:
+		case <-ctx.Done():
+			c.leave(key, w)
+			return nil, ctx.Err()
+		case <-c.ctx.Done():
+			c.leave(key, w)
+			return nil, errClosed
+		}
+	}
+}
+
+// leave removes a goroutine that stopped waiting for a key.
+func (c *Cacher) leave(key interface{}, w *waiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w.n--
+	if w.n == 0 && c.waiters[key] == w {
+		delete(c.waiters, key)
+	}
+}
+
+// wake releases the goroutines waiting for a key.
+func (c *Cacher) wake(key interface{}) {
+	if w, ok := c.waiters[key]; ok {
+		close(w.ch)
+		delete(c.waiters, key)
+	}
+}
@@ -0,0 +1,54 @@
+package cacher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Wait(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cache.Set("result", "done", 0)
+	}()
+
+	got, err := cache.Wait(context.Background(), "result")
+	require.NoError(t, err)
+	assert.Equal(t, "done", got)
+
+	// Уже закэшированное значение возвращается сразу
+	got, err = cache.Wait(context.Background(), "result")
+	require.NoError(t, err)
+	assert.Equal(t, "done", got)
+}
+
+func TestCacher_WaitTimeout(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := cache.Wait(ctx, "never")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	cache.mu.Lock()
+	assert.Empty(t, cache.waiters)
+	cache.mu.Unlock()
+}
+
+func TestCacher_WaitClose(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cache.Close()
+	}()
+	_, err := cache.Wait(context.Background(), "never")
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package cacher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+)
+
+// WAL fsync policies
+const (
+	WALSyncPeriodic = iota // fsync every WALSyncInterval
+	WALSyncAlways          // fsync after every record, slowest but loses nothing on power loss
+	WALSyncNever           // Leave flushing to the operating system
+)
+
+var defaultWALSyncInterval = time.Second
+
+// walOp is the kind of a logged operation.
+type walOp int
+
+const (
+	walSet walOp = iota
+	walDelete
+	walClear
+)
+
+// walRecord is a logged operation. Set records hold the item as stored,
+// so replay does not encode or compress values again.
+type walRecord struct {
+	Op         walOp
+	Key        interface{}
+	Value      interface{}
+	TTL        time.Duration
+	Deadline   time.Time
+	SetAt      time.Time
+	UsedAt     time.Time
+	Priority   int
+	Pinned     bool
+	Negative   bool
+	Compressed int
+	Encoded    bool
+}
+
+// wal appends operations to a log file. Written with Cacher.mu held.
+type wal struct {
+	file *os.File
+	sync int
+}
+
+// walHeaderSize is the size of the length and CRC-32 preceding each record.
+const walHeaderSize = 8
+
+// openWAL opens the log at path for appending, creating it if needed.
+func openWAL(path string, sync int) (*wal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open wal: %w", err)
+	}
+	return &wal{file: file, sync: sync}, nil
+}
+
+// append writes a record as a single write, so a crash leaves at most
+// the last record incomplete. Each record is gob-encoded on its own, so
+// logs written by several processes can be replayed as one.
+func (w *wal) append(record walRecord) error {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, walHeaderSize))
+	if err := gob.NewEncoder(&buf).Encode(&record); err != nil {
+		return fmt.Errorf("encode wal record: %w", err)
+	}
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(data)-walHeaderSize))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(data[walHeaderSize:]))
+
+	if _, err := w.file.Write(data); err != nil {
+		return err
+	}
+	if w.sync == WALSyncAlways {
+		return w.file.Sync()
+	}
+	return nil
+}
+
+// log appends a record, logging failures. Does nothing if the WAL is disabled.
+// Must be called with c.mu held.
+func (c *Cacher) log(record walRecord) {
+	if c.wal == nil {
+		return
+	}
+	if err := c.wal.append(record); err != nil {
+		c.logger.Warn("cache wal write failed", "op", record.Op, "key", record.Key, "error", err)
+	}
+}
+
+// logSet records a stored item.
+func (c *Cacher) logSet(key interface{}, item cache) {
+	if c.wal == nil {
+		return
+	}
+	c.log(walRecord{
+		Op:         walSet,
+		Key:        key,
+		Value:      item.value,
+		TTL:        item.ttl,
+		Deadline:   item.deadline,
+		SetAt:      item.createdAt,
+		UsedAt:     item.lastUsedAt,
+		Priority:   item.priority,
+		Pinned:     item.pinned,
+		Negative:   item.negative,
+		Compressed: item.compressed,
+		Encoded:    item.encoded,
+	})
+}
+
+// runWAL syncs the log every interval if the policy is WALSyncPeriodic,
+// and closes it when the cache is closed.
+func (c *Cacher) runWAL(w *wal, interval time.Duration) {
+	var tick <-chan time.Time
+	if w.sync == WALSyncPeriodic {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			if err := w.file.Sync(); err != nil {
+				c.logger.Warn("cache wal sync failed", "error", err)
+			}
+		case <-c.ctx.Done():
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if err := w.file.Close(); err != nil {
+				c.logger.Warn("cache wal close failed", "error", err)
+			}
+			c.wal = nil
+			return
+		}
+	}
+}
+
+// ReplayWAL applies the operations logged at path to the cache, so its state can be
+// reconstructed after a crash. Call it at startup, before serving requests.
+// Replayed operations are not logged again, and items that expired meanwhile are skipped.
+// A torn or corrupted record, e.g. the last one written before a crash, ends the replay
+// without an error. The log grows with every write, so start a new one after a snapshot
+// to bound the replay time.
+func (c *Cacher) ReplayWAL(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open wal: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("open wal: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := c.wal
+	c.wal = nil
+	defer func() { c.wal = active }()
+
+	header := make([]byte, walHeaderSize)
+	for offset := int64(0); ; {
+		if _, err := io.ReadFull(file, header); err != nil {
+			if !errors.Is(err, io.EOF) {
+				c.logger.Warn("cache wal ends with a torn record", "path", path, "offset", offset)
+			}
+			return nil
+		}
+		// A corrupted length must not allocate more than the file holds
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		if size > info.Size()-offset-walHeaderSize {
+			c.logger.Warn("cache wal ends with a torn record", "path", path, "offset", offset)
+			return nil
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(file, data); err != nil ||
+			crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+			c.logger.Warn("cache wal ends with a torn record", "path", path, "offset", offset)
+			return nil
+		}
+
+		var record walRecord
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+			return fmt.Errorf("decode wal record at offset %d: %w", offset, err)
+		}
+		c.replay(record)
+		offset += int64(walHeaderSize + len(data))
+	}
+}
+
+// replay applies a logged operation. Must be called with c.mu held.
+func (c *Cacher) replay(record walRecord) {
+	switch record.Op {
+	case walSet:
+		item := cache{
+			value:      record.Value,
+			ttl:        record.TTL,
+			deadline:   record.Deadline,
+			negative:   record.Negative,
+			counter:    1,
+			createdAt:  record.SetAt,
+			lastUsedAt: record.UsedAt,
+			priority:   record.Priority,
+			pinned:     record.Pinned,
+			compressed: record.Compressed,
+			encoded:    record.Encoded,
+		}
+		// The logged state replaces the item, including its pin
+		if _, ok := c.cache[record.Key]; ok {
+			c.removeKey(record.Key)
+		}
+		if c.checkExpiration(item) == nil {
+			c.set(record.Key, item)
+		}
+	case walDelete:
+		if _, ok := c.cache[record.Key]; ok {
+			c.removeKey(record.Key)
+		}
+	case walClear:
+		c.clear()
+	}
+}
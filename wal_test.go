@@ -0,0 +1,121 @@
+package cacher
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_ReplayWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+	cache := New(Config{Capacity: 3, WALPath: path, WALSync: WALSyncAlways})
+	defer cache.Close()
+
+	cache.Set("k1", "v1", 0)
+	cache.Set("k2", []byte("v2"), time.Hour)
+	cache.SetWithPriority("k3", 3, 0, 5)
+	require.NoError(t, cache.Update("k1", "v1 updated"))
+	require.NoError(t, cache.Pin("k3"))
+	require.NoError(t, cache.Delete("k2"))
+	cache.Set("k4", "v4", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	cache.Set("k5", "v5", 0) // истёкший k4 удаляется, чтобы освободить место
+
+	// восстановление в новом кэше, как после падения процесса
+	restored := New(Config{Capacity: 3})
+	defer restored.Close()
+	require.NoError(t, restored.ReplayWAL(path))
+
+	got, err := restored.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1 updated", got)
+	_, err = restored.Get("k2")
+	assert.Error(t, err)
+	_, err = restored.Get("k4")
+	assert.Error(t, err)
+	got, err = restored.Get("k5")
+	require.NoError(t, err)
+	assert.Equal(t, "v5", got)
+
+	entry, err := restored.Entry("k3")
+	require.NoError(t, err)
+	assert.True(t, entry.Pinned)
+	assert.Equal(t, 5, entry.Priority)
+
+	cache.Set("k6", "v6", 0) // вытесняет k1: k3 закреплён
+	restored = New(Config{Capacity: 3})
+	defer restored.Close()
+	require.NoError(t, restored.ReplayWAL(path))
+	want, err := cache.Keys()
+	require.NoError(t, err)
+	keys, err := restored.Keys()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, want, keys)
+
+	cache.Clear()
+	cache.Set("k7", "v7", 0)
+	restored = New(Config{})
+	defer restored.Close()
+	require.NoError(t, restored.ReplayWAL(path))
+	keys, err = restored.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"k7"}, keys)
+}
+
+func TestCacher_ReplayWALTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+	cache := New(Config{WALPath: path, WALSync: WALSyncNever})
+	cache.Set("k1", "v1", 0)
+	cache.Set("k2", "v2", 0)
+	cache.Close()
+
+	// обрезаем последнюю запись, как при падении во время записи
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-3))
+
+	restored := New(Config{WALPath: path})
+	defer restored.Close()
+	require.NoError(t, restored.ReplayWAL(path))
+
+	_, err = restored.Get("k1")
+	assert.NoError(t, err)
+	_, err = restored.Get("k2")
+	assert.Error(t, err)
+
+	assert.Error(t, restored.ReplayWAL(filepath.Join(t.TempDir(), "missing.wal")))
+}
+
+func TestCacher_ReplayWALHugeLength(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+	cache := New(Config{WALPath: path, WALSync: WALSyncNever})
+	cache.Set("k1", "v1", 0)
+	cache.Close()
+
+	// Испорченная длина записи не должна приводить к огромному выделению памяти
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	restored := New(Config{})
+	defer restored.Close()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	require.NoError(t, restored.ReplayWAL(path))
+	runtime.ReadMemStats(&after)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+	_, err = restored.Get("k1")
+	assert.NoError(t, err)
+}
+
+func TestConfig_ValidateWAL(t *testing.T) {
+	assert.ErrorContains(t, Config{WALSync: 7}.Validate(), "invalid wal sync policy")
+	assert.NoError(t, Config{WALPath: "cache.wal", WALSync: WALSyncNever}.Validate())
+}
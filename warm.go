@@ -0,0 +1,211 @@
+package cacher
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Loader returns the items to preload into the cache.
+type Loader func(ctx context.Context) (map[interface{}]interface{}, error)
+
+// ProgressFunc reports how many of the total loaded items have been stored.
+type ProgressFunc func(done, total int)
+
+// fileRecord is the on-disk representation of an item written by SaveToFile.
+type fileRecord struct {
+	Key        interface{}
+	Value      interface{}
+	ExpiresAt  time.Time     // Zero if the item never expires
+	Encoded    bool          // Value holds the bytes encoded with the cache codec
+	TTL        time.Duration // Sliding TTL
+	Deadline   time.Time     // Absolute expiration time, zero if none
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	Priority   int
+}
+
+// Warm preloads the items returned by loader with the given TTL.
+// Keys already in the cache are kept, so values set while warming are not overwritten.
+// Values over the size limits and keys with a tombstone are skipped, as by Set,
+// but the doorkeeper is bypassed.
+// Stops early and returns the context error if ctx is cancelled.
+func (c *Cacher) Warm(ctx context.Context, loader Loader, ttl time.Duration) error {
+	return c.warm(ctx, loader, ttl, nil)
+}
+
+// WarmAsync runs Warm in a separate goroutine, calling progress (if not nil) after
+// every stored item. The returned channel receives the result and is then closed.
+func (c *Cacher) WarmAsync(ctx context.Context, loader Loader, ttl time.Duration, progress ProgressFunc) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- c.warm(ctx, loader, ttl, progress)
+		close(done)
+	}()
+	return done
+}
+
+// warm loads the items and stores those not already cached.
+func (c *Cacher) warm(ctx context.Context, loader Loader, ttl time.Duration, progress ProgressFunc) error {
+	items, err := loader(ctx)
+	if err != nil {
+		return fmt.Errorf("warm: %w", err)
+	}
+
+	done := 0
+	for key, value := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		c.add(c.key(key), c.newItem(value, ttl, 0))
+		done++
+		if progress != nil {
+			progress(done, len(items))
+		}
+	}
+	c.logger.Info("cache warmed", "items", done)
+	return nil
+}
+
+// SaveToFile writes all unexpired items, except negative ones, to a gob-encoded file that WarmFromFile can load.
+// The file is replaced atomically. Keys and values of custom types must be registered
+// with gob.Register, except values of a cache with a Codec, which are saved as encoded by it.
+func (c *Cacher) SaveToFile(path string) error {
+	_, err := c.saveToFile(path, 1)
+	return err
+}
+
+// saveToFile writes a snapshot to path, keeping up to keep generations
+// (see rotateSnapshots), and returns its size in bytes.
+func (c *Cacher) saveToFile(path string, keep int) (int64, error) {
+	c.mu.RLock()
+	records := make([]fileRecord, 0, len(c.cache))
+	for key, item := range c.cache {
+		if item.negative || c.checkExpiration(item) != nil {
+			continue
+		}
+		record := fileRecord{
+			Key:        key,
+			ExpiresAt:  c.expiresAt(item),
+			TTL:        item.ttl,
+			Deadline:   item.deadline,
+			CreatedAt:  item.createdAt,
+			LastUsedAt: item.lastUsedAt,
+			Priority:   item.priority,
+		}
+		if item.encoded {
+			record.Value, record.Encoded = item.unpack(), true
+		} else {
+			record.Value = c.load(item)
+		}
+		records = append(records, record)
+	}
+	c.mu.RUnlock()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(records); err != nil {
+		return 0, fmt.Errorf("encode cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+	if err := rotateSnapshots(path, keep); err != nil {
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+	return int64(buf.Len()), os.Rename(tmp.Name(), path)
+}
+
+// WarmFromFile preloads the items saved by SaveToFile with the TTL, deadline,
+// last access time and priority they had when saved.
+// Expired items and keys already in the cache are skipped, and so are the items
+// Warm skips.
+func (c *Cacher) WarmFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var records []fileRecord
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&records); err != nil {
+		return fmt.Errorf("decode %s: %w", path, err)
+	}
+
+	loaded := 0
+	for _, record := range records {
+		if !record.ExpiresAt.IsZero() && !record.ExpiresAt.After(time.Now()) {
+			continue
+		}
+		if record.Encoded {
+			if c.codec == nil {
+				return fmt.Errorf("decode %s: values were saved with a codec, but the cache has none", path)
+			}
+			if record.Value, err = c.codec.Unmarshal(record.Value.([]byte)); err != nil {
+				return fmt.Errorf("decode %s: %w", path, err)
+			}
+		}
+		if c.add(record.Key, c.recordItem(record)) {
+			loaded++
+		}
+	}
+	c.logger.Info("cache warmed from file", "path", path, "items", loaded)
+	return nil
+}
+
+// recordItem rebuilds an item saved by SaveToFile. Files written before the TTL
+// and deadline were saved give the remaining lifetime as a sliding TTL.
+func (c *Cacher) recordItem(record fileRecord) cache {
+	if record.LastUsedAt.IsZero() {
+		var ttl time.Duration
+		if !record.ExpiresAt.IsZero() {
+			ttl = time.Until(record.ExpiresAt)
+		}
+		return c.newItem(record.Value, ttl, 0)
+	}
+	return c.pack(cache{
+		ttl:        record.TTL,
+		deadline:   record.Deadline,
+		counter:    1,
+		createdAt:  record.CreatedAt,
+		lastUsedAt: record.LastUsedAt,
+		priority:   record.Priority,
+	}, record.Value)
+}
+
+// add stores an item unless the key is already cached and not expired.
+// Like the setters, it skips values over the size limits and keys with a tombstone,
+// but it bypasses the doorkeeper, which would keep out every key seen for the first time.
+// Reports whether the item was stored.
+func (c *Cacher) add(key interface{}, item cache) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.cache[key]; ok && c.checkExpiration(old) == nil {
+		return false
+	}
+	if err := c.checkLimits(key, item); err != nil {
+		c.logger.Debug("cache value rejected", "key", key, "error", err)
+		return false
+	}
+	if len(c.tombstones) > 0 && c.buried(key) {
+		return false
+	}
+	c.dropSpilled(key)
+	c.set(key, item)
+	return true
+}
@@ -0,0 +1,181 @@
+package cacher
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Warm(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("k1", "fresh", 0)
+	loader := func(ctx context.Context) (map[interface{}]interface{}, error) {
+		return map[interface{}]interface{}{"k1": "stale", "k2": "v2"}, nil
+	}
+	require.NoError(t, cache.Warm(context.Background(), loader, time.Minute))
+
+	// Уже закэшированное значение не перезаписывается
+	got, err := cache.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", got)
+
+	got, err = cache.Get("k2")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got)
+	ttl, _ := cache.GetTTL("k2")
+	assert.Equal(t, time.Minute, ttl)
+}
+
+func TestCacher_WarmChecks(t *testing.T) {
+	cache := New(Config{MaxValueBytes: 100, TombstoneTTL: time.Minute, DoorkeeperKeys: 100})
+	defer cache.Close()
+
+	cache.Set("deleted", "v", 0)
+	cache.Set("deleted", "v", 0) // второй Set проходит doorkeeper
+	require.NoError(t, cache.Delete("deleted"))
+
+	loader := func(ctx context.Context) (map[interface{}]interface{}, error) {
+		return map[interface{}]interface{}{
+			"deleted": "old",
+			"big":     strings.Repeat("x", 1000),
+			"new":     "v",
+		}, nil
+	}
+	require.NoError(t, cache.Warm(context.Background(), loader, time.Minute))
+
+	// Удалённый ключ не возвращается, слишком большое значение не сохраняется
+	_, err := cache.Get("deleted")
+	assert.Error(t, err)
+	_, err = cache.Get("big")
+	assert.Error(t, err)
+
+	// Doorkeeper при прогреве не применяется
+	got, err := cache.Get("new")
+	require.NoError(t, err)
+	assert.Equal(t, "v", got)
+}
+
+func TestCacher_WarmErrors(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	errLoad := errors.New("db down")
+	err := cache.Warm(context.Background(), func(ctx context.Context) (map[interface{}]interface{}, error) {
+		return nil, errLoad
+	}, 0)
+	assert.ErrorIs(t, err, errLoad)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = cache.Warm(ctx, func(ctx context.Context) (map[interface{}]interface{}, error) {
+		return map[interface{}]interface{}{"k1": "v1"}, nil
+	}, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = cache.Get("k1")
+	assert.Error(t, err)
+}
+
+func TestCacher_WarmAsync(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	loader := func(ctx context.Context) (map[interface{}]interface{}, error) {
+		return map[interface{}]interface{}{1: "a", 2: "b", 3: "c"}, nil
+	}
+	var calls [][2]int
+	err := <-cache.WarmAsync(context.Background(), loader, 0, func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int{{1, 3}, {2, 3}, {3, 3}}, calls)
+	assert.Len(t, cache.GetAll(), 3)
+}
+
+func TestCacher_SaveAndWarmFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+
+	src := New(Config{ClearingInterval: -1})
+	defer src.Close()
+	src.Set("k1", "v1", 0)
+	src.Set("k2", []byte("v2"), time.Minute)
+	src.Set("k3", "v3", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, src.SaveToFile(path))
+
+	dst := New(Config{ClearingInterval: -1})
+	defer dst.Close()
+	require.NoError(t, dst.WarmFromFile(path))
+
+	got, err := dst.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+
+	got, err = dst.Get("k2")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), got)
+	ttl, _ := dst.GetTTL("k2")
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+	// Истёкший элемент не сохраняется
+	_, err = dst.Get("k3")
+	assert.Error(t, err)
+
+	assert.Error(t, dst.WarmFromFile(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestCacher_WarmFromFileKeepsExpiration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	deadline := time.Now().Add(time.Hour)
+
+	src := New(Config{ClearingInterval: -1})
+	defer src.Close()
+	src.SetWithPriority("sliding", "v1", time.Minute, 3)
+	src.SetWithDeadline("deadline", "v2", deadline)
+	require.NoError(t, src.SaveToFile(path))
+
+	dst := New(Config{ClearingInterval: -1})
+	defer dst.Close()
+	require.NoError(t, dst.WarmFromFile(path))
+
+	// Скользящий TTL и абсолютный срок восстанавливаются как были, а не как остаток
+	entry, err := dst.Entry("sliding")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, entry.TTL)
+	assert.Equal(t, 3, entry.Priority)
+	assert.True(t, entry.Deadline.IsZero())
+
+	entry, err = dst.Entry("deadline")
+	require.NoError(t, err)
+	assert.Zero(t, entry.TTL)
+	assert.True(t, deadline.Equal(entry.Deadline))
+}
+
+func TestCacher_SaveToFileWithCodec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+
+	src := New(Config{ClearingInterval: -1, Codec: GobCodec{}})
+	defer src.Close()
+	src.Set("k1", []int{1, 2, 3}, 0)
+	require.NoError(t, src.SaveToFile(path))
+
+	dst := New(Config{ClearingInterval: -1, Codec: GobCodec{}})
+	defer dst.Close()
+	require.NoError(t, dst.WarmFromFile(path))
+
+	got, err := dst.Get("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, got)
+
+	// Без кодека закодированные значения прочитать нельзя
+	plain := New(Config{ClearingInterval: -1})
+	defer plain.Close()
+	assert.Error(t, plain.WarmFromFile(path))
+}
@@ -0,0 +1,51 @@
+package cacher
+
+// WatermarkFunc is called when the cache occupancy crosses a watermark.
+type WatermarkFunc func(items, capacity int)
+
+// checkWatermarks fires the watermark callbacks when occupancy crosses a threshold
+// and, if configured, evicts items down to the low watermark.
+// Callbacks run outside the lock, so they may use the cache.
+func (c *Cacher) checkWatermarks() {
+	if c.capacity <= 0 || c.highWatermark <= 0 {
+		return
+	}
+
+	if !c.aboveHigh && c.occupancy() >= c.highWatermark {
+		c.aboveHigh = true
+		c.logger.Info("cache reached high watermark", "items", len(c.cache), "capacity", c.capacity)
+		c.notifyWatermark(c.onHighWatermark)
+
+		if c.shedToLow {
+			for c.occupancy() > c.lowWatermark {
+				key, ok := c.victim()
+				if !ok {
+					break
+				}
+				c.evictKey(key)
+			}
+		}
+	}
+
+	if c.aboveHigh && c.occupancy() <= c.lowWatermark {
+		c.aboveHigh = false
+		c.logger.Info("cache dropped to low watermark", "items", len(c.cache), "capacity", c.capacity)
+		c.notifyWatermark(c.onLowWatermark)
+	}
+}
+
+// occupancy returns the fraction of capacity in use.
+func (c *Cacher) occupancy() float64 {
+	return float64(len(c.cache)) / float64(c.capacity)
+}
+
+// notifyWatermark queues a watermark callback with the current occupancy.
+func (c *Cacher) notifyWatermark(fn WatermarkFunc) {
+	if fn == nil {
+		return
+	}
+	items, capacity := len(c.cache), c.capacity
+	c.dispatcher.dispatch(func() {
+		fn(items, capacity)
+	})
+}
@@ -0,0 +1,65 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_Watermarks(t *testing.T) {
+	high := make(chan int, 1)
+	low := make(chan int, 1)
+	cfg := Config{
+		Capacity:        10,
+		HighWatermark:   0.8,
+		LowWatermark:    0.5,
+		OnHighWatermark: func(items, capacity int) { high <- items },
+		OnLowWatermark:  func(items, capacity int) { low <- items },
+	}
+	cache := New(cfg)
+	defer cache.Close()
+
+	for i := 0; i < 8; i++ {
+		cache.Set(i, i, 5*time.Second)
+	}
+	select {
+	case items := <-high:
+		assert.Equal(t, 8, items)
+	case <-time.After(time.Second):
+		t.Fatal("high watermark callback not called")
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, cache.Delete(i))
+	}
+	select {
+	case items := <-low:
+		assert.Equal(t, 5, items)
+	case <-time.After(time.Second):
+		t.Fatal("low watermark callback not called")
+	}
+}
+
+func TestCacher_EvictToLowWatermark(t *testing.T) {
+	cfg := Config{
+		Capacity:            10,
+		EvictionPolicy:      LRU,
+		HighWatermark:       0.8,
+		LowWatermark:        0.5,
+		EvictToLowWatermark: true,
+	}
+	cache := New(cfg)
+	defer cache.Close()
+
+	for i := 0; i < 8; i++ {
+		cache.Set(i, i, 5*time.Second)
+	}
+
+	assert.Len(t, cache.GetAll(), 5)
+	_, err := cache.Get(0) // самые старые вытеснены
+	assert.Error(t, err)
+	_, err = cache.Get(7)
+	assert.NoError(t, err)
+}
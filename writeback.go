@@ -0,0 +1,143 @@
+package cacher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	defaultWriteBehindInterval = time.Second
+	defaultWriteRetries        = 3
+)
+
+// ErrNoStore is returned by Write and WriteDelete when Config.Store is not set.
+var ErrNoStore = errors.New("cache store is not configured")
+
+// pendingWrite is a write-behind change waiting to be flushed to the store.
+type pendingWrite struct {
+	value    interface{}
+	ttl      time.Duration
+	delete   bool
+	attempts int // Failed flushes so far
+}
+
+// writeBehind queues changes for the store and flushes them in batches.
+type writeBehind struct {
+	mu      sync.Mutex
+	pending map[interface{}]pendingWrite // Latest change per key
+	flushMu sync.Mutex                   // Serializes flushes to keep changes in order
+}
+
+// Write sets a value in the cache and in Config.Store, like Set, but returns the store error.
+// In write-through mode (the default) the store is written first and the cache
+// is only updated if that succeeded, so the store error is returned.
+// In write-behind mode the cache is updated right away and the change is queued
+// to be flushed to the store every WriteBehindInterval; only the latest change
+// per key is written. Returns ErrNoStore if no store is configured.
+func (c *Cacher) Write(key, value interface{}, ttl time.Duration) error {
+	if c.origin == nil {
+		return ErrNoStore
+	}
+	ttl = c.resolveTTL(ttl) // The store must not see DefaultExpiration
+	if c.writeBehind != nil {
+		c.SetWithPriority(key, value, ttl, 0)
+		c.queueWrite(c.key(key), pendingWrite{value: value, ttl: ttl})
+		return nil
+	}
+
+	if err := c.origin.Set(c.key(key), value, ttl); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	c.SetWithPriority(key, value, ttl, 0)
+	return nil
+}
+
+// WriteDelete removes a key from the cache and from Config.Store, in the same
+// write-through or write-behind mode as Write. A key missing from the cache is
+// still deleted from the store. Returns ErrNoStore if no store is configured.
+func (c *Cacher) WriteDelete(key interface{}) error {
+	if c.origin == nil {
+		return ErrNoStore
+	}
+	if c.writeBehind != nil {
+		_ = c.Delete(key)
+		c.queueWrite(c.key(key), pendingWrite{delete: true})
+		return nil
+	}
+
+	if err := c.origin.Delete(c.key(key)); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	_ = c.Delete(key)
+	return nil
+}
+
+// Flush writes the queued write-behind changes to the store now.
+// Changes that fail are kept for a retry on the next flush, up to WriteRetries
+// attempts. Returns the joined errors of the failed changes.
+func (c *Cacher) Flush() error {
+	if c.writeBehind == nil {
+		return nil
+	}
+	w := c.writeBehind
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = make(map[interface{}]pendingWrite)
+	w.mu.Unlock()
+
+	var errs []error
+	for key, change := range batch {
+		var err error
+		if change.delete {
+			err = c.origin.Delete(key)
+		} else {
+			err = c.origin.Set(key, change.value, change.ttl)
+		}
+		if err == nil {
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("store %v: %w", key, err))
+		change.attempts++
+		if change.attempts >= c.writeRetries {
+			c.logger.Error("cache write-behind dropped change", "key", key, "attempts", change.attempts, "error", err)
+			continue
+		}
+		c.logger.Warn("cache write-behind failed", "key", key, "attempts", change.attempts, "error", err)
+
+		w.mu.Lock()
+		if _, newer := w.pending[key]; !newer {
+			w.pending[key] = change
+		}
+		w.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// queueWrite queues a write-behind change, replacing an older one for the same key.
+func (c *Cacher) queueWrite(key interface{}, change pendingWrite) {
+	c.writeBehind.mu.Lock()
+	defer c.writeBehind.mu.Unlock()
+
+	c.writeBehind.pending[key] = change
+}
+
+// startWriteBehind flushes queued changes every interval until the cache is closed.
+func (c *Cacher) startWriteBehind(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = c.Flush()
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
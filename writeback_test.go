@@ -0,0 +1,217 @@
+package cacher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory Backend that can be made to fail.
+type memStore struct {
+	mu     sync.Mutex
+	data   map[interface{}]interface{}
+	ttls   map[interface{}]time.Duration
+	writes int
+	fail   error
+}
+
+func newMemStore() *memStore {
+	return &memStore{data: make(map[interface{}]interface{}), ttls: make(map[interface{}]time.Duration)}
+}
+
+func (s *memStore) Get(key interface{}) (interface{}, time.Duration, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.data[key]
+	return value, 0, ok, nil
+}
+
+func (s *memStore) Set(key, value interface{}, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail != nil {
+		return s.fail
+	}
+	s.writes++
+	s.data[key] = value
+	s.ttls[key] = ttl
+	return nil
+}
+
+func (s *memStore) Delete(key interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail != nil {
+		return s.fail
+	}
+	delete(s.data, key)
+	return nil
+}
+
+func (s *memStore) setFail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = err
+}
+
+func (s *memStore) value(key interface{}) (interface{}, bool) {
+	value, _, ok, _ := s.Get(key)
+	return value, ok
+}
+
+func TestCacher_WriteThrough(t *testing.T) {
+	store := newMemStore()
+	cache := New(Config{Store: store})
+	defer cache.Close()
+
+	require.NoError(t, cache.Write("k", "v", time.Minute))
+	got, ok := store.value("k")
+	assert.True(t, ok)
+	assert.Equal(t, "v", got)
+	cached, err := cache.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", cached)
+
+	// ошибка хранилища не меняет кэш
+	store.setFail(errors.New("db down"))
+	assert.Error(t, cache.Write("k", "v2", time.Minute))
+	cached, _ = cache.Get("k")
+	assert.Equal(t, "v", cached)
+	assert.Error(t, cache.WriteDelete("k"))
+
+	store.setFail(nil)
+	require.NoError(t, cache.WriteDelete("k"))
+	_, ok = store.value("k")
+	assert.False(t, ok)
+	_, err = cache.Get("k")
+	assert.Error(t, err)
+}
+
+func TestCacher_SetWritesThrough(t *testing.T) {
+	store := newMemStore()
+	cache := New(Config{Store: store})
+	defer cache.Close()
+
+	cache.Set("k", "v", time.Minute)
+	got, ok := store.value("k")
+	assert.True(t, ok)
+	assert.Equal(t, "v", got)
+
+	// при ошибке хранилища Set не меняет кэш
+	store.setFail(errors.New("db down"))
+	cache.Set("k", "v2", time.Minute)
+	cached, err := cache.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", cached)
+
+	// в режиме write-behind Set ставит запись в очередь
+	behind := New(Config{Store: store, WriteBehind: true, WriteBehindInterval: time.Hour})
+	defer behind.Close()
+	store.setFail(nil)
+	behind.Set("queued", "v", 0)
+	require.NoError(t, behind.Flush())
+	got, ok = store.value("queued")
+	assert.True(t, ok)
+	assert.Equal(t, "v", got)
+}
+
+func TestCacher_WriteDefaultExpiration(t *testing.T) {
+	store := newMemStore()
+	cache := New(Config{Store: store, DefaultTTL: time.Hour})
+	defer cache.Close()
+
+	// Хранилище получает DefaultTTL, а не DefaultExpiration
+	cache.Set("k", "v", DefaultExpiration)
+	require.NoError(t, cache.Write("w", "v", DefaultExpiration))
+
+	behind := New(Config{Store: store, DefaultTTL: time.Minute, WriteBehind: true, WriteBehindInterval: time.Hour})
+	defer behind.Close()
+	behind.Set("b", "v", DefaultExpiration)
+	require.NoError(t, behind.Flush())
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	assert.Equal(t, time.Hour, store.ttls["k"])
+	assert.Equal(t, time.Hour, store.ttls["w"])
+	assert.Equal(t, time.Minute, store.ttls["b"])
+}
+
+func TestCacher_WriteNoStore(t *testing.T) {
+	cache := New(Config{})
+	defer cache.Close()
+
+	assert.ErrorIs(t, cache.Write("k", "v", 0), ErrNoStore)
+	assert.ErrorIs(t, cache.WriteDelete("k"), ErrNoStore)
+	assert.NoError(t, cache.Flush())
+}
+
+func TestCacher_WriteBehind(t *testing.T) {
+	store := newMemStore()
+	cache := New(Config{Store: store, WriteBehind: true, WriteBehindInterval: 20 * time.Millisecond})
+	defer cache.Close()
+
+	require.NoError(t, cache.Write("k", "v1", 0))
+	require.NoError(t, cache.Write("k", "v2", 0))
+	require.NoError(t, cache.Write("gone", "v", 0))
+	require.NoError(t, cache.WriteDelete("gone"))
+
+	// кэш обновлён сразу, хранилище — позже
+	cached, err := cache.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", cached)
+	_, ok := store.value("k")
+	assert.False(t, ok)
+
+	assert.Eventually(t, func() bool {
+		got, ok := store.value("k")
+		return ok && got == "v2"
+	}, time.Second, 10*time.Millisecond)
+	_, ok = store.value("gone")
+	assert.False(t, ok)
+
+	store.mu.Lock()
+	assert.Equal(t, 1, store.writes) // записано только последнее значение
+	store.mu.Unlock()
+}
+
+func TestCacher_WriteBehindRetry(t *testing.T) {
+	store := newMemStore()
+	cache := New(Config{Store: store, WriteBehind: true, WriteBehindInterval: time.Hour, WriteRetries: 2})
+	defer cache.Close()
+
+	store.setFail(errors.New("db down"))
+	require.NoError(t, cache.Write("k1", "v1", 0))
+	assert.Error(t, cache.Flush())
+
+	store.setFail(nil)
+	require.NoError(t, cache.Flush()) // повторная попытка успешна
+	got, ok := store.value("k1")
+	assert.True(t, ok)
+	assert.Equal(t, "v1", got)
+
+	store.setFail(errors.New("db down"))
+	require.NoError(t, cache.Write("k2", "v2", 0))
+	assert.Error(t, cache.Flush())
+	assert.Error(t, cache.Flush()) // попытки исчерпаны, изменение отброшено
+	store.setFail(nil)
+	require.NoError(t, cache.Flush())
+	_, ok = store.value("k2")
+	assert.False(t, ok)
+}
+
+func TestCacher_WriteBehindShutdown(t *testing.T) {
+	store := newMemStore()
+	cache := New(Config{Store: store, WriteBehind: true, WriteBehindInterval: time.Hour})
+
+	require.NoError(t, cache.Write("k", "v", 0))
+	require.NoError(t, cache.Shutdown(context.Background()))
+
+	got, ok := store.value("k")
+	assert.True(t, ok)
+	assert.Equal(t, "v", got)
+}
@@ -0,0 +1,59 @@
+package cacher
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+var defaultRefreshBeta = 1.0
+
+// SetWithRecompute adds a value to the cache with a TTL, recording how long it took
+// to compute. GetWithRefresh uses it to spread refreshes of expiring items.
+func (c *Cacher) SetWithRecompute(key, value interface{}, ttl, recompute time.Duration) {
+	if c.latency != nil {
+		defer c.latency.set.since(time.Now())
+	}
+	key = c.key(key)
+	item := c.newItem(value, ttl, 0)
+	item.recompute = recompute
+
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.store(key, item)
+}
+
+// GetWithRefresh is like Get, but also reports whether the caller should recompute
+// the value ahead of its expiration. The probability rises as expiration approaches
+// and with the recompute time passed to SetWithRecompute, so concurrent readers
+// refresh at different moments instead of all at once when the item expires
+// (probabilistic early expiration, XFetch).
+func (c *Cacher) GetWithRefresh(key interface{}) (value interface{}, refresh bool, err error) {
+	key = c.key(key)
+	c.unspill(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.cache[key]
+	value, err = c.get(key)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, ok && c.shouldRefresh(item), nil
+}
+
+// shouldRefresh reports whether an item should be recomputed early:
+// true if now - recompute*beta*ln(rand) reaches its expiration time.
+func (c *Cacher) shouldRefresh(item cache) bool {
+	if item.recompute <= 0 {
+		return false
+	}
+	at := c.expiresAt(item)
+	if at.IsZero() {
+		return false
+	}
+	gap := float64(item.recompute) * c.refreshBeta * -math.Log(1-rand.Float64())
+	return gap >= float64(at.Sub(c.now()))
+}
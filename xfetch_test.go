@@ -0,0 +1,56 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher_GetWithRefresh(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	cache.Set("plain", "v", time.Second)
+	cache.SetWithRecompute("forever", "v", 0, time.Hour)
+	cache.SetWithRecompute("cheap", "v", time.Hour, time.Microsecond)
+	cache.SetWithRecompute("costly", "v", time.Second, 1000*time.Hour)
+
+	for i := 0; i < 100; i++ {
+		for key, want := range map[string]bool{"plain": false, "forever": false, "cheap": false, "costly": true} {
+			value, refresh, err := cache.GetWithRefresh(key)
+			require.NoError(t, err)
+			assert.Equal(t, "v", value)
+			assert.Equal(t, want, refresh, key)
+		}
+	}
+
+	_, _, err := cache.GetWithRefresh("missing")
+	assert.Error(t, err)
+}
+
+func TestMemoize_EarlyRefresh(t *testing.T) {
+	cache := New(Config{ClearingInterval: -1})
+	defer cache.Close()
+
+	calls := 0
+	slow := Memoize(cache, time.Hour, func(n int) (int, error) {
+		calls++
+		return n, nil
+	})
+	_, err := slow(1)
+	require.NoError(t, err)
+
+	// Пересчёт «дорогой», поэтому значение обновляется заранее
+	require.NoError(t, cache.SetTTL(1, time.Millisecond))
+	cache.mu.Lock()
+	item := cache.cache[1]
+	item.recompute = 1000 * time.Hour
+	cache.cache[1] = item
+	cache.mu.Unlock()
+
+	_, err = slow(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}